@@ -12,10 +12,11 @@ import (
 	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/database"
 	"github.com/anzhy11/go-e-commerce/internal/events"
-	"github.com/anzhy11/go-e-commerce/internal/interfaces"
 	"github.com/anzhy11/go-e-commerce/internal/logger"
 	"github.com/anzhy11/go-e-commerce/internal/providers"
 	"github.com/anzhy11/go-e-commerce/internal/server"
+	importService "github.com/anzhy11/go-e-commerce/internal/services/imports"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -63,21 +64,41 @@ func main() {
 	}()
 	gin.SetMode(cfg.Server.GinMode)
 
-	var up interfaces.Upload
-	if cfg.Upload.Provider == "s3" {
-		up = providers.NewS3UploadProvider(cfg)
+	up, err := providers.NewUploadProvider(cfg, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create upload provider")
+	}
+
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+
+	eventPub, err := events.NewPublisher(relayCtx, cfg)
+	if err != nil {
+		log.Error().Err(err).Msg("Failed to create event publisher, events stay in the outbox")
 	} else {
-		up = providers.NewLocalUploadProvider(cfg.Upload.Path)
+		defer func() {
+			if err := eventPub.Close(); err != nil {
+				log.Error().Err(err).Msg("Failed to close event publisher")
+			}
+		}()
+
+		relay := events.NewRelay(db, eventPub, &cfg.Events, log)
+		go relay.Run(relayCtx)
 	}
 
-	ctx := context.Background()
-	eventPub, err := events.NewEventPublisher(ctx, &cfg.AWS)
+	tokens, err := utils.NewTokenManager(&cfg.JWT, cfg.Server.GinMode, log)
 	if err != nil {
-		log.Error().Err(err).Msg("Failed to create event publisher")
+		log.Fatal().Err(err).Msg("Failed to load JWT keys")
 	}
 
-	srv := server.New(cfg, db, log, eventPub, up)
-	router := srv.SetupRoutes()
+	imports := importService.NewWorker(db, log)
+	go imports.Run()
+
+	srv := server.New(cfg, db, log, up, tokens, imports)
+	router, err := srv.SetupRoutes()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up routes")
+	}
 
 	httpServer := &http.Server{
 		Addr:         fmt.Sprintf(":%s", cfg.Server.Port),
@@ -106,5 +127,11 @@ func main() {
 		log.Error().Err(err).Msg("Server forced to shutdown")
 	}
 
+	if err := imports.Shutdown(ctx); err != nil {
+		log.Error().Err(err).Msg("Product imports did not stop in time")
+	}
+
+	stopRelay()
+
 	log.Info().Msg("Server exited properly")
 }
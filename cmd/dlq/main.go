@@ -0,0 +1,149 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/logger"
+)
+
+const usage = `Usage:
+  dlq list [-limit n] [-wait d]
+  dlq replay (-id event_id | -all) [-limit n] [-wait d]
+
+list prints dead-lettered messages and leaves them in the queue.
+replay moves matching messages back to the event queue with their retry count reset.
+`
+
+// dlq inspects and replays messages the notifier moved to the dead-letter queue.
+func main() {
+	if len(os.Args) < 2 {
+		exitWithUsage()
+	}
+
+	command := os.Args[1]
+	flags := flag.NewFlagSet(command, flag.ExitOnError)
+	limit := flags.Int("limit", 100, "stop after this many messages")
+	wait := flags.Duration("wait", 5*time.Second, "stop when no message arrives for this long")
+	eventID := flags.String("id", "", "replay the message with this event id")
+	all := flags.Bool("all", false, "replay every message")
+	_ = flags.Parse(os.Args[2:])
+
+	if command != "list" && command != "replay" {
+		exitWithUsage()
+	}
+	if command == "replay" && *eventID == "" && !*all {
+		exitWithUsage()
+	}
+
+	log := logger.New()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	subscriber, err := events.NewSubscriber(ctx, cfg)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create subscriber")
+	}
+	defer func() {
+		_ = subscriber.Close()
+	}()
+
+	// handle reports whether the message is done with and can be removed
+	// from the dead-letter queue.
+	var handle func(msg *message.Message) (bool, error)
+
+	switch command {
+	case "list":
+		handle = func(msg *message.Message) (bool, error) {
+			fmt.Printf("%s\t%s\tevent_id=%s\tretries=%s\tdead_lettered_at=%s\treason=%s\n",
+				msg.UUID,
+				msg.Metadata.Get(events.EventTypeMetadataKey),
+				msg.Metadata.Get(events.EventIDMetadataKey),
+				valueOr(msg.Metadata.Get(events.RetryCountMetadataKey), "0"),
+				msg.Metadata.Get(events.DeadLetteredAtMetadataKey),
+				msg.Metadata.Get(events.DeadLetterReasonMetadataKey),
+			)
+			return false, nil
+		}
+	case "replay":
+		publisher, err := events.NewMessagePublisher(ctx, cfg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to create publisher")
+		}
+		defer func() {
+			_ = publisher.Close()
+		}()
+
+		handle = func(msg *message.Message) (bool, error) {
+			if !*all && msg.Metadata.Get(events.EventIDMetadataKey) != *eventID {
+				return false, nil
+			}
+			if err := publisher.Publish(cfg.AWS.EventQueueName, events.Replay(msg)); err != nil {
+				return false, err
+			}
+			fmt.Printf("replayed %s\t%s\tevent_id=%s\n", msg.UUID, msg.Metadata.Get(events.EventTypeMetadataKey), msg.Metadata.Get(events.EventIDMetadataKey))
+			return true, nil
+		}
+	}
+
+	messages, err := subscriber.Subscribe(ctx, cfg.AWS.EventDLQName)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to subscribe to dead-letter queue")
+	}
+
+	seen := map[string]bool{}
+
+	for len(seen) < *limit {
+		select {
+		case msg, ok := <-messages:
+			if !ok {
+				return
+			}
+
+			// Messages left in the queue are redelivered straight away, so
+			// seeing one again means every message has been read.
+			if seen[msg.UUID] {
+				msg.Nack()
+				log.Info().Int("messages", len(seen)).Msg("Read every dead-lettered message")
+				return
+			}
+			seen[msg.UUID] = true
+
+			done, err := handle(msg)
+			if err != nil {
+				log.Error().Err(err).Str("message_id", msg.UUID).Msg("Failed to handle message")
+			}
+			if done {
+				msg.Ack()
+			} else {
+				msg.Nack()
+			}
+		case <-time.After(*wait):
+			log.Info().Int("messages", len(seen)).Msg("No more dead-lettered messages")
+			return
+		}
+	}
+}
+
+func exitWithUsage() {
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(2)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anzhy11/go-e-commerce/internal/logger"
+	"github.com/anzhy11/go-e-commerce/internal/notifications"
+)
+
+// email-preview renders every email template in every locale with sample
+// data, so the emails can be reviewed in a browser without sending them.
+func main() {
+	out := flag.String("out", "tmp/email-preview", "directory to write the rendered emails to")
+	flag.Parse()
+
+	log := logger.New()
+
+	renderer, err := notifications.NewRenderer()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load email templates")
+	}
+
+	if err := os.MkdirAll(*out, 0o755); err != nil {
+		log.Fatal().Err(err).Msg("Failed to create output directory")
+	}
+
+	var index strings.Builder
+	index.WriteString("<!DOCTYPE html>\n<html>\n<head><meta charset=\"utf-8\"><title>Email previews</title></head>\n<body>\n<h1>Email previews</h1>\n")
+
+	for _, name := range notifications.Templates {
+		data, err := notifications.SampleData(name)
+		if err != nil {
+			log.Fatal().Err(err).Str("template", name).Msg("Failed to build sample data")
+		}
+
+		fmt.Fprintf(&index, "<h2>%s</h2>\n<ul>\n", html.EscapeString(name))
+
+		for _, locale := range renderer.Locales() {
+			rendered, err := renderer.Render(name, locale, data)
+			if err != nil {
+				log.Fatal().Err(err).Str("template", name).Str("locale", locale).Msg("Failed to render email")
+			}
+
+			base := name + "." + locale
+			if err := os.WriteFile(filepath.Join(*out, base+".html"), []byte(rendered.HTML), 0o644); err != nil {
+				log.Fatal().Err(err).Msg("Failed to write preview")
+			}
+			if err := os.WriteFile(filepath.Join(*out, base+".txt"), []byte(rendered.Text), 0o644); err != nil {
+				log.Fatal().Err(err).Msg("Failed to write preview")
+			}
+
+			fmt.Fprintf(&index, "<li>%s: %s (<a href=\"%s.html\">html</a>, <a href=\"%s.txt\">text</a>)</li>\n",
+				html.EscapeString(locale), html.EscapeString(rendered.Subject), base, base)
+		}
+
+		index.WriteString("</ul>\n")
+	}
+
+	index.WriteString("</body>\n</html>\n")
+
+	indexPath := filepath.Join(*out, "index.html")
+	if err := os.WriteFile(indexPath, []byte(index.String()), 0o644); err != nil {
+		log.Fatal().Err(err).Msg("Failed to write preview index")
+	}
+
+	log.Info().Str("index", indexPath).Msg("Rendered email previews")
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"net/http"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/identity/oidctest"
+	"github.com/anzhy11/go-e-commerce/internal/logger"
+)
+
+// fake-oidc serves a fake OpenID Connect issuer for trying sign-in with an
+// external provider locally. Every login is approved as the user given by
+// the flags. Point the API at it with:
+//
+//	OIDC_PROVIDERS=dev
+//	OIDC_DEV_ISSUER_URL=http://localhost:9400
+//	OIDC_DEV_CLIENT_ID=ecommerce
+//	OIDC_DEV_CLIENT_SECRET=secret
+//
+// then open /api/v1/auth/oidc/dev/login in a browser.
+func main() {
+	addr := flag.String("addr", "localhost:9400", "address to listen on")
+	issuerURL := flag.String("issuer", "http://localhost:9400", "issuer URL, as the API reaches it")
+	clientID := flag.String("client-id", "ecommerce", "client id")
+	clientSecret := flag.String("client-secret", "secret", "client secret")
+	subject := flag.String("sub", "fake-oidc-user", "subject of the user signed in")
+	email := flag.String("email", "oidc.user@example.com", "email of the user signed in")
+	verified := flag.Bool("email-verified", true, "whether the email is verified")
+	givenName := flag.String("given-name", "Oidc", "given name of the user signed in")
+	familyName := flag.String("family-name", "User", "family name of the user signed in")
+	flag.Parse()
+
+	log := logger.New()
+
+	issuer, err := oidctest.New(*issuerURL, *clientID, *clientSecret)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create issuer")
+	}
+	issuer.SetUser(oidctest.User{
+		Subject:       *subject,
+		Email:         *email,
+		EmailVerified: *verified,
+		GivenName:     *givenName,
+		FamilyName:    *familyName,
+	})
+
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           issuer,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	log.Info().Str("addr", *addr).Str("issuer", issuer.URL).Str("email", *email).Msg("Fake OIDC issuer listening")
+	if err := server.ListenAndServe(); err != nil {
+		log.Fatal().Err(err).Msg("Fake OIDC issuer stopped")
+	}
+}
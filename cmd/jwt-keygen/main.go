@@ -0,0 +1,93 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/anzhy11/go-e-commerce/internal/logger"
+	"github.com/anzhy11/go-e-commerce/pkg/jwks"
+)
+
+// jwt-keygen writes a new JWT signing key to <out>/<kid>.pem.
+//
+// Keys are rotated without logging anyone out:
+//  1. Generate a key into the keys directory of every instance and restart
+//     them. The key is now published in /.well-known/jwks.json but signs
+//     nothing yet.
+//  2. Once other services had time to refresh their copy of the key set,
+//     set JWT_SIGNING_KEY_ID to the new kid and restart.
+//  3. Replace the old key with its public half (-public) so tokens it signed
+//     still verify, and delete it once REFRESH_TOKEN_EXPIRES_IN has passed.
+func main() {
+	alg := flag.String("alg", jwks.AlgEdDSA, "signing algorithm, EdDSA or RS256")
+	kid := flag.String("kid", "", "key id, random when empty")
+	out := flag.String("out", "keys", "directory to write the key to")
+	public := flag.String("public", "", "write the public half of this private key file instead of generating a key")
+	flag.Parse()
+
+	log := logger.New()
+
+	var key *jwks.Key
+	if *public != "" {
+		data, err := os.ReadFile(*public)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to read key")
+		}
+		id := strings.TrimSuffix(filepath.Base(*public), filepath.Ext(*public))
+		key, err = jwks.ParsePEM(id, data)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to parse key")
+		}
+		key.Private = nil
+	} else {
+		var err error
+		key, err = jwks.Generate(*alg)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to generate key")
+		}
+	}
+
+	if *kid != "" {
+		if strings.ContainsAny(*kid, `/\`) || strings.HasPrefix(*kid, ".") {
+			log.Fatal().Str("kid", *kid).Msg("Key id must be a plain file name")
+		}
+		key.ID = *kid
+	}
+
+	data, err := key.MarshalPEM()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to encode key")
+	}
+
+	if err := os.MkdirAll(*out, 0o700); err != nil {
+		log.Fatal().Err(err).Msg("Failed to create output directory")
+	}
+
+	path := filepath.Join(*out, key.ID+".pem")
+	if *public != "" && filepath.Clean(path) == filepath.Clean(*public) {
+		// Overwriting the private key with its public half retires it.
+		err = os.WriteFile(path, data, 0o644)
+	} else {
+		err = writeNew(path, data)
+	}
+	if err != nil {
+		log.Fatal().Err(err).Str("path", path).Msg("Failed to write key")
+	}
+
+	log.Info().Str("kid", key.ID).Str("alg", key.Algorithm).Str("path", path).Msg("Key written")
+}
+
+// writeNew refuses to overwrite an existing key.
+func writeNew(path string, data []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -2,31 +2,31 @@ package main
 
 import (
 	"context"
-	"encoding/json"
-	"log"
+	"math"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
+	"time"
 
-	"github.com/ThreeDotsLabs/watermill"
-	"github.com/ThreeDotsLabs/watermill-aws/sqs"
-	"github.com/ThreeDotsLabs/watermill/message"
 	"github.com/anzhy11/go-e-commerce/internal/config"
-	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/logger"
 	"github.com/anzhy11/go-e-commerce/internal/notifications"
-	"github.com/anzhy11/go-e-commerce/internal/providers"
+	"github.com/rs/zerolog"
 )
 
 func main() {
-	log.Println("Starting notifier service...")
+	log := logger.New()
+	log.Info().Msg("Starting notifier service")
 
 	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to load config: ", err)
+		log.Fatal().Err(err).Msg("Failed to load config")
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	notifierConfig := &notifications.SMTPConfig{
 		Host:     cfg.SMTP.Host,
@@ -36,87 +36,126 @@ func main() {
 		From:     cfg.SMTP.From,
 	}
 
-	emailNotifier := notifications.NewEmailNotifier(notifierConfig)
-
-	awsConfig, err := providers.CreateAwsConfig(ctx, &cfg.AWS)
+	emailNotifier, err := notifications.NewEmailNotifier(notifierConfig)
 	if err != nil {
-		log.Printf("Failed to create AWS config: %v", err)
+		log.Fatal().Err(err).Msg("Failed to load email templates")
 	}
 
-	logger := watermill.NewStdLogger(false, false)
-
-	subscriber, err := sqs.NewSubscriber(
-		sqs.SubscriberConfig{
-			AWSConfig: awsConfig,
-		},
-		logger,
-	)
+	subscriber, err := events.NewSubscriber(ctx, cfg)
 	if err != nil {
-		log.Printf("Failed to create SQS subscriber: %v", err)
+		log.Fatal().Err(err).Msg("Failed to create subscriber")
 	}
 	defer func() {
 		_ = subscriber.Close()
 	}()
 
-	messages, err := subscriber.Subscribe(ctx, cfg.AWS.EventQueueName)
+	publisher, err := events.NewMessagePublisher(ctx, cfg)
 	if err != nil {
-		log.Printf("Failed to subscribe to SQS: %v", err)
+		log.Fatal().Err(err).Msg("Failed to create publisher")
 	}
+	defer func() {
+		_ = publisher.Close()
+	}()
 
-	signalChan := make(chan os.Signal, 1)
-	signal.Notify(signalChan, syscall.SIGINT, syscall.SIGTERM)
-	defer signal.Stop(signalChan)
+	router := newRouter(cfg, log, emailNotifier)
+	consumer := events.NewConsumer(subscriber, publisher, router, cfg, log)
 
-	log.Println("Notifier service started. Waiting for messages...")
+	log.Info().Str("queue", cfg.AWS.EventQueueName).Msg("Notifier service started, waiting for messages")
 
-	for {
-		select {
-		case msg, ok := <-messages:
-			if !ok {
-				log.Println("SQS message channel closed")
-				return
-			}
+	if err := consumer.Run(ctx); err != nil {
+		log.Error().Err(err).Msg("Failed to consume event queue")
+		os.Exit(1)
+	}
 
-			if err := processMessage(msg, emailNotifier); err != nil {
-				log.Printf("Failed to process message: %v", err)
-				msg.Nack()
-				continue
-			}
+	log.Info().Msg("Notifier service stopped")
+}
 
-			msg.Ack()
+func newRouter(cfg *config.Config, log *zerolog.Logger, emailNotifier *notifications.EmailNotifier) *events.Router {
+	router := events.NewRouter()
+	router.Use(
+		events.Logging(log),
+		events.Timeout(cfg.Events.HandlerTimeout),
+		events.Recoverer(log),
+	)
 
-		case <-signalChan:
-			log.Println("Shutting down notifier service...")
-			cancel()
-			return
-		}
-	}
-}
+	router.Handle(events.UserLoggedInEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		loggedIn := event.(*events.UserLoggedIn)
+		return emailNotifier.SendLoginNotification(loggedIn.Email, displayName(loggedIn.FirstName, loggedIn.LastName), loggedIn.Locale)
+	})
 
-func processMessage(msg *message.Message, emailNotifier *notifications.EmailNotifier) error {
-	eventType := msg.Metadata.Get("event_type")
+	router.Handle(events.UserRegisteredEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		registered := event.(*events.UserRegistered)
 
-	switch eventType {
-	case notifications.UserLoggedInEventType:
-		return handleUserLoggedIn(msg, emailNotifier)
-	default:
-		log.Printf("Unknown event type: %s", eventType)
-		return nil
-	}
-}
+		// Accounts registered before email verification existed, or through an
+		// identity provider that verified the email, have no link.
+		if registered.VerificationURL == "" {
+			return nil
+		}
 
-func handleUserLoggedIn(msg *message.Message, emailNotifier *notifications.EmailNotifier) error {
-	var user models.User
-	if err := json.Unmarshal(msg.Payload, &user); err != nil {
-		return err
-	}
+		return emailNotifier.SendVerificationEmail(registered.Email, registered.Locale, notifications.VerifyEmailData{
+			Name:           displayName(registered.FirstName, registered.LastName),
+			URL:            registered.VerificationURL,
+			ExpiresInHours: hoursUntil(envelope.OccurredAt, registered.VerificationExpiresAt),
+		})
+	})
+
+	router.Handle(events.EmailVerificationRequestedEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		requested := event.(*events.EmailVerificationRequested)
+		return emailNotifier.SendVerificationEmail(requested.Email, requested.Locale, notifications.VerifyEmailData{
+			Name:           displayName(requested.FirstName, requested.LastName),
+			URL:            requested.VerificationURL,
+			ExpiresInHours: hoursUntil(envelope.OccurredAt, requested.VerificationExpiresAt),
+		})
+	})
+
+	router.Handle(events.PasswordResetRequestedEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		requested := event.(*events.PasswordResetRequested)
+		return emailNotifier.SendPasswordResetEmail(requested.Email, requested.Locale, notifications.PasswordResetData{
+			Name:             displayName(requested.FirstName, requested.LastName),
+			URL:              requested.ResetURL,
+			ExpiresInMinutes: int(math.Ceil(requested.ExpiresAt.Sub(envelope.OccurredAt).Minutes())),
+		})
+	})
+
+	router.Handle(events.PasswordChangedEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		changed := event.(*events.PasswordChanged)
+		return emailNotifier.SendPasswordChangedNotification(changed.Email, displayName(changed.FirstName, changed.LastName), changed.Locale)
+	})
+
+	router.Handle(events.RefreshTokenReusedEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		reused := event.(*events.RefreshTokenReused)
+		return emailNotifier.SendSessionRevokedNotification(reused.Email, displayName(reused.FirstName, reused.LastName), reused.Locale)
+	})
+
+	router.Handle(events.AccountLockedEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		locked := event.(*events.AccountLocked)
+		return emailNotifier.SendAccountLockedNotification(locked.Email, locked.Locale, notifications.AccountLockedData{
+			Name:             displayName(locked.FirstName, locked.LastName),
+			IPAddress:        locked.IPAddress,
+			LockedForMinutes: int(math.Ceil(locked.LockedUntil.Sub(locked.LockedAt).Minutes())),
+		})
+	})
+
+	router.Handle(events.WishlistBackInStockEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		return emailNotifier.SendBackInStockNotification(&event.(*events.WishlistBackInStock).WishlistAlert)
+	})
+
+	router.Handle(events.WishlistPriceDropEventType, func(ctx context.Context, envelope *events.Envelope, event events.Event) error {
+		return emailNotifier.SendPriceDropNotification(&event.(*events.WishlistPriceDrop).WishlistAlert)
+	})
+
+	return router
+}
 
-	userName := user.FirstName + " " + user.LastName
-	if userName == "" {
-		userName = "User"
+func displayName(firstName, lastName string) string {
+	name := strings.TrimSpace(firstName + " " + lastName)
+	if name == "" {
+		return "User"
 	}
+	return name
+}
 
-	log.Printf("Sending login notification to %s (%s)", userName, user.Email)
-
-	return emailNotifier.SendLoginNotification(user.Email, userName)
+// hoursUntil rounds the validity of a link up to whole hours for display.
+func hoursUntil(from, to time.Time) int {
+	return int(math.Ceil(to.Sub(from).Hours()))
 }
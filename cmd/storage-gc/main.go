@@ -0,0 +1,61 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/database"
+	"github.com/anzhy11/go-e-commerce/internal/logger"
+	"github.com/anzhy11/go-e-commerce/internal/providers"
+	uploadService "github.com/anzhy11/go-e-commerce/internal/services/upload"
+)
+
+// storage-gc deletes uploaded files that no product image references any more.
+func main() {
+	gracePeriod := flag.Duration("grace", 24*time.Hour, "keep unreferenced objects touched more recently than this")
+	dryRun := flag.Bool("dry-run", false, "list the objects that would be deleted without deleting them")
+	flag.Parse()
+
+	log := logger.New()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+
+	mainDb, err := db.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get database connection")
+	}
+
+	defer func() {
+		if dbErr := mainDb.Close(); dbErr != nil {
+			log.Error().Err(dbErr).Msg("Failed to close database connection")
+		}
+	}()
+
+	up, err := providers.NewUploadProvider(cfg, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to create upload provider")
+	}
+	us := uploadService.NewUploadService(db, up, &cfg.Upload)
+
+	result, err := us.CollectGarbage(*gracePeriod, *dryRun, log)
+	if err != nil {
+		log.Error().Err(err).Msg("Garbage collection stopped")
+	}
+
+	if result != nil {
+		log.Info().
+			Bool("dry_run", *dryRun).
+			Int("deleted", result.Deleted).
+			Int("skipped", result.Skipped).
+			Int("failed", result.Failed).
+			Msg("Garbage collection finished")
+	}
+}
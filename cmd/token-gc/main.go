@@ -0,0 +1,65 @@
+package main
+
+import (
+	"flag"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/database"
+	"github.com/anzhy11/go-e-commerce/internal/logger"
+	authService "github.com/anzhy11/go-e-commerce/internal/services/auth"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+)
+
+// token-gc deletes expired sessions, refresh tokens, single-use email
+// tokens, OIDC login states and stale login throttles.
+// Rotated refresh tokens are kept until they expire so reuse can be detected.
+func main() {
+	gracePeriod := flag.Duration("grace", 24*time.Hour, "keep tokens that expired more recently than this")
+	flag.Parse()
+
+	log := logger.New()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load config")
+	}
+
+	db, err := database.New(&cfg.Database)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to connect to database")
+	}
+
+	mainDb, err := db.DB()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get database connection")
+	}
+
+	defer func() {
+		if dbErr := mainDb.Close(); dbErr != nil {
+			log.Error().Err(dbErr).Msg("Failed to close database connection")
+		}
+	}()
+
+	tokens, err := utils.NewTokenManager(&cfg.JWT, cfg.Server.GinMode, log)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load JWT keys")
+	}
+
+	as := authService.New(db, cfg, log, tokens, sessionService.New(db, cfg))
+
+	result, err := as.PurgeExpiredTokens(time.Now().Add(-*gracePeriod))
+	if err != nil {
+		log.Error().Err(err).Msg("Token cleanup stopped")
+	}
+
+	if result != nil {
+		log.Info().
+			Int64("sessions", result.Sessions).
+			Int64("refresh_tokens", result.RefreshTokens).
+			Int64("user_tokens", result.UserTokens).
+			Int64("login_throttles", result.LoginThrottles).
+			Int64("oidc_login_states", result.OIDCLoginStates).
+			Msg("Token cleanup finished")
+	}
+}
@@ -0,0 +1,12 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_category_attributes_updated_at ON category_attributes;
+
+-- Drop product attribute values
+DROP INDEX IF EXISTS idx_products_attributes;
+ALTER TABLE products DROP COLUMN IF EXISTS attributes;
+
+-- Drop unique constraint
+DROP INDEX IF EXISTS idx_category_attributes_unique_key_per_category;
+
+-- Drop tables
+DROP TABLE IF EXISTS category_attributes;
@@ -0,0 +1,40 @@
+-- Create category_attributes table
+CREATE TABLE IF NOT EXISTS category_attributes (
+    id SERIAL PRIMARY KEY,
+    category_id INTEGER NOT NULL,
+    key VARCHAR(100) NOT NULL,
+    label VARCHAR(255) NOT NULL,
+    type VARCHAR(20) NOT NULL CHECK (type IN ('text', 'number', 'enum', 'boolean')),
+    unit VARCHAR(50),
+    options JSONB NOT NULL DEFAULT '[]',
+    is_required BOOLEAN DEFAULT false,
+    is_filterable BOOLEAN DEFAULT false,
+    position INTEGER DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_category_attributes_category
+        FOREIGN KEY (category_id)
+        REFERENCES categories(id)
+        ON DELETE CASCADE
+);
+
+-- Create indexes for category_attributes
+CREATE INDEX IF NOT EXISTS idx_category_attributes_deleted_at ON category_attributes(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_category_attributes_category_id ON category_attributes(category_id);
+
+-- Ensure unique attribute key per category (excluding soft-deleted attributes)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_category_attributes_unique_key_per_category
+    ON category_attributes(category_id, key)
+    WHERE deleted_at IS NULL;
+
+-- Add attribute values to products
+ALTER TABLE products ADD COLUMN IF NOT EXISTS attributes JSONB NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_products_attributes ON products USING GIN (attributes);
+
+-- Create trigger for category_attributes table
+CREATE TRIGGER update_category_attributes_updated_at
+    BEFORE UPDATE ON category_attributes
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
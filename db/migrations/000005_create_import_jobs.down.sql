@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_import_jobs_updated_at ON import_jobs;
+
+-- Drop tables
+DROP TABLE IF EXISTS import_jobs;
+
+-- Drop custom enum type
+DROP TYPE IF EXISTS import_job_status;
@@ -0,0 +1,15 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_reviews_updated_at ON reviews;
+
+-- Drop rating summary
+ALTER TABLE products DROP COLUMN IF EXISTS review_count;
+ALTER TABLE products DROP COLUMN IF EXISTS average_rating;
+
+-- Drop unique constraint
+DROP INDEX IF EXISTS idx_reviews_unique_user_per_product;
+
+-- Drop tables
+DROP TABLE IF EXISTS reviews;
+
+-- Drop custom enum type
+DROP TYPE IF EXISTS review_status;
@@ -0,0 +1,51 @@
+-- Create custom enum type for review status
+CREATE TYPE review_status AS ENUM ('pending', 'approved', 'rejected');
+
+-- Create reviews table
+CREATE TABLE IF NOT EXISTS reviews (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 5),
+    title VARCHAR(255),
+    body TEXT NOT NULL,
+    status review_status DEFAULT 'pending',
+    moderation_note TEXT,
+    moderated_by INTEGER,
+    moderated_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_reviews_product
+        FOREIGN KEY (product_id)
+        REFERENCES products(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_reviews_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_reviews_moderated_by
+        FOREIGN KEY (moderated_by)
+        REFERENCES users(id)
+        ON DELETE SET NULL
+);
+
+-- Create indexes for reviews
+CREATE INDEX IF NOT EXISTS idx_reviews_deleted_at ON reviews(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_reviews_product_id_status ON reviews(product_id, status);
+CREATE INDEX IF NOT EXISTS idx_reviews_status ON reviews(status);
+
+-- One review per customer and product (excluding soft-deleted reviews)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_reviews_unique_user_per_product
+    ON reviews(product_id, user_id)
+    WHERE deleted_at IS NULL;
+
+-- Denormalised rating summary on products
+ALTER TABLE products ADD COLUMN IF NOT EXISTS average_rating DECIMAL(3, 2) NOT NULL DEFAULT 0;
+ALTER TABLE products ADD COLUMN IF NOT EXISTS review_count INTEGER NOT NULL DEFAULT 0;
+
+-- Create trigger for reviews table
+CREATE TRIGGER update_reviews_updated_at
+    BEFORE UPDATE ON reviews
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,8 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_wishlist_items_updated_at ON wishlist_items;
+
+-- Drop unique constraint
+DROP INDEX IF EXISTS idx_wishlist_items_unique_product_per_user;
+
+-- Drop tables
+DROP TABLE IF EXISTS wishlist_items;
@@ -0,0 +1,36 @@
+-- Create wishlist_items table
+CREATE TABLE IF NOT EXISTS wishlist_items (
+    id SERIAL PRIMARY KEY,
+    user_id INTEGER NOT NULL,
+    product_id INTEGER NOT NULL,
+    notify_back_in_stock BOOLEAN NOT NULL DEFAULT true,
+    notify_price_drop BOOLEAN NOT NULL DEFAULT true,
+    unsubscribe_token VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT fk_wishlist_items_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_wishlist_items_product
+        FOREIGN KEY (product_id)
+        REFERENCES products(id)
+        ON DELETE CASCADE
+);
+
+-- Create indexes for wishlist_items
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_deleted_at ON wishlist_items(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_user_id ON wishlist_items(user_id);
+CREATE INDEX IF NOT EXISTS idx_wishlist_items_product_id ON wishlist_items(product_id);
+
+-- Ensure unique product per wishlist (excluding soft-deleted items)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_wishlist_items_unique_product_per_user
+    ON wishlist_items(user_id, product_id)
+    WHERE deleted_at IS NULL;
+
+-- Create trigger for wishlist_items table
+CREATE TRIGGER update_wishlist_items_updated_at
+    BEFORE UPDATE ON wishlist_items
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
@@ -0,0 +1,6 @@
+-- Drop rendition columns
+ALTER TABLE product_images DROP COLUMN IF EXISTS height;
+ALTER TABLE product_images DROP COLUMN IF EXISTS width;
+ALTER TABLE product_images DROP COLUMN IF EXISTS large_url;
+ALTER TABLE product_images DROP COLUMN IF EXISTS medium_url;
+ALTER TABLE product_images DROP COLUMN IF EXISTS thumbnail_url;
@@ -0,0 +1,11 @@
+-- Add rendition URLs and dimensions to product_images
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS thumbnail_url TEXT;
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS medium_url TEXT;
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS large_url TEXT;
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS width INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS height INTEGER NOT NULL DEFAULT 0;
+
+-- Images uploaded before renditions existed fall back to the original file
+UPDATE product_images
+SET thumbnail_url = url, medium_url = url, large_url = url
+WHERE thumbnail_url IS NULL;
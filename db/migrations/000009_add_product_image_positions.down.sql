@@ -0,0 +1,6 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_product_images_unique_primary;
+DROP INDEX IF EXISTS idx_product_images_product_id_position;
+
+-- Drop position column
+ALTER TABLE product_images DROP COLUMN IF EXISTS position;
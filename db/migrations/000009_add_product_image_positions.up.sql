@@ -0,0 +1,26 @@
+-- Add display order to product_images
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS position INTEGER NOT NULL DEFAULT 0;
+
+-- Number existing images per product, primary image first
+UPDATE product_images
+SET position = ordered.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY product_id ORDER BY is_primary DESC, created_at, id) - 1 AS position
+    FROM product_images
+    WHERE deleted_at IS NULL
+) AS ordered
+WHERE product_images.id = ordered.id;
+
+-- Keep only the earliest primary image where several were flagged
+UPDATE product_images
+SET is_primary = false
+WHERE is_primary = true
+  AND deleted_at IS NULL
+  AND position > 0;
+
+CREATE INDEX IF NOT EXISTS idx_product_images_product_id_position ON product_images(product_id, position);
+
+-- At most one primary image per product (excluding soft-deleted images)
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_images_unique_primary
+    ON product_images(product_id)
+    WHERE is_primary = true AND deleted_at IS NULL;
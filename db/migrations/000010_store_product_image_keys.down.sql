@@ -0,0 +1,14 @@
+-- Restore the URLs the local provider used to store
+UPDATE product_images
+SET key = '/uploads/' || key,
+    thumbnail_key = '/uploads/' || thumbnail_key,
+    medium_key = '/uploads/' || medium_key,
+    large_key = '/uploads/' || large_key
+WHERE storage_provider = 'local';
+
+ALTER TABLE product_images DROP COLUMN IF EXISTS storage_provider;
+
+ALTER TABLE product_images RENAME COLUMN large_key TO large_url;
+ALTER TABLE product_images RENAME COLUMN medium_key TO medium_url;
+ALTER TABLE product_images RENAME COLUMN thumbnail_key TO thumbnail_url;
+ALTER TABLE product_images RENAME COLUMN key TO url;
@@ -0,0 +1,19 @@
+-- Product images reference storage keys instead of provider-specific URLs
+ALTER TABLE product_images RENAME COLUMN url TO key;
+ALTER TABLE product_images RENAME COLUMN thumbnail_url TO thumbnail_key;
+ALTER TABLE product_images RENAME COLUMN medium_url TO medium_key;
+ALTER TABLE product_images RENAME COLUMN large_url TO large_key;
+
+ALTER TABLE product_images ADD COLUMN IF NOT EXISTS storage_provider VARCHAR(20) NOT NULL DEFAULT 'local';
+
+-- The local provider stored "/uploads/<key>"; anything else is a bare S3 key
+UPDATE product_images
+SET storage_provider = 's3'
+WHERE key NOT LIKE '/uploads/%';
+
+UPDATE product_images
+SET key = regexp_replace(key, '^/uploads/', ''),
+    thumbnail_key = regexp_replace(thumbnail_key, '^/uploads/', ''),
+    medium_key = regexp_replace(medium_key, '^/uploads/', ''),
+    large_key = regexp_replace(large_key, '^/uploads/', '')
+WHERE storage_provider = 'local';
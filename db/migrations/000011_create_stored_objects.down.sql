@@ -0,0 +1,5 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_stored_objects_updated_at ON stored_objects;
+
+-- Drop tables
+DROP TABLE IF EXISTS stored_objects;
@@ -0,0 +1,44 @@
+-- Create stored_objects table
+CREATE TABLE IF NOT EXISTS stored_objects (
+    id SERIAL PRIMARY KEY,
+    storage_provider VARCHAR(20) NOT NULL,
+    key TEXT NOT NULL,
+    content_type VARCHAR(100),
+    size BIGINT NOT NULL DEFAULT 0,
+    ref_count INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create indexes for stored_objects
+CREATE UNIQUE INDEX IF NOT EXISTS idx_stored_objects_provider_key ON stored_objects(storage_provider, key);
+CREATE INDEX IF NOT EXISTS idx_stored_objects_unreferenced ON stored_objects(updated_at) WHERE ref_count <= 0;
+
+-- Register files of existing images, counting each image once per distinct key
+INSERT INTO stored_objects (storage_provider, key, ref_count)
+SELECT storage_provider, object_key, COUNT(*)
+FROM (
+    SELECT DISTINCT id, storage_provider, unnest(ARRAY[key, thumbnail_key, medium_key, large_key]) AS object_key
+    FROM product_images
+    WHERE deleted_at IS NULL
+) AS refs
+WHERE object_key IS NOT NULL AND object_key <> ''
+GROUP BY storage_provider, object_key
+ON CONFLICT (storage_provider, key) DO NOTHING;
+
+-- Files of already deleted images are registered unreferenced so they get collected
+INSERT INTO stored_objects (storage_provider, key, ref_count)
+SELECT DISTINCT storage_provider, object_key, 0
+FROM (
+    SELECT storage_provider, unnest(ARRAY[key, thumbnail_key, medium_key, large_key]) AS object_key
+    FROM product_images
+    WHERE deleted_at IS NOT NULL
+) AS refs
+WHERE object_key IS NOT NULL AND object_key <> ''
+ON CONFLICT (storage_provider, key) DO NOTHING;
+
+-- Create trigger for stored_objects table
+CREATE TRIGGER update_stored_objects_updated_at
+    BEFORE UPDATE ON stored_objects
+    FOR EACH ROW
+    EXECUTE FUNCTION update_updated_at_column();
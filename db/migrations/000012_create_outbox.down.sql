@@ -0,0 +1,5 @@
+-- Drop triggers
+DROP TRIGGER IF EXISTS update_outbox_updated_at ON outbox;
+
+-- Drop tables
+DROP TABLE IF EXISTS outbox;
@@ -0,0 +1,2 @@
+-- Drop locale column
+ALTER TABLE users DROP COLUMN IF EXISTS locale;
@@ -0,0 +1,2 @@
+-- Language used for emails sent to the user
+ALTER TABLE users ADD COLUMN IF NOT EXISTS locale VARCHAR(10) NOT NULL DEFAULT 'en';
@@ -0,0 +1,5 @@
+-- Drop tables
+DROP TABLE IF EXISTS user_tokens;
+
+-- Drop email_verified_at column
+ALTER TABLE users DROP COLUMN IF EXISTS email_verified_at;
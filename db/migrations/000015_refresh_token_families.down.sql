@@ -0,0 +1,12 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_refresh_tokens_expires_at;
+DROP INDEX IF EXISTS idx_refresh_tokens_family_id;
+DROP INDEX IF EXISTS idx_refresh_tokens_token_hash;
+
+-- Raw tokens cannot be recovered from their hashes, so every user signs in again
+DELETE FROM refresh_tokens;
+
+ALTER TABLE refresh_tokens ADD COLUMN IF NOT EXISTS token VARCHAR(500) UNIQUE NOT NULL;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS rotated_at;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS family_id;
+ALTER TABLE refresh_tokens DROP COLUMN IF EXISTS token_hash;
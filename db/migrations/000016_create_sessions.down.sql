@@ -0,0 +1,5 @@
+-- Drop constraints
+ALTER TABLE refresh_tokens DROP CONSTRAINT IF EXISTS fk_refresh_tokens_session;
+
+-- Drop tables
+DROP TABLE IF EXISTS sessions;
@@ -0,0 +1,6 @@
+-- Drop tables
+DROP TABLE IF EXISTS mfa_recovery_codes;
+DROP TABLE IF EXISTS user_totps;
+
+-- Drop mfa_enabled_at column
+ALTER TABLE users DROP COLUMN IF EXISTS mfa_enabled_at;
@@ -0,0 +1,61 @@
+-- Create roles table
+CREATE TABLE IF NOT EXISTS roles (
+    name VARCHAR(50) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    is_system BOOLEAN NOT NULL DEFAULT false,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Create permissions table
+CREATE TABLE IF NOT EXISTS permissions (
+    name VARCHAR(100) PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT ''
+);
+
+-- Create role_permissions table
+CREATE TABLE IF NOT EXISTS role_permissions (
+    role_name VARCHAR(50) NOT NULL,
+    permission VARCHAR(100) NOT NULL,
+    PRIMARY KEY (role_name, permission),
+    CONSTRAINT fk_role_permissions_role
+        FOREIGN KEY (role_name)
+        REFERENCES roles(name)
+        ON DELETE CASCADE,
+    CONSTRAINT fk_role_permissions_permission
+        FOREIGN KEY (permission)
+        REFERENCES permissions(name)
+        ON DELETE CASCADE
+);
+
+-- Seed permissions
+INSERT INTO permissions (name, description) VALUES
+    ('catalog:write', 'Manage products, categories, images and imports'),
+    ('orders:write', 'Update the status of orders'),
+    ('reviews:moderate', 'Moderate product reviews'),
+    ('users:write', 'Unlock user accounts'),
+    ('roles:manage', 'Manage roles and assign them to users')
+ON CONFLICT (name) DO NOTHING;
+
+-- Seed system roles
+INSERT INTO roles (name, description, is_system) VALUES
+    ('admin', 'Every permission', true),
+    ('customer', 'Shop customers', true)
+ON CONFLICT (name) DO NOTHING;
+
+INSERT INTO role_permissions (role_name, permission)
+SELECT 'admin', name FROM permissions
+ON CONFLICT DO NOTHING;
+
+-- Keep any other role already given to users, without permissions
+INSERT INTO roles (name)
+SELECT DISTINCT role FROM users WHERE role IS NOT NULL
+ON CONFLICT (name) DO NOTHING;
+
+-- Users get a role that exists
+UPDATE users SET role = 'customer' WHERE role IS NULL;
+ALTER TABLE users ALTER COLUMN role SET NOT NULL;
+ALTER TABLE users
+    ADD CONSTRAINT fk_users_role
+        FOREIGN KEY (role)
+        REFERENCES roles(name);
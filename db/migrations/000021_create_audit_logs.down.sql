@@ -0,0 +1,9 @@
+-- Remove users:read permission
+DELETE FROM permissions WHERE name = 'users:read';
+
+UPDATE permissions
+SET description = 'Unlock user accounts'
+WHERE name = 'users:write';
+
+-- Drop audit_logs table
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,33 @@
+-- Create audit_logs table
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id SERIAL PRIMARY KEY,
+    actor_id INTEGER NOT NULL,
+    user_id INTEGER NOT NULL,
+    action VARCHAR(50) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    CONSTRAINT fk_audit_logs_actor
+        FOREIGN KEY (actor_id)
+        REFERENCES users(id),
+    CONSTRAINT fk_audit_logs_user
+        FOREIGN KEY (user_id)
+        REFERENCES users(id)
+);
+
+-- Create indexes for audit_logs
+CREATE INDEX IF NOT EXISTS idx_audit_logs_user_id ON audit_logs(user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_actor_id ON audit_logs(actor_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_at ON audit_logs(created_at);
+
+-- Add users:read permission
+INSERT INTO permissions (name, description) VALUES
+    ('users:read', 'View user accounts, their orders and sessions, and the audit log')
+ON CONFLICT (name) DO NOTHING;
+
+UPDATE permissions
+SET description = 'Unlock, deactivate and reactivate user accounts and force password resets'
+WHERE name = 'users:write';
+
+INSERT INTO role_permissions (role_name, permission) VALUES
+    ('admin', 'users:read')
+ON CONFLICT DO NOTHING;
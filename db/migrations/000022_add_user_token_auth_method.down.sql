@@ -0,0 +1,2 @@
+-- Drop auth_method column
+ALTER TABLE user_tokens DROP COLUMN IF EXISTS auth_method;
@@ -0,0 +1,2 @@
+-- Remember the first factor of the login an MFA challenge continues
+ALTER TABLE user_tokens ADD COLUMN IF NOT EXISTS auth_method VARCHAR(20) NOT NULL DEFAULT '';
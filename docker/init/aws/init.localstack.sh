@@ -3,5 +3,6 @@
 awslocal s3 mb s3://ecommerce-uploads
 
 awslocal sqs create-queue --queue-name ecommerce-events
+awslocal sqs create-queue --queue-name ecommerce-events-dlq
 
 echo "LocalStack initialized"
\ No newline at end of file
@@ -3,23 +3,32 @@ package config
 import (
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
 )
 
 type Config struct {
-	Server   ServerConfig
-	Database DatabaseConfig
-	AWS      AWSConfig
-	JWT      JWTConfig
-	Upload   UploadConfig
-	SMTP     SMTPConfig
+	Server    ServerConfig
+	Database  DatabaseConfig
+	AWS       AWSConfig
+	Events    EventsConfig
+	JWT       JWTConfig
+	Auth      AuthConfig
+	OIDC      OIDCConfig
+	Upload    UploadConfig
+	Inventory InventoryConfig
+	SMTP      SMTPConfig
 }
 
 type ServerConfig struct {
 	Port    string
 	GinMode string
+	AppURL  string
+	// TrustedProxies are the addresses or CIDR ranges whose X-Forwarded-For
+	// header is believed when finding a client's IP. Empty trusts no proxy.
+	TrustedProxies []string
 }
 
 type DatabaseConfig struct {
@@ -39,18 +48,98 @@ type AWSConfig struct {
 	S3Endpoint      string
 	SQSQueueURL     string
 	EventQueueName  string
+	EventDLQName    string
 }
 
+type EventsConfig struct {
+	Transport             string
+	Producer              string
+	OutboxPollInterval    time.Duration
+	OutboxBatchSize       int
+	OutboxMaxAttempts     int
+	OutboxRetryBackoff    time.Duration
+	OutboxMaxRetryBackoff time.Duration
+	OutboxRetention       time.Duration
+	MaxRetries            int
+	RetryBackoff          time.Duration
+	MaxRetryBackoff       time.Duration
+	HandlerTimeout        time.Duration
+}
+
+// JWTConfig configures token signing. KeysDir holds one PEM file per key,
+// named after its key ID; SigningKeyID picks the key new tokens are signed
+// with and may be left empty when the directory has a single private key.
 type JWTConfig struct {
-	Secret                string
+	KeysDir               string
+	SigningKeyID          string
+	Issuer                string
+	Audience              string
 	ExpiresIn             time.Duration
 	RefreshTokenExpiresIn time.Duration
 }
 
+// AuthConfig also holds the login throttling policy. Failures are counted
+// per account and per client IP over LoginFailureWindow. After
+// LoginFreeFailures an account has to wait LoginBaseDelay, doubling with each
+// further failure, and at LoginMaxFailures it is locked for
+// LoginLockoutDuration. An IP is blocked for the same duration after
+// LoginIPMaxFailures.
+//
+// MFASecretKey is the base64 encoded 32 byte key authenticator secrets are
+// encrypted with. Changing it disables every enrolled authenticator.
+// RequireMFAForAdmin applies to staff, that is every user whose role has a
+// permission.
+type AuthConfig struct {
+	EmailVerificationTokenTTL       time.Duration
+	RequireVerifiedEmailForCheckout bool
+	PasswordResetTokenTTL           time.Duration
+	PasswordResetURL                string
+	SessionCacheTTL                 time.Duration
+	LoginFailureWindow              time.Duration
+	LoginFreeFailures               int
+	LoginBaseDelay                  time.Duration
+	LoginMaxFailures                int
+	LoginLockoutDuration            time.Duration
+	LoginIPMaxFailures              int
+	MFAIssuer                       string
+	MFASecretKey                    string
+	MFAChallengeTTL                 time.Duration
+	RequireMFAForAdmin              bool
+	PermissionCacheTTL              time.Duration
+}
+
+// OIDCConfig lists the external identity providers users can sign in with.
+// OIDC_PROVIDERS names them, comma separated, and each name reads its
+// settings from OIDC_<NAME>_ISSUER_URL, _CLIENT_ID, _CLIENT_SECRET, and
+// optionally _SCOPES (space separated) and _REDIRECT_URL.
+type OIDCConfig struct {
+	Providers []OIDCProviderConfig
+	StateTTL  time.Duration
+}
+
+type OIDCProviderConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+}
+
 type UploadConfig struct {
-	Path          string
-	MaxUploadSize int64
-	Provider      string
+	Path                string
+	MaxUploadSize       int64
+	Provider            string
+	MinImageDimension   int
+	MaxImageDimension   int
+	MaxImagesPerProduct int
+	SigningSecret       string
+	PresignExpiresIn    time.Duration
+	CDNBaseURL          string
+}
+
+type InventoryConfig struct {
+	LowStockThreshold int
 }
 
 type SMTPConfig struct {
@@ -66,13 +155,44 @@ func Load() (*Config, error) {
 
 	jwtExpiresIn, _ := time.ParseDuration(getEnv("JWT_EXPIRES_IN", "24h"))
 	refreshTokenExpiresIn, _ := time.ParseDuration(getEnv("REFRESH_TOKEN_EXPIRES_IN", "72h"))
+	emailVerificationTokenTTL, _ := time.ParseDuration(getEnv("EMAIL_VERIFICATION_TOKEN_TTL", "24h"))
+	requireVerifiedEmailForCheckout, _ := strconv.ParseBool(getEnv("REQUIRE_VERIFIED_EMAIL_FOR_CHECKOUT", "false"))
+	passwordResetTokenTTL, _ := time.ParseDuration(getEnv("PASSWORD_RESET_TOKEN_TTL", "1h"))
+	sessionCacheTTL, _ := time.ParseDuration(getEnv("SESSION_CACHE_TTL", "30s"))
+	loginFailureWindow, _ := time.ParseDuration(getEnv("LOGIN_FAILURE_WINDOW", "15m"))
+	loginFreeFailures, _ := strconv.Atoi(getEnv("LOGIN_FREE_FAILURES", "2"))
+	loginBaseDelay, _ := time.ParseDuration(getEnv("LOGIN_BASE_DELAY", "1s"))
+	loginMaxFailures, _ := strconv.Atoi(getEnv("LOGIN_MAX_FAILURES", "5"))
+	loginLockoutDuration, _ := time.ParseDuration(getEnv("LOGIN_LOCKOUT_DURATION", "15m"))
+	loginIPMaxFailures, _ := strconv.Atoi(getEnv("LOGIN_IP_MAX_FAILURES", "50"))
+	mfaChallengeTTL, _ := time.ParseDuration(getEnv("MFA_CHALLENGE_TTL", "5m"))
+	requireMFAForAdmin, _ := strconv.ParseBool(getEnv("MFA_REQUIRED_FOR_ADMIN", "false"))
+	permissionCacheTTL, _ := time.ParseDuration(getEnv("PERMISSION_CACHE_TTL", "30s"))
+	oidcStateTTL, _ := time.ParseDuration(getEnv("OIDC_STATE_TTL", "10m"))
 	maxUploadSize, _ := strconv.ParseInt(getEnv("MAX_UPLOAD_SIZE", "10485760"), 10, 64)
 	smtpPort, _ := strconv.Atoi(getEnv("SMTP_PORT", "1025"))
+	minImageDimension, _ := strconv.Atoi(getEnv("MIN_IMAGE_DIMENSION", "100"))
+	maxImageDimension, _ := strconv.Atoi(getEnv("MAX_IMAGE_DIMENSION", "8000"))
+	maxImagesPerProduct, _ := strconv.Atoi(getEnv("MAX_IMAGES_PER_PRODUCT", "10"))
+	presignExpiresIn, _ := time.ParseDuration(getEnv("PRESIGN_EXPIRES_IN", "15m"))
+	lowStockThreshold, _ := strconv.Atoi(getEnv("LOW_STOCK_THRESHOLD", "5"))
+	outboxPollInterval, _ := time.ParseDuration(getEnv("OUTBOX_POLL_INTERVAL", "1s"))
+	outboxBatchSize, _ := strconv.Atoi(getEnv("OUTBOX_BATCH_SIZE", "100"))
+	outboxMaxAttempts, _ := strconv.Atoi(getEnv("OUTBOX_MAX_ATTEMPTS", "10"))
+	outboxRetryBackoff, _ := time.ParseDuration(getEnv("OUTBOX_RETRY_BACKOFF", "1s"))
+	outboxMaxRetryBackoff, _ := time.ParseDuration(getEnv("OUTBOX_MAX_RETRY_BACKOFF", "5m"))
+	outboxRetention, _ := time.ParseDuration(getEnv("OUTBOX_RETENTION", "168h"))
+	eventMaxRetries, _ := strconv.Atoi(getEnv("EVENT_MAX_RETRIES", "5"))
+	eventRetryBackoff, _ := time.ParseDuration(getEnv("EVENT_RETRY_BACKOFF", "10s"))
+	eventMaxRetryBackoff, _ := time.ParseDuration(getEnv("EVENT_MAX_RETRY_BACKOFF", "15m"))
+	eventHandlerTimeout, _ := time.ParseDuration(getEnv("EVENT_HANDLER_TIMEOUT", "30s"))
 
 	return &Config{
 		Server: ServerConfig{
-			Port:    getEnv("PORT", "8080"),
-			GinMode: getEnv("GIN_MODE", "debug"),
+			Port:           getEnv("PORT", "8080"),
+			GinMode:        getEnv("GIN_MODE", "debug"),
+			AppURL:         getEnv("APP_URL", "http://localhost:8080"),
+			TrustedProxies: getEnvList("TRUSTED_PROXIES"),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -90,16 +210,65 @@ func Load() (*Config, error) {
 			S3Endpoint:      getEnv("AWS_S3_ENDPOINT", "http://localhost:4566"),
 			SQSQueueURL:     getEnv("AWS_SQS_QUEUE_URL", "http://localhost:4566/000000000000/ecommerce-events"),
 			EventQueueName:  getEnv("AWS_EVENT_QUEUE_NAME", "ecommerce-events"),
+			EventDLQName:    getEnv("AWS_EVENT_DLQ_NAME", "ecommerce-events-dlq"),
+		},
+		Events: EventsConfig{
+			Transport:             getEnv("EVENT_TRANSPORT", "sqs"),
+			Producer:              getEnv("EVENT_PRODUCER", "api"),
+			OutboxPollInterval:    outboxPollInterval,
+			OutboxBatchSize:       outboxBatchSize,
+			OutboxMaxAttempts:     outboxMaxAttempts,
+			OutboxRetryBackoff:    outboxRetryBackoff,
+			OutboxMaxRetryBackoff: outboxMaxRetryBackoff,
+			OutboxRetention:       outboxRetention,
+			MaxRetries:            eventMaxRetries,
+			RetryBackoff:          eventRetryBackoff,
+			MaxRetryBackoff:       eventMaxRetryBackoff,
+			HandlerTimeout:        eventHandlerTimeout,
 		},
 		JWT: JWTConfig{
-			Secret:                getEnv("JWT_SECRET", "secret"),
+			KeysDir:               getEnv("JWT_KEYS_DIR", "./keys"),
+			SigningKeyID:          getEnv("JWT_SIGNING_KEY_ID", ""),
+			Issuer:                getEnv("JWT_ISSUER", getEnv("APP_URL", "http://localhost:8080")),
+			Audience:              getEnv("JWT_AUDIENCE", "ecommerce-api"),
 			ExpiresIn:             jwtExpiresIn,
 			RefreshTokenExpiresIn: refreshTokenExpiresIn,
 		},
+		Auth: AuthConfig{
+			EmailVerificationTokenTTL:       emailVerificationTokenTTL,
+			RequireVerifiedEmailForCheckout: requireVerifiedEmailForCheckout,
+			PasswordResetTokenTTL:           passwordResetTokenTTL,
+			PasswordResetURL:                getEnv("PASSWORD_RESET_URL", "http://localhost:3000/reset-password"),
+			SessionCacheTTL:                 sessionCacheTTL,
+			LoginFailureWindow:              loginFailureWindow,
+			LoginFreeFailures:               loginFreeFailures,
+			LoginBaseDelay:                  loginBaseDelay,
+			LoginMaxFailures:                loginMaxFailures,
+			LoginLockoutDuration:            loginLockoutDuration,
+			LoginIPMaxFailures:              loginIPMaxFailures,
+			MFAIssuer:                       getEnv("MFA_ISSUER", "E-Commerce Shop"),
+			MFASecretKey:                    getEnv("MFA_SECRET_KEY", ""),
+			MFAChallengeTTL:                 mfaChallengeTTL,
+			RequireMFAForAdmin:              requireMFAForAdmin,
+			PermissionCacheTTL:              permissionCacheTTL,
+		},
+		OIDC: OIDCConfig{
+			Providers: loadOIDCProviders(getEnv("APP_URL", "http://localhost:8080")),
+			StateTTL:  oidcStateTTL,
+		},
 		Upload: UploadConfig{
-			Path:          getEnv("UPLOAD_PATH", "./uploads"),
-			MaxUploadSize: maxUploadSize,
-			Provider:      getEnv("UPLOAD_PROVIDER", "local"),
+			Path:                getEnv("UPLOAD_PATH", "./uploads"),
+			MaxUploadSize:       maxUploadSize,
+			Provider:            getEnv("UPLOAD_PROVIDER", "local"),
+			MinImageDimension:   minImageDimension,
+			MaxImageDimension:   maxImageDimension,
+			MaxImagesPerProduct: maxImagesPerProduct,
+			SigningSecret:       getEnv("UPLOAD_SIGNING_SECRET", ""),
+			PresignExpiresIn:    presignExpiresIn,
+			CDNBaseURL:          getEnv("CDN_BASE_URL", ""),
+		},
+		Inventory: InventoryConfig{
+			LowStockThreshold: lowStockThreshold,
 		},
 		SMTP: SMTPConfig{
 			Host:     getEnv("SMTP_HOST", "localhost"),
@@ -111,9 +280,42 @@ func Load() (*Config, error) {
 	}, nil
 }
 
+func loadOIDCProviders(appURL string) []OIDCProviderConfig {
+	var providers []OIDCProviderConfig
+	for _, name := range strings.Split(getEnv("OIDC_PROVIDERS", ""), ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(name) + "_"
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    getEnv(prefix+"ISSUER_URL", ""),
+			ClientID:     getEnv(prefix+"CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"CLIENT_SECRET", ""),
+			RedirectURL:  getEnv(prefix+"REDIRECT_URL", strings.TrimRight(appURL, "/")+"/api/v1/auth/oidc/"+name+"/callback"),
+			Scopes:       strings.Fields(getEnv(prefix+"SCOPES", "")),
+		})
+	}
+	return providers
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return fallback
 }
+
+// getEnvList splits a comma-separated variable, skipping empty items. It
+// returns nil when the variable is unset or empty.
+func getEnvList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
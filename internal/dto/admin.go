@@ -0,0 +1,30 @@
+package dto
+
+// UserFilter narrows the user listing. Search matches part of the email or
+// name.
+type UserFilter struct {
+	Search   string
+	Role     string
+	IsActive *bool
+}
+
+type DeactivateUserRequest struct {
+	Reason string `json:"reason" binding:"required,max=500"`
+}
+
+// AuditLogFilter narrows the audit log to a user, an actor or an action.
+type AuditLogFilter struct {
+	UserID  uint
+	ActorID uint
+	Action  string
+}
+
+type AuditLogResponse struct {
+	ID         uint           `json:"id"`
+	Action     string         `json:"action"`
+	UserID     uint           `json:"user_id"`
+	ActorID    uint           `json:"actor_id"`
+	ActorEmail string         `json:"actor_email"`
+	Details    map[string]any `json:"details"`
+	CreatedAt  string         `json:"created_at"`
+}
@@ -1,14 +1,17 @@
 package dto
 
 type UserResponse struct {
-	ID        uint   `json:"id"`
-	Email     string `json:"email"`
-	FirstName string `json:"first_name"`
-	LastName  string `json:"last_name"`
-	Phone     string `json:"phone"`
-	IsActive  bool   `json:"is_active"`
-	Role      string `json:"role"`
-	CreatedAt string `json:"created_at"`
+	ID            uint   `json:"id"`
+	Email         string `json:"email"`
+	FirstName     string `json:"first_name"`
+	LastName      string `json:"last_name"`
+	Phone         string `json:"phone"`
+	IsActive      bool   `json:"is_active"`
+	Role          string `json:"role"`
+	Locale        string `json:"locale"`
+	EmailVerified bool   `json:"email_verified"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
+	CreatedAt     string `json:"created_at"`
 }
 
 type RegisterRequest struct {
@@ -17,6 +20,7 @@ type RegisterRequest struct {
 	FirstName string `json:"first_name" binding:"required"`
 	LastName  string `json:"last_name" binding:"required"`
 	Phone     string `json:"phone" binding:"required"`
+	Locale    string `json:"locale" binding:"omitempty,oneof=en fr"`
 }
 
 type LoginRequest struct {
@@ -32,6 +36,25 @@ type UpdateProfileRequest struct {
 	FirstName string `json:"first_name" binding:"omitempty"`
 	LastName  string `json:"last_name" binding:"omitempty"`
 	Phone     string `json:"phone" binding:"omitempty"`
+	Locale    string `json:"locale" binding:"omitempty,oneof=en fr"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
 
 type AuthResponse struct {
@@ -0,0 +1,26 @@
+package dto
+
+import "time"
+
+// OIDCProvidersResponse lists the identity providers users can sign in with.
+type OIDCProvidersResponse struct {
+	Providers []string `json:"providers"`
+}
+
+// OIDCLogin is a login started with an identity provider: the user is sent
+// to AuthorizationURL, and the browser has to present State again when it
+// comes back.
+type OIDCLogin struct {
+	AuthorizationURL string
+	State            string
+	ExpiresAt        time.Time
+}
+
+// OIDCCallbackRequest is what an identity provider sends the user back
+// with: a code, or an error when the login was refused.
+type OIDCCallbackRequest struct {
+	State            string `form:"state" binding:"required"`
+	Code             string `form:"code"`
+	Error            string `form:"error"`
+	ErrorDescription string `form:"error_description"`
+}
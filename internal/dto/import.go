@@ -0,0 +1,25 @@
+package dto
+
+type ImportJobResponse struct {
+	ID            uint                     `json:"id"`
+	Status        string                   `json:"status"`
+	FileName      string                   `json:"file_name"`
+	TotalRows     int                      `json:"total_rows"`
+	ProcessedRows int                      `json:"processed_rows"`
+	Progress      float64                  `json:"progress"`
+	CreatedCount  int                      `json:"created_count"`
+	UpdatedCount  int                      `json:"updated_count"`
+	FailedCount   int                      `json:"failed_count"`
+	Errors        []ImportRowErrorResponse `json:"errors"`
+	Message       string                   `json:"message,omitempty"`
+	StartedAt     string                   `json:"started_at,omitempty"`
+	FinishedAt    string                   `json:"finished_at,omitempty"`
+	CreatedAt     string                   `json:"created_at"`
+}
+
+type ImportRowErrorResponse struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
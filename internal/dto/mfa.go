@@ -0,0 +1,48 @@
+package dto
+
+type MFAStatusResponse struct {
+	Enabled           bool   `json:"enabled"`
+	Required          bool   `json:"required"`
+	RecoveryCodesLeft int64  `json:"recovery_codes_left"`
+	EnabledAt         string `json:"enabled_at,omitempty"`
+}
+
+// MFAEnrollmentResponse holds the secret to add to an authenticator app,
+// either typed in or scanned from the otpauth URI as a QR code.
+type MFAEnrollmentResponse struct {
+	Secret     string `json:"secret"`
+	OTPAuthURI string `json:"otpauth_uri"`
+}
+
+// MFACodeRequest carries an authenticator code. Where noted, a recovery code
+// is accepted too.
+type MFACodeRequest struct {
+	Code string `json:"code" binding:"required"`
+}
+
+type DisableMFARequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+// MFARecoveryCodesResponse lists recovery codes. They are only shown once.
+type MFARecoveryCodesResponse struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
+
+type MFAVerifyRequest struct {
+	MFAToken string `json:"mfa_token" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+}
+
+type MFAChallengeResponse struct {
+	MFAToken  string `json:"mfa_token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// LoginResponse holds the tokens, or only an MFA challenge when the account
+// has two-factor authentication. The tokens then come from /auth/mfa/verify.
+type LoginResponse struct {
+	*AuthResponse
+	MFA *MFAChallengeResponse `json:"mfa,omitempty"`
+}
@@ -38,3 +38,7 @@ type OrderItemResponse struct {
 	Price    float64         `json:"price"`
 	Product  ProductResponse `json:"product"`
 }
+
+type UpdateOrderStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=confirmed shipped delivered cancelled"`
+}
@@ -19,42 +19,132 @@ type CategoryResponse struct {
 	CreatedAt   string `json:"created_at"`
 }
 
+type CreateCategoryAttributeRequest struct {
+	Key          string   `json:"key" binding:"required"`
+	Label        string   `json:"label" binding:"required"`
+	Type         string   `json:"type" binding:"required,oneof=text number enum boolean"`
+	Unit         string   `json:"unit" binding:"omitempty"`
+	Options      []string `json:"options" binding:"omitempty"`
+	IsRequired   bool     `json:"is_required" binding:"omitempty"`
+	IsFilterable bool     `json:"is_filterable" binding:"omitempty"`
+	Position     int      `json:"position" binding:"omitempty"`
+}
+
+type UpdateCategoryAttributeRequest struct {
+	Label        string   `json:"label" binding:"omitempty"`
+	Unit         string   `json:"unit" binding:"omitempty"`
+	Options      []string `json:"options" binding:"omitempty"`
+	IsRequired   bool     `json:"is_required" binding:"omitempty"`
+	IsFilterable bool     `json:"is_filterable" binding:"omitempty"`
+	Position     int      `json:"position" binding:"omitempty"`
+}
+
+type CategoryAttributeResponse struct {
+	ID           uint     `json:"id"`
+	CategoryID   uint     `json:"category_id"`
+	Key          string   `json:"key"`
+	Label        string   `json:"label"`
+	Type         string   `json:"type"`
+	Unit         string   `json:"unit"`
+	Options      []string `json:"options"`
+	IsRequired   bool     `json:"is_required"`
+	IsFilterable bool     `json:"is_filterable"`
+	Position     int      `json:"position"`
+}
+
 type CreateProductRequest struct {
-	Name        string  `json:"name" binding:"required"`
-	CategoryID  uint    `json:"category_id" binding:"required"`
-	Description string  `json:"description" binding:"required"`
-	Price       float64 `json:"price" binding:"required"`
-	Stock       int     `json:"stock" binding:"required"`
-	SKU         string  `json:"sku" binding:"required"`
+	Name        string         `json:"name" binding:"required"`
+	CategoryID  uint           `json:"category_id" binding:"required"`
+	Description string         `json:"description" binding:"required"`
+	Price       float64        `json:"price" binding:"required"`
+	Stock       int            `json:"stock" binding:"required"`
+	SKU         string         `json:"sku" binding:"required"`
+	Attributes  map[string]any `json:"attributes" binding:"omitempty"`
 }
 
 type UpdateProductRequest struct {
-	CategoryID  uint    `json:"category_id" binding:"omitempty"`
-	Name        string  `json:"name" binding:"omitempty"`
-	Description string  `json:"description" binding:"omitempty"`
-	Price       float64 `json:"price" binding:"omitempty"`
-	Stock       int     `json:"stock" binding:"omitempty"`
-	SKU         string  `json:"sku" binding:"omitempty"`
-	IsActive    bool    `json:"is_active" binding:"omitempty"`
+	CategoryID  uint           `json:"category_id" binding:"omitempty"`
+	Name        string         `json:"name" binding:"omitempty"`
+	Description string         `json:"description" binding:"omitempty"`
+	Price       float64        `json:"price" binding:"omitempty"`
+	Stock       int            `json:"stock" binding:"omitempty"`
+	SKU         string         `json:"sku" binding:"omitempty"`
+	IsActive    bool           `json:"is_active" binding:"omitempty"`
+	Attributes  map[string]any `json:"attributes" binding:"omitempty"`
+}
+
+// ProductFilter narrows the product listing. Attributes matches exact values,
+// AttributeMin and AttributeMax bound numeric attributes. Attribute filters
+// need a category: the service checks them against its schema and fills
+// AttributeMatches with the values typed as they are stored.
+type ProductFilter struct {
+	CategoryID       uint
+	Attributes       map[string]string
+	AttributeMin     map[string]float64
+	AttributeMax     map[string]float64
+	AttributeMatches map[string]any
 }
 
 type ProductResponse struct {
-	ID          uint                   `json:"id"`
-	CategoryID  uint                   `json:"category_id"`
-	Name        string                 `json:"name"`
-	Description string                 `json:"description"`
-	Price       float64                `json:"price"`
-	Stock       int                    `json:"stock"`
-	SKU         string                 `json:"sku"`
-	IsActive    bool                   `json:"is_active"`
-	Category    CategoryResponse       `json:"category"`
-	Images      []ProductImageResponse `json:"images"`
-	UpdatedAt   string                 `json:"updated_at"`
+	ID             uint                           `json:"id"`
+	CategoryID     uint                           `json:"category_id"`
+	Name           string                         `json:"name"`
+	Description    string                         `json:"description"`
+	Price          float64                        `json:"price"`
+	Stock          int                            `json:"stock"`
+	SKU            string                         `json:"sku"`
+	IsActive       bool                           `json:"is_active"`
+	Attributes     map[string]any                 `json:"attributes"`
+	Specifications []ProductSpecificationResponse `json:"specifications"`
+	AverageRating  float64                        `json:"average_rating"`
+	ReviewCount    int                            `json:"review_count"`
+	Category       CategoryResponse               `json:"category"`
+	Images         []ProductImageResponse         `json:"images"`
+	UpdatedAt      string                         `json:"updated_at"`
+}
+
+type ProductSpecificationResponse struct {
+	Key   string `json:"key"`
+	Label string `json:"label"`
+	Value any    `json:"value"`
+	Unit  string `json:"unit,omitempty"`
 }
 
 type ProductImageResponse struct {
-	ID        uint   `json:"id"`
-	URL       string `json:"url"`
-	AltText   string `json:"alt_text"`
-	IsPrimary bool   `json:"is_primary"`
+	ID           uint   `json:"id"`
+	URL          string `json:"url"`
+	ThumbnailURL string `json:"thumbnail_url"`
+	MediumURL    string `json:"medium_url"`
+	LargeURL     string `json:"large_url"`
+	Width        int    `json:"width"`
+	Height       int    `json:"height"`
+	AltText      string `json:"alt_text"`
+	IsPrimary    bool   `json:"is_primary"`
+	Position     int    `json:"position"`
+}
+
+type UpdateProductImageRequest struct {
+	AltText string `json:"alt_text" binding:"max=255"`
+}
+
+type ProductImageUploadURLRequest struct {
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png image/gif image/webp"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+type ProductImageUploadURLResponse struct {
+	Key       string            `json:"key"`
+	URL       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt string            `json:"expires_at"`
+}
+
+type ConfirmProductImageUploadRequest struct {
+	Key     string `json:"key" binding:"required"`
+	AltText string `json:"alt_text" binding:"max=255"`
+}
+
+type ReorderProductImagesRequest struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1"`
 }
@@ -0,0 +1,32 @@
+package dto
+
+type CreateReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Title  string `json:"title" binding:"omitempty,max=255"`
+	Body   string `json:"body" binding:"required"`
+}
+
+type UpdateReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5"`
+	Title  string `json:"title" binding:"omitempty,max=255"`
+	Body   string `json:"body" binding:"required"`
+}
+
+type ModerateReviewRequest struct {
+	Status string `json:"status" binding:"required,oneof=approved rejected"`
+	Note   string `json:"note" binding:"omitempty"`
+}
+
+type ReviewResponse struct {
+	ID             uint   `json:"id"`
+	ProductID      uint   `json:"product_id"`
+	UserID         uint   `json:"user_id"`
+	AuthorName     string `json:"author_name"`
+	Rating         int    `json:"rating"`
+	Title          string `json:"title"`
+	Body           string `json:"body"`
+	Status         string `json:"status"`
+	ModerationNote string `json:"moderation_note,omitempty"`
+	CreatedAt      string `json:"created_at"`
+	UpdatedAt      string `json:"updated_at"`
+}
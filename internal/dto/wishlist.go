@@ -0,0 +1,31 @@
+package dto
+
+type AddToWishlistRequest struct {
+	ProductID         uint  `json:"product_id" binding:"required"`
+	NotifyBackInStock *bool `json:"notify_back_in_stock"`
+	NotifyPriceDrop   *bool `json:"notify_price_drop"`
+}
+
+type UpdateWishlistItemRequest struct {
+	NotifyBackInStock *bool `json:"notify_back_in_stock"`
+	NotifyPriceDrop   *bool `json:"notify_price_drop"`
+}
+
+type WishlistItemResponse struct {
+	ID                uint                    `json:"id"`
+	ProductID         uint                    `json:"product_id"`
+	Product           WishlistProductResponse `json:"product"`
+	NotifyBackInStock bool                    `json:"notify_back_in_stock"`
+	NotifyPriceDrop   bool                    `json:"notify_price_drop"`
+	CreatedAt         string                  `json:"created_at"`
+}
+
+type WishlistProductResponse struct {
+	Name     string  `json:"name"`
+	SKU      string  `json:"sku"`
+	Price    float64 `json:"price"`
+	Stock    int     `json:"stock"`
+	IsActive bool    `json:"is_active"`
+	Category string  `json:"category"`
+	ImageURL string  `json:"image_url,omitempty"`
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+
+	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
+)
+
+// Metadata keys the consumer adds when it retries or dead-letters a message.
+const (
+	RetryCountMetadataKey       = "retry_count"
+	DelaySecondsMetadataKey     = "delay_seconds"
+	DeadLetterReasonMetadataKey = "dead_letter_reason"
+	DeadLetteredAtMetadataKey   = "dead_lettered_at"
+)
+
+// Consumer feeds messages from the event queue to a router. A failed message
+// is published again with its retry count raised and a growing delay; once
+// the retries are used up, or the error is permanent, it is moved to the
+// dead-letter queue.
+type Consumer struct {
+	subscriber message.Subscriber
+	publisher  message.Publisher
+	router     *Router
+	cfg        *appConfig.Config
+	log        *zerolog.Logger
+}
+
+func NewConsumer(subscriber message.Subscriber, publisher message.Publisher, router *Router, cfg *appConfig.Config, log *zerolog.Logger) *Consumer {
+	return &Consumer{
+		subscriber: subscriber,
+		publisher:  publisher,
+		router:     router,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Run consumes the event queue until ctx is cancelled.
+func (c *Consumer) Run(ctx context.Context) error {
+	messages, err := c.subscriber.Subscribe(ctx, c.cfg.AWS.EventQueueName)
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return nil
+			}
+			c.handle(ctx, msg)
+		}
+	}
+}
+
+func (c *Consumer) handle(ctx context.Context, msg *message.Message) {
+	err := c.router.Process(ctx, msg)
+	switch {
+	case err == nil:
+		msg.Ack()
+		return
+	case errors.Is(err, ErrNoHandler):
+		c.log.Debug().Str("event_type", msg.Metadata.Get(EventTypeMetadataKey)).Msg("Skipping event without handler")
+		msg.Ack()
+		return
+	}
+
+	retries, _ := strconv.Atoi(msg.Metadata.Get(RetryCountMetadataKey))
+
+	if IsPermanent(err) || retries >= c.cfg.Events.MaxRetries {
+		if dlqErr := c.deadLetter(msg, err); dlqErr != nil {
+			c.log.Error().Err(dlqErr).Str("message_id", msg.UUID).Msg("Failed to dead-letter message")
+			msg.Nack()
+			return
+		}
+		c.log.Warn().Err(err).Str("message_id", msg.UUID).Int("retries", retries).Msg("Moved message to dead-letter queue")
+		msg.Ack()
+		return
+	}
+
+	if retryErr := c.retry(msg, retries+1); retryErr != nil {
+		c.log.Error().Err(retryErr).Str("message_id", msg.UUID).Msg("Failed to schedule retry")
+		msg.Nack()
+		return
+	}
+	msg.Ack()
+}
+
+func (c *Consumer) retry(msg *message.Message, retries int) error {
+	delay := backoff(c.cfg.Events.RetryBackoff, c.cfg.Events.MaxRetryBackoff, retries)
+
+	retry := msg.Copy()
+	retry.Metadata.Set(RetryCountMetadataKey, strconv.Itoa(retries))
+	retry.Metadata.Set(DelaySecondsMetadataKey, strconv.Itoa(int(delay.Seconds())))
+
+	return c.publisher.Publish(c.cfg.AWS.EventQueueName, retry)
+}
+
+func (c *Consumer) deadLetter(msg *message.Message, cause error) error {
+	dead := msg.Copy()
+	delete(dead.Metadata, DelaySecondsMetadataKey)
+	dead.Metadata.Set(DeadLetterReasonMetadataKey, cause.Error())
+	dead.Metadata.Set(DeadLetteredAtMetadataKey, time.Now().UTC().Format(time.RFC3339))
+
+	return c.publisher.Publish(c.cfg.AWS.EventDLQName, dead)
+}
+
+// Replay returns a copy of a dead-lettered message ready to be published to
+// the event queue again, with its retry state cleared.
+func Replay(msg *message.Message) *message.Message {
+	replay := msg.Copy()
+	delete(replay.Metadata, RetryCountMetadataKey)
+	delete(replay.Metadata, DelaySecondsMetadataKey)
+	delete(replay.Metadata, DeadLetterReasonMetadataKey)
+	delete(replay.Metadata, DeadLetteredAtMetadataKey)
+	return replay
+}
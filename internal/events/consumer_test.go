@@ -0,0 +1,301 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+	"github.com/rs/zerolog"
+
+	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
+)
+
+const (
+	testQueue = "events"
+	testDLQ   = "events-dlq"
+)
+
+var errHandlerFailed = errors.New("handler failed")
+
+func newTestConfig() *appConfig.Config {
+	cfg := &appConfig.Config{}
+	cfg.AWS.EventQueueName = testQueue
+	cfg.AWS.EventDLQName = testDLQ
+	cfg.Events.MaxRetries = 2
+	cfg.Events.RetryBackoff = time.Second
+	cfg.Events.MaxRetryBackoff = time.Minute
+	return cfg
+}
+
+// newTestPubSub returns the channel transport of the memory event transport,
+// made persistent so a subscriber that starts after a publish still gets the
+// message.
+func newTestPubSub(t *testing.T) *gochannel.GoChannel {
+	t.Helper()
+
+	pubSub := gochannel.NewGoChannel(gochannel.Config{
+		OutputChannelBuffer: 64,
+		Persistent:          true,
+	}, watermill.NopLogger{})
+	t.Cleanup(func() { _ = pubSub.Close() })
+	return pubSub
+}
+
+// startConsumer runs a consumer for router until the test ends.
+func startConsumer(t *testing.T, pubSub *gochannel.GoChannel, router *Router) {
+	t.Helper()
+
+	log := zerolog.Nop()
+	consumer := NewConsumer(pubSub, pubSub, router, newTestConfig(), &log)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- consumer.Run(ctx) }()
+
+	t.Cleanup(func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("consumer: %v", err)
+		}
+	})
+}
+
+func subscribe(t *testing.T, pubSub *gochannel.GoChannel, topic string) <-chan *message.Message {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	messages, err := pubSub.Subscribe(ctx, topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return messages
+}
+
+// receive acknowledges and returns the next message.
+func receive(t *testing.T, messages <-chan *message.Message) *message.Message {
+	t.Helper()
+
+	select {
+	case msg := <-messages:
+		msg.Ack()
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message received")
+		return nil
+	}
+}
+
+func newTestMessage(t *testing.T) *message.Message {
+	t.Helper()
+
+	envelope, err := NewEnvelope("test", &UserLoggedIn{UserID: 7, Email: "jane@example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	msg := message.NewMessage(watermill.NewUUID(), payload)
+	for key, value := range envelope.Metadata() {
+		msg.Metadata.Set(key, value)
+	}
+	return msg
+}
+
+func publish(t *testing.T, pubSub *gochannel.GoChannel, msg *message.Message) {
+	t.Helper()
+
+	if err := pubSub.Publish(testQueue, msg); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// countingRouter handles UserLoggedIn with handler and counts its calls.
+func countingRouter(handler Handler) (*Router, *atomic.Int32) {
+	calls := &atomic.Int32{}
+	router := NewRouter()
+	router.Handle(UserLoggedInEventType, func(ctx context.Context, envelope *Envelope, event Event) error {
+		calls.Add(1)
+		return handler(ctx, envelope, event)
+	})
+	return router, calls
+}
+
+func TestConsumerRetriesThenDeadLetters(t *testing.T) {
+	pubSub := newTestPubSub(t)
+	router, calls := countingRouter(func(context.Context, *Envelope, Event) error {
+		return errHandlerFailed
+	})
+	queue := subscribe(t, pubSub, testQueue)
+	dlq := subscribe(t, pubSub, testDLQ)
+	startConsumer(t, pubSub, router)
+
+	msg := newTestMessage(t)
+	publish(t, pubSub, msg)
+
+	// The original, then one retry per allowed retry with a doubling delay.
+	wantRetries := []struct{ count, delay string }{{"", ""}, {"1", "1"}, {"2", "2"}}
+	for _, want := range wantRetries {
+		got := receive(t, queue)
+		if got.Metadata.Get(RetryCountMetadataKey) != want.count || got.Metadata.Get(DelaySecondsMetadataKey) != want.delay {
+			t.Errorf("retry_count %q, delay_seconds %q, want %q and %q",
+				got.Metadata.Get(RetryCountMetadataKey), got.Metadata.Get(DelaySecondsMetadataKey), want.count, want.delay)
+		}
+	}
+
+	dead := receive(t, dlq)
+	if calls.Load() != 3 {
+		t.Errorf("handler called %d times, want 3", calls.Load())
+	}
+	if string(dead.Payload) != string(msg.Payload) {
+		t.Error("dead-lettered payload differs from the original")
+	}
+	if dead.Metadata.Get(RetryCountMetadataKey) != "2" {
+		t.Errorf("retry_count = %q, want 2", dead.Metadata.Get(RetryCountMetadataKey))
+	}
+	if dead.Metadata.Get(DeadLetterReasonMetadataKey) != errHandlerFailed.Error() {
+		t.Errorf("dead_letter_reason = %q", dead.Metadata.Get(DeadLetterReasonMetadataKey))
+	}
+	if dead.Metadata.Get(DeadLetteredAtMetadataKey) == "" || dead.Metadata.Get(DelaySecondsMetadataKey) != "" {
+		t.Errorf("unexpected dead-letter metadata %v", dead.Metadata)
+	}
+}
+
+func TestConsumerDeadLettersPermanentErrorsAtOnce(t *testing.T) {
+	pubSub := newTestPubSub(t)
+	router, calls := countingRouter(func(context.Context, *Envelope, Event) error {
+		return Permanent(errHandlerFailed)
+	})
+	dlq := subscribe(t, pubSub, testDLQ)
+	startConsumer(t, pubSub, router)
+
+	publish(t, pubSub, newTestMessage(t))
+
+	dead := receive(t, dlq)
+	if calls.Load() != 1 {
+		t.Errorf("handler called %d times, want 1", calls.Load())
+	}
+	if dead.Metadata.Get(RetryCountMetadataKey) != "" {
+		t.Errorf("permanent failure was retried %s times", dead.Metadata.Get(RetryCountMetadataKey))
+	}
+}
+
+func TestConsumerDeadLettersUndecodableMessages(t *testing.T) {
+	pubSub := newTestPubSub(t)
+	router, calls := countingRouter(func(context.Context, *Envelope, Event) error { return nil })
+	dlq := subscribe(t, pubSub, testDLQ)
+	startConsumer(t, pubSub, router)
+
+	msg := message.NewMessage(watermill.NewUUID(), []byte("not json"))
+	msg.Metadata.Set(EventTypeMetadataKey, UserLoggedInEventType)
+	publish(t, pubSub, msg)
+
+	dead := receive(t, dlq)
+	if calls.Load() != 0 {
+		t.Errorf("handler called %d times, want 0", calls.Load())
+	}
+	if !strings.Contains(dead.Metadata.Get(DeadLetterReasonMetadataKey), ErrInvalidEnvelope.Error()) {
+		t.Errorf("dead_letter_reason = %q", dead.Metadata.Get(DeadLetterReasonMetadataKey))
+	}
+}
+
+func TestConsumerSkipsEventsWithoutHandler(t *testing.T) {
+	pubSub := newTestPubSub(t)
+	queue := subscribe(t, pubSub, testQueue)
+	dlq := subscribe(t, pubSub, testDLQ)
+	startConsumer(t, pubSub, NewRouter())
+
+	publish(t, pubSub, newTestMessage(t))
+	receive(t, queue)
+
+	select {
+	case msg := <-dlq:
+		t.Fatalf("message without handler was dead-lettered: %v", msg.Metadata)
+	case msg := <-queue:
+		t.Fatalf("message without handler was retried: %v", msg.Metadata)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestReplayedMessageIsHandledAfresh(t *testing.T) {
+	pubSub := newTestPubSub(t)
+	var failing atomic.Bool
+	failing.Store(true)
+	router, calls := countingRouter(func(context.Context, *Envelope, Event) error {
+		if failing.Load() {
+			return errHandlerFailed
+		}
+		return nil
+	})
+	queue := subscribe(t, pubSub, testQueue)
+	dlq := subscribe(t, pubSub, testDLQ)
+	startConsumer(t, pubSub, router)
+
+	publish(t, pubSub, newTestMessage(t))
+	dead := receive(t, dlq)
+	for range 3 {
+		receive(t, queue)
+	}
+
+	replay := Replay(dead)
+	for _, key := range []string{RetryCountMetadataKey, DelaySecondsMetadataKey, DeadLetterReasonMetadataKey, DeadLetteredAtMetadataKey} {
+		if replay.Metadata.Get(key) != "" {
+			t.Errorf("replay kept %s", key)
+		}
+	}
+	if replay.Metadata.Get(EventTypeMetadataKey) != UserLoggedInEventType {
+		t.Errorf("replay lost its event type: %v", replay.Metadata)
+	}
+
+	failing.Store(false)
+	publish(t, pubSub, replay)
+	receive(t, queue)
+
+	select {
+	case msg := <-dlq:
+		t.Fatalf("replayed message was dead-lettered again: %v", msg.Metadata)
+	case msg := <-queue:
+		t.Fatalf("replayed message was retried: %v", msg.Metadata)
+	case <-time.After(100 * time.Millisecond):
+	}
+	if calls.Load() != 4 {
+		t.Errorf("handler called %d times, want 3 failures and the replay", calls.Load())
+	}
+}
+
+// A handler that ignores its context is still running when Timeout gives
+// up. Retrying the message would run it a second time.
+func TestConsumerDoesNotRetryTimedOutHandlers(t *testing.T) {
+	pubSub := newTestPubSub(t)
+	release := make(chan struct{})
+	t.Cleanup(func() { close(release) })
+
+	router, calls := countingRouter(func(context.Context, *Envelope, Event) error {
+		<-release
+		return nil
+	})
+	router.Use(Timeout(10 * time.Millisecond))
+	dlq := subscribe(t, pubSub, testDLQ)
+	startConsumer(t, pubSub, router)
+
+	publish(t, pubSub, newTestMessage(t))
+
+	dead := receive(t, dlq)
+	if calls.Load() != 1 {
+		t.Errorf("handler started %d times, want 1", calls.Load())
+	}
+	if !strings.Contains(dead.Metadata.Get(DeadLetterReasonMetadataKey), ErrHandlerTimeout.Error()) {
+		t.Errorf("dead_letter_reason = %q", dead.Metadata.Get(DeadLetterReasonMetadataKey))
+	}
+}
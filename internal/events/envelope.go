@@ -0,0 +1,146 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill"
+)
+
+// Metadata keys set on every published message so consumers can route and
+// trace messages without decoding the payload. SQS allows ten attributes per
+// message, so anything else stays in the envelope.
+const (
+	EventTypeMetadataKey     = "event_type"
+	EventIDMetadataKey       = "event_id"
+	CorrelationIDMetadataKey = "correlation_id"
+	CausationIDMetadataKey   = "causation_id"
+)
+
+var (
+	ErrInvalidEnvelope = errors.New("invalid event envelope")
+	ErrInvalidEvent    = errors.New("invalid event")
+)
+
+// Event is a typed event payload. EventType and EventVersion must not depend
+// on the receiver's fields, the registry calls them on zero values.
+type Event interface {
+	EventType() string
+	EventVersion() int
+	Validate() error
+}
+
+// Envelope wraps every event published by the application. CorrelationID is
+// shared by all events caused by the same original action; CausationID is the
+// ID of the event that directly caused this one.
+type Envelope struct {
+	ID            string          `json:"id"`
+	Type          string          `json:"type"`
+	Version       int             `json:"version"`
+	OccurredAt    time.Time       `json:"occurred_at"`
+	Producer      string          `json:"producer"`
+	CorrelationID string          `json:"correlation_id"`
+	CausationID   string          `json:"causation_id,omitempty"`
+	Data          json.RawMessage `json:"data"`
+}
+
+type EnvelopeOption func(*Envelope)
+
+// WithCorrelationID ties the event to an existing correlation ID, for example
+// one taken from an incoming request.
+func WithCorrelationID(correlationID string) EnvelopeOption {
+	return func(e *Envelope) {
+		if correlationID != "" {
+			e.CorrelationID = correlationID
+		}
+	}
+}
+
+// CausedBy marks the event as a consequence of parent.
+func CausedBy(parent *Envelope) EnvelopeOption {
+	return func(e *Envelope) {
+		e.CorrelationID = parent.CorrelationID
+		e.CausationID = parent.ID
+	}
+}
+
+// NewEnvelope validates event against the registry and wraps it.
+func NewEnvelope(producer string, event Event, opts ...EnvelopeOption) (*Envelope, error) {
+	if err := DefaultRegistry.Check(event); err != nil {
+		return nil, err
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidEvent, event.EventType(), err)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+
+	id := watermill.NewUUID()
+	envelope := &Envelope{
+		ID:            id,
+		Type:          event.EventType(),
+		Version:       event.EventVersion(),
+		OccurredAt:    time.Now().UTC(),
+		Producer:      producer,
+		CorrelationID: id,
+		Data:          data,
+	}
+
+	for _, opt := range opts {
+		opt(envelope)
+	}
+
+	return envelope, nil
+}
+
+// Metadata returns the message metadata describing the envelope.
+func (e *Envelope) Metadata() map[string]string {
+	metadata := map[string]string{
+		EventTypeMetadataKey:     e.Type,
+		EventIDMetadataKey:       e.ID,
+		CorrelationIDMetadataKey: e.CorrelationID,
+	}
+	if e.CausationID != "" {
+		metadata[CausationIDMetadataKey] = e.CausationID
+	}
+	return metadata
+}
+
+// Decode parses a message payload into its envelope and typed event, and
+// checks both against the registry. eventType is the event_type metadata of
+// the message and must match the envelope.
+func Decode(eventType string, payload []byte) (*Envelope, Event, error) {
+	var envelope Envelope
+	if err := json.Unmarshal(payload, &envelope); err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidEnvelope, err)
+	}
+
+	if envelope.ID == "" || envelope.Type == "" || envelope.Version < 1 || len(envelope.Data) == 0 {
+		return nil, nil, fmt.Errorf("%w: missing id, type, version or data", ErrInvalidEnvelope)
+	}
+
+	if eventType != "" && eventType != envelope.Type {
+		return nil, nil, fmt.Errorf("%w: metadata event type %q does not match %q", ErrInvalidEnvelope, eventType, envelope.Type)
+	}
+
+	event, err := DefaultRegistry.New(envelope.Type, envelope.Version)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := json.Unmarshal(envelope.Data, event); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidEvent, envelope.Type, err)
+	}
+
+	if err := event.Validate(); err != nil {
+		return nil, nil, fmt.Errorf("%w: %s: %v", ErrInvalidEvent, envelope.Type, err)
+	}
+
+	return &envelope, event, nil
+}
@@ -0,0 +1,83 @@
+package events
+
+import (
+	"maps"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+)
+
+type PublishedMessage struct {
+	EventType string
+	Payload   []byte
+	Metadata  map[string]string
+}
+
+// MemoryPublisher records every published message. When it is given a
+// watermill publisher it also forwards the messages, so an in-process
+// subscriber receives them exactly as it would from SQS.
+type MemoryPublisher struct {
+	mu        sync.Mutex
+	messages  []PublishedMessage
+	publisher message.Publisher
+	queueName string
+}
+
+func NewMemoryPublisher(publisher message.Publisher, queueName string) *MemoryPublisher {
+	return &MemoryPublisher{
+		publisher: publisher,
+		queueName: queueName,
+	}
+}
+
+func (m *MemoryPublisher) Publish(eventType string, payload any, metadata map[string]string) error {
+	msg, err := newMessage(eventType, payload, metadata)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	m.messages = append(m.messages, PublishedMessage{
+		EventType: eventType,
+		Payload:   msg.Payload,
+		Metadata:  maps.Clone(map[string]string(msg.Metadata)),
+	})
+	m.mu.Unlock()
+
+	if m.publisher == nil {
+		return nil
+	}
+	return m.publisher.Publish(m.queueName, msg)
+}
+
+// Messages returns the messages published so far, oldest first.
+func (m *MemoryPublisher) Messages() []PublishedMessage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return append([]PublishedMessage(nil), m.messages...)
+}
+
+func (m *MemoryPublisher) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.messages = nil
+}
+
+// Close leaves the forwarding publisher open; it is shared with the
+// subscriber and closed by whoever created it.
+func (m *MemoryPublisher) Close() error {
+	return nil
+}
+
+// NewChannelPubSub creates a Go channel backed pub/sub. Messages only reach
+// subscribers in the same process that subscribed before they were published.
+func NewChannelPubSub() *gochannel.GoChannel {
+	return gochannel.NewGoChannel(gochannel.Config{
+		OutputChannelBuffer:            64,
+		BlockPublishUntilSubscriberAck: false,
+	}, watermill.NewStdLogger(false, false))
+}
@@ -0,0 +1,192 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+
+	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+)
+
+const (
+	// OutboxIDMetadataKey carries the outbox row id, which stays the same
+	// when a message is published more than once.
+	OutboxIDMetadataKey = "outbox_id"
+
+	outboxCleanupInterval     = time.Hour
+	defaultOutboxPollInterval = time.Second
+	defaultOutboxBatchSize    = 100
+)
+
+// NewOutboxMessage wraps event in an envelope and prepares it to be saved
+// with repository CreateOutboxMessagesTx alongside the change that raised it.
+func NewOutboxMessage(producer string, event Event, opts ...EnvelopeOption) (*models.OutboxMessage, error) {
+	envelope, err := NewEnvelope(producer, event, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.OutboxMessage{
+		EventType:   envelope.Type,
+		Payload:     string(data),
+		Metadata:    envelope.Metadata(),
+		Status:      models.OutboxStatusPending,
+		AvailableAt: envelope.OccurredAt,
+	}, nil
+}
+
+// Relay publishes pending outbox messages. Failed messages are retried with
+// exponential backoff and marked failed after the configured number of
+// attempts. Delivery is at least once: a message can be published again if
+// the relay stops between publishing it and recording that it did.
+type Relay struct {
+	outboxRepo repository.OutboxRepositoryInterface
+	publisher  PublisherInterface
+	cfg        *appConfig.EventsConfig
+	log        *zerolog.Logger
+}
+
+func NewRelay(db *gorm.DB, publisher PublisherInterface, cfg *appConfig.EventsConfig, log *zerolog.Logger) *Relay {
+	return &Relay{
+		outboxRepo: repository.NewOutboxRepo(db),
+		publisher:  publisher,
+		cfg:        cfg,
+		log:        log,
+	}
+}
+
+// Run polls the outbox until ctx is cancelled.
+func (r *Relay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.pollInterval())
+	defer ticker.Stop()
+
+	lastCleanup := time.Time{}
+
+	for {
+		r.drain(ctx)
+
+		if r.cfg.OutboxRetention > 0 && time.Since(lastCleanup) >= outboxCleanupInterval {
+			lastCleanup = time.Now()
+			deleted, err := r.outboxRepo.DeletePublishedOutboxMessages(time.Now().Add(-r.cfg.OutboxRetention))
+			if err != nil {
+				r.log.Error().Err(err).Msg("Failed to delete published outbox messages")
+			} else if deleted > 0 {
+				r.log.Info().Int64("deleted", deleted).Msg("Deleted published outbox messages")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// drain publishes batches until no message is due.
+func (r *Relay) drain(ctx context.Context) {
+	for ctx.Err() == nil {
+		messages, err := r.outboxRepo.ClaimOutboxMessages(r.batchSize(), r.lease())
+		if err != nil {
+			r.log.Error().Err(err).Msg("Failed to claim outbox messages")
+			return
+		}
+
+		for i := range messages {
+			r.publish(&messages[i])
+		}
+
+		if len(messages) < r.batchSize() {
+			return
+		}
+	}
+}
+
+func (r *Relay) publish(message *models.OutboxMessage) {
+	metadata := make(map[string]string, len(message.Metadata)+1)
+	for k, v := range message.Metadata {
+		metadata[k] = v
+	}
+	metadata[OutboxIDMetadataKey] = strconv.FormatUint(uint64(message.ID), 10)
+
+	// A message that no longer decodes would fail the same way on every
+	// attempt, so it is marked failed straight away.
+	if _, _, err := Decode(message.EventType, []byte(message.Payload)); err != nil {
+		message.Attempts++
+		message.LastError = err.Error()
+		message.Status = models.OutboxStatusFailed
+		r.log.Error().Err(err).Uint("outbox_id", message.ID).Str("event_type", message.EventType).Msg("Refusing to publish invalid outbox message")
+
+		if err := r.outboxRepo.MarkOutboxMessageFailed(message); err != nil {
+			r.log.Error().Err(err).Uint("outbox_id", message.ID).Msg("Failed to record outbox publish failure")
+		}
+		return
+	}
+
+	err := r.publisher.Publish(message.EventType, json.RawMessage(message.Payload), metadata)
+	if err == nil {
+		if err := r.outboxRepo.MarkOutboxMessagePublished(message.ID); err != nil {
+			r.log.Error().Err(err).Uint("outbox_id", message.ID).Msg("Failed to mark outbox message published")
+		}
+		return
+	}
+
+	message.Attempts++
+	message.LastError = err.Error()
+	message.AvailableAt = time.Now().Add(backoff(r.cfg.OutboxRetryBackoff, r.cfg.OutboxMaxRetryBackoff, message.Attempts))
+	if message.Attempts >= r.cfg.OutboxMaxAttempts {
+		message.Status = models.OutboxStatusFailed
+	}
+
+	event := r.log.Warn()
+	if message.Status == models.OutboxStatusFailed {
+		event = r.log.Error()
+	}
+	event.Err(err).
+		Uint("outbox_id", message.ID).
+		Str("event_type", message.EventType).
+		Int("attempts", message.Attempts).
+		Msg("Failed to publish outbox message")
+
+	if err := r.outboxRepo.MarkOutboxMessageFailed(message); err != nil {
+		r.log.Error().Err(err).Uint("outbox_id", message.ID).Msg("Failed to record outbox publish failure")
+	}
+}
+
+// backoff doubles base for every attempt after the first, up to limit.
+func backoff(base, limit time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < limit; i++ {
+		delay *= 2
+	}
+	return min(delay, limit)
+}
+
+// lease is how long claimed messages stay hidden from other relays.
+func (r *Relay) lease() time.Duration {
+	return max(time.Minute, 10*r.pollInterval())
+}
+
+func (r *Relay) pollInterval() time.Duration {
+	if r.cfg.OutboxPollInterval <= 0 {
+		return defaultOutboxPollInterval
+	}
+	return r.cfg.OutboxPollInterval
+}
+
+func (r *Relay) batchSize() int {
+	if r.cfg.OutboxBatchSize <= 0 {
+		return defaultOutboxBatchSize
+	}
+	return r.cfg.OutboxBatchSize
+}
@@ -0,0 +1,99 @@
+package events
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	ErrUnknownEventType        = errors.New("unknown event type")
+	ErrUnsupportedEventVersion = errors.New("unsupported event version")
+)
+
+type registryKey struct {
+	eventType string
+	version   int
+}
+
+// Registry maps an event type and version to the Go type carrying it. A new
+// version of an event gets its own struct and registration, and consumers
+// keep handling older versions until no producer sends them.
+type Registry struct {
+	mu    sync.RWMutex
+	types map[registryKey]reflect.Type
+}
+
+// DefaultRegistry holds every event the application publishes.
+var DefaultRegistry = NewRegistry()
+
+func NewRegistry() *Registry {
+	return &Registry{
+		types: map[registryKey]reflect.Type{},
+	}
+}
+
+// Register adds the type of event, which must be a pointer to a struct.
+// Registering the same type and version twice panics.
+func (r *Registry) Register(event Event) {
+	t := reflect.TypeOf(event)
+	if t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Struct {
+		panic(fmt.Sprintf("events: %T must be a pointer to a struct", event))
+	}
+
+	key := registryKey{eventType: event.EventType(), version: event.EventVersion()}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.types[key]; ok {
+		panic(fmt.Sprintf("events: %s version %d registered twice", key.eventType, key.version))
+	}
+	r.types[key] = t.Elem()
+}
+
+// New returns a new zero event for the type and version.
+func (r *Registry) New(eventType string, version int) (Event, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	t, ok := r.types[registryKey{eventType: eventType, version: version}]
+	if ok {
+		return reflect.New(t).Interface().(Event), nil
+	}
+
+	for key := range r.types {
+		if key.eventType == eventType {
+			return nil, fmt.Errorf("%w: %s version %d", ErrUnsupportedEventVersion, eventType, version)
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownEventType, eventType)
+}
+
+// Has reports whether any version of eventType is registered.
+func (r *Registry) Has(eventType string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for key := range r.types {
+		if key.eventType == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Check reports whether event is registered under its type and version with
+// its own Go type.
+func (r *Registry) Check(event Event) error {
+	expected, err := r.New(event.EventType(), event.EventVersion())
+	if err != nil {
+		return err
+	}
+
+	if reflect.TypeOf(expected) != reflect.TypeOf(event) {
+		return fmt.Errorf("%w: %s version %d is registered as %T, got %T", ErrInvalidEvent, event.EventType(), event.EventVersion(), expected, event)
+	}
+	return nil
+}
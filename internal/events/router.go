@@ -0,0 +1,163 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"time"
+
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/rs/zerolog"
+)
+
+var (
+	ErrNoHandler      = errors.New("no handler for event type")
+	ErrHandlerTimeout = errors.New("event handler timed out")
+)
+
+// Handler processes one decoded event.
+type Handler func(ctx context.Context, envelope *Envelope, event Event) error
+
+// Middleware wraps a handler, for example to log or time it.
+type Middleware func(next Handler) Handler
+
+// Router dispatches messages to the handler registered for their event type.
+type Router struct {
+	handlers    map[string]Handler
+	middlewares []Middleware
+}
+
+func NewRouter() *Router {
+	return &Router{
+		handlers: map[string]Handler{},
+	}
+}
+
+// Use adds middleware to every handler. The first middleware added is the
+// outermost.
+func (r *Router) Use(middlewares ...Middleware) {
+	r.middlewares = append(r.middlewares, middlewares...)
+}
+
+// Handle registers handler for eventType. The type must be in the registry
+// and may only have one handler.
+func (r *Router) Handle(eventType string, handler Handler) {
+	if !DefaultRegistry.Has(eventType) {
+		panic(fmt.Sprintf("events: cannot handle unregistered event type %s", eventType))
+	}
+	if _, ok := r.handlers[eventType]; ok {
+		panic(fmt.Sprintf("events: %s handled twice", eventType))
+	}
+	r.handlers[eventType] = handler
+}
+
+// Process decodes msg and runs its handler behind the middleware. Messages
+// that fail to decode return a permanent error; messages without a handler
+// return ErrNoHandler.
+func (r *Router) Process(ctx context.Context, msg *message.Message) error {
+	envelope, event, err := Decode(msg.Metadata.Get(EventTypeMetadataKey), msg.Payload)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	handler, ok := r.handlers[envelope.Type]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoHandler, envelope.Type)
+	}
+
+	for i := len(r.middlewares) - 1; i >= 0; i-- {
+		handler = r.middlewares[i](handler)
+	}
+
+	return handler(ctx, envelope, event)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as one that retrying will not fix, so the message goes
+// straight to the dead-letter queue.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Logging logs the outcome and duration of every handled event.
+func Logging(log *zerolog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, envelope *Envelope, event Event) error {
+			start := time.Now()
+			err := next(ctx, envelope, event)
+
+			entry := log.Info()
+			if err != nil {
+				entry = log.Error().Err(err)
+			}
+			entry.Str("event_type", envelope.Type).
+				Str("event_id", envelope.ID).
+				Str("correlation_id", envelope.CorrelationID).
+				Dur("duration", time.Since(start)).
+				Msg("Handled event")
+
+			return err
+		}
+	}
+}
+
+// Recoverer turns a panicking handler into a permanent error.
+func Recoverer(log *zerolog.Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, envelope *Envelope, event Event) (err error) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Error().Str("event_id", envelope.ID).Bytes("stack", debug.Stack()).Msgf("Event handler panicked: %v", r)
+					err = Permanent(fmt.Errorf("handler panicked: %v", r))
+				}
+			}()
+			return next(ctx, envelope, event)
+		}
+	}
+}
+
+// Timeout cancels the handler's context after d and returns
+// ErrHandlerTimeout if it has not finished by then. A handler that ignores
+// its context keeps running in the background and may still succeed, so the
+// error is permanent: retrying could send the same email twice, while a
+// dead-lettered message can be checked and replayed by hand. The handler
+// runs in its own goroutine, so Recoverer must come after Timeout.
+func Timeout(d time.Duration) Middleware {
+	return func(next Handler) Handler {
+		return func(ctx context.Context, envelope *Envelope, event Event) error {
+			if d <= 0 {
+				return next(ctx, envelope, event)
+			}
+
+			ctx, cancel := context.WithTimeout(ctx, d)
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() {
+				done <- next(ctx, envelope, event)
+			}()
+
+			select {
+			case err := <-done:
+				return err
+			case <-ctx.Done():
+				return Permanent(fmt.Errorf("%w after %s", ErrHandlerTimeout, d))
+			}
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func process(t *testing.T, router *Router) error {
+	t.Helper()
+	return router.Process(context.Background(), newTestMessage(t))
+}
+
+func TestMiddlewareOrder(t *testing.T) {
+	var calls []string
+	record := func(name string) Middleware {
+		return func(next Handler) Handler {
+			return func(ctx context.Context, envelope *Envelope, event Event) error {
+				calls = append(calls, name+" before")
+				err := next(ctx, envelope, event)
+				calls = append(calls, name+" after")
+				return err
+			}
+		}
+	}
+
+	router := NewRouter()
+	router.Use(record("first"), record("second"))
+	router.Use(record("third"))
+	router.Handle(UserLoggedInEventType, func(context.Context, *Envelope, Event) error {
+		calls = append(calls, "handler")
+		return nil
+	})
+
+	if err := process(t, router); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"first before", "second before", "third before", "handler", "third after", "second after", "first after"}
+	if !slices.Equal(calls, want) {
+		t.Errorf("calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRecovererInsideTimeoutCatchesPanics(t *testing.T) {
+	log := zerolog.Nop()
+	router := NewRouter()
+	router.Use(Timeout(time.Second), Recoverer(&log))
+	router.Handle(UserLoggedInEventType, func(context.Context, *Envelope, Event) error {
+		panic("boom")
+	})
+
+	err := process(t, router)
+	if !IsPermanent(err) {
+		t.Fatalf("got %v, want a permanent error", err)
+	}
+}
+
+func TestTimeoutErrorIsPermanent(t *testing.T) {
+	router := NewRouter()
+	router.Use(Timeout(10 * time.Millisecond))
+	router.Handle(UserLoggedInEventType, func(ctx context.Context, _ *Envelope, _ Event) error {
+		<-ctx.Done()
+		time.Sleep(10 * time.Millisecond)
+		return nil
+	})
+
+	err := process(t, router)
+	if !errors.Is(err, ErrHandlerTimeout) || !IsPermanent(err) {
+		t.Fatalf("got %v, want a permanent ErrHandlerTimeout", err)
+	}
+}
+
+func TestTimeoutPassesHandlerErrorsThrough(t *testing.T) {
+	router := NewRouter()
+	router.Use(Timeout(time.Second))
+	router.Handle(UserLoggedInEventType, func(context.Context, *Envelope, Event) error {
+		return errHandlerFailed
+	})
+
+	err := process(t, router)
+	if !errors.Is(err, errHandlerFailed) || IsPermanent(err) {
+		t.Fatalf("got %v, want the handler's retryable error", err)
+	}
+}
+
+func TestProcessWithoutHandler(t *testing.T) {
+	if err := process(t, NewRouter()); !errors.Is(err, ErrNoHandler) {
+		t.Fatalf("got %v, want ErrNoHandler", err)
+	}
+}
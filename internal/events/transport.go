@@ -0,0 +1,88 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/ThreeDotsLabs/watermill"
+	"github.com/ThreeDotsLabs/watermill-aws/sqs"
+	"github.com/ThreeDotsLabs/watermill/message"
+	"github.com/ThreeDotsLabs/watermill/pubsub/gochannel"
+
+	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/providers"
+)
+
+const (
+	TransportSQS    = "sqs"
+	TransportMemory = "memory"
+)
+
+var (
+	memoryPubSubOnce sync.Once
+	memoryPubSub     *gochannel.GoChannel
+)
+
+// sharedPubSub is the channel every memory transport publisher and subscriber
+// in the process uses, so an API and a notifier wired up in the same test see
+// each other's messages.
+func sharedPubSub() *gochannel.GoChannel {
+	memoryPubSubOnce.Do(func() {
+		memoryPubSub = NewChannelPubSub()
+	})
+	return memoryPubSub
+}
+
+// NewPublisher returns the publisher selected by EVENT_TRANSPORT.
+func NewPublisher(ctx context.Context, cfg *appConfig.Config) (PublisherInterface, error) {
+	switch cfg.Events.Transport {
+	case TransportSQS, "":
+		publisher, err := NewEventPublisher(ctx, &cfg.AWS)
+		if err != nil {
+			return nil, err
+		}
+		return publisher, nil
+	case TransportMemory:
+		return NewMemoryPublisher(sharedPubSub(), cfg.AWS.EventQueueName), nil
+	default:
+		return nil, fmt.Errorf("unknown event transport %q", cfg.Events.Transport)
+	}
+}
+
+// NewMessagePublisher returns a raw watermill publisher for the selected
+// transport, used to move messages between queues. The memory transport
+// ignores delay_seconds and delivers retries straight away.
+func NewMessagePublisher(ctx context.Context, cfg *appConfig.Config) (message.Publisher, error) {
+	switch cfg.Events.Transport {
+	case TransportSQS, "":
+		return NewSQSPublisher(ctx, &cfg.AWS)
+	case TransportMemory:
+		return sharedPubSub(), nil
+	default:
+		return nil, fmt.Errorf("unknown event transport %q", cfg.Events.Transport)
+	}
+}
+
+// NewSubscriber returns the subscriber selected by EVENT_TRANSPORT.
+func NewSubscriber(ctx context.Context, cfg *appConfig.Config) (message.Subscriber, error) {
+	switch cfg.Events.Transport {
+	case TransportSQS, "":
+		awsConfig, err := providers.CreateAwsConfig(ctx, &cfg.AWS)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create aws config: %w", err)
+		}
+
+		subscriber, err := sqs.NewSubscriber(sqs.SubscriberConfig{
+			AWSConfig: awsConfig,
+		}, watermill.NewStdLogger(false, false))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create subscriber: %w", err)
+		}
+		return subscriber, nil
+	case TransportMemory:
+		return sharedPubSub(), nil
+	default:
+		return nil, fmt.Errorf("unknown event transport %q", cfg.Events.Transport)
+	}
+}
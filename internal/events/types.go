@@ -0,0 +1,422 @@
+package events
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	UserRegisteredEventType             = "USER_REGISTERED"
+	EmailVerificationRequestedEventType = "EMAIL_VERIFICATION_REQUESTED"
+	UserLoggedInEventType               = "USER_LOGGED_IN"
+	PasswordResetRequestedEventType     = "PASSWORD_RESET_REQUESTED"
+	PasswordChangedEventType            = "PASSWORD_CHANGED"
+	RefreshTokenReusedEventType         = "REFRESH_TOKEN_REUSED"
+	AccountLockedEventType              = "ACCOUNT_LOCKED"
+	OrderCreatedEventType               = "ORDER_CREATED"
+	OrderStatusChangedEventType         = "ORDER_STATUS_CHANGED"
+	OrderCancelledEventType             = "ORDER_CANCELLED"
+	ProductStockLowEventType            = "PRODUCT_STOCK_LOW"
+	ProductOutOfStockEventType          = "PRODUCT_OUT_OF_STOCK"
+	ProductPriceChangedEventType        = "PRODUCT_PRICE_CHANGED"
+	WishlistBackInStockEventType        = "WISHLIST_BACK_IN_STOCK"
+	WishlistPriceDropEventType          = "WISHLIST_PRICE_DROP"
+)
+
+func init() {
+	DefaultRegistry.Register(&UserRegistered{})
+	DefaultRegistry.Register(&EmailVerificationRequested{})
+	DefaultRegistry.Register(&UserLoggedIn{})
+	DefaultRegistry.Register(&PasswordResetRequested{})
+	DefaultRegistry.Register(&PasswordChanged{})
+	DefaultRegistry.Register(&RefreshTokenReused{})
+	DefaultRegistry.Register(&AccountLocked{})
+	DefaultRegistry.Register(&OrderCreated{})
+	DefaultRegistry.Register(&OrderStatusChanged{})
+	DefaultRegistry.Register(&OrderCancelled{})
+	DefaultRegistry.Register(&ProductStockLow{})
+	DefaultRegistry.Register(&ProductOutOfStock{})
+	DefaultRegistry.Register(&ProductPriceChanged{})
+	DefaultRegistry.Register(&WishlistBackInStock{})
+	DefaultRegistry.Register(&WishlistPriceDrop{})
+}
+
+// UserRegistered is published when a customer account is created. It carries
+// the link the notifier emails to verify the address.
+type UserRegistered struct {
+	UserID                uint      `json:"user_id"`
+	Email                 string    `json:"email"`
+	FirstName             string    `json:"first_name"`
+	LastName              string    `json:"last_name"`
+	Locale                string    `json:"locale,omitempty"`
+	VerificationURL       string    `json:"verification_url,omitempty"`
+	VerificationExpiresAt time.Time `json:"verification_expires_at,omitzero"`
+}
+
+func (*UserRegistered) EventType() string { return UserRegisteredEventType }
+func (*UserRegistered) EventVersion() int { return 1 }
+
+func (e *UserRegistered) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+// EmailVerificationRequested is published when a user asks for a new
+// verification link.
+type EmailVerificationRequested struct {
+	UserID                uint      `json:"user_id"`
+	Email                 string    `json:"email"`
+	FirstName             string    `json:"first_name"`
+	LastName              string    `json:"last_name"`
+	Locale                string    `json:"locale,omitempty"`
+	VerificationURL       string    `json:"verification_url"`
+	VerificationExpiresAt time.Time `json:"verification_expires_at"`
+}
+
+func (*EmailVerificationRequested) EventType() string { return EmailVerificationRequestedEventType }
+func (*EmailVerificationRequested) EventVersion() int { return 1 }
+
+func (e *EmailVerificationRequested) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	if e.VerificationURL == "" {
+		return errors.New("verification_url is required")
+	}
+	return nil
+}
+
+// UserLoggedIn is published for every successful login or token refresh.
+type UserLoggedIn struct {
+	UserID    uint   `json:"user_id"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Locale    string `json:"locale,omitempty"`
+}
+
+func (*UserLoggedIn) EventType() string { return UserLoggedInEventType }
+func (*UserLoggedIn) EventVersion() int { return 1 }
+
+func (e *UserLoggedIn) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+// PasswordResetRequested is published when a user asks for a password reset
+// link.
+type PasswordResetRequested struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Locale    string    `json:"locale,omitempty"`
+	ResetURL  string    `json:"reset_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (*PasswordResetRequested) EventType() string { return PasswordResetRequestedEventType }
+func (*PasswordResetRequested) EventVersion() int { return 1 }
+
+func (e *PasswordResetRequested) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	if e.ResetURL == "" {
+		return errors.New("reset_url is required")
+	}
+	return nil
+}
+
+const (
+	PasswordChangeMethodChange = "change"
+	PasswordChangeMethodReset  = "reset"
+)
+
+// PasswordChanged is published when a user's password is changed or reset.
+// Method tells which of the two happened.
+type PasswordChanged struct {
+	UserID    uint      `json:"user_id"`
+	Email     string    `json:"email"`
+	FirstName string    `json:"first_name"`
+	LastName  string    `json:"last_name"`
+	Locale    string    `json:"locale,omitempty"`
+	Method    string    `json:"method,omitempty"`
+	ChangedAt time.Time `json:"changed_at"`
+}
+
+func (*PasswordChanged) EventType() string { return PasswordChangedEventType }
+func (*PasswordChanged) EventVersion() int { return 1 }
+
+func (e *PasswordChanged) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+// RefreshTokenReused is published when a refresh token is used after it was
+// already exchanged, which means it was copied. The session it belongs to is
+// revoked.
+type RefreshTokenReused struct {
+	UserID     uint      `json:"user_id"`
+	Email      string    `json:"email"`
+	FirstName  string    `json:"first_name"`
+	LastName   string    `json:"last_name"`
+	Locale     string    `json:"locale,omitempty"`
+	FamilyID   string    `json:"family_id"`
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+func (*RefreshTokenReused) EventType() string { return RefreshTokenReusedEventType }
+func (*RefreshTokenReused) EventVersion() int { return 1 }
+
+func (e *RefreshTokenReused) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	return nil
+}
+
+// AccountLocked is published when too many failed logins lock an account.
+// IPAddress is the client of the failure that locked it.
+type AccountLocked struct {
+	UserID      uint      `json:"user_id"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"first_name"`
+	LastName    string    `json:"last_name"`
+	Locale      string    `json:"locale,omitempty"`
+	IPAddress   string    `json:"ip_address,omitempty"`
+	LockedAt    time.Time `json:"locked_at"`
+	LockedUntil time.Time `json:"locked_until"`
+}
+
+func (*AccountLocked) EventType() string { return AccountLockedEventType }
+func (*AccountLocked) EventVersion() int { return 1 }
+
+func (e *AccountLocked) Validate() error {
+	if e.UserID == 0 {
+		return errors.New("user_id is required")
+	}
+	if e.Email == "" {
+		return errors.New("email is required")
+	}
+	if e.LockedUntil.IsZero() {
+		return errors.New("locked_until is required")
+	}
+	return nil
+}
+
+type OrderItem struct {
+	ProductID uint    `json:"product_id"`
+	Quantity  int     `json:"quantity"`
+	Price     float64 `json:"price"`
+}
+
+// OrderCreated is published when a customer checks out their cart. Price is
+// the unit price at checkout.
+type OrderCreated struct {
+	OrderID     uint        `json:"order_id"`
+	UserID      uint        `json:"user_id"`
+	Status      string      `json:"status"`
+	TotalAmount float64     `json:"total_amount"`
+	Items       []OrderItem `json:"items"`
+}
+
+func (*OrderCreated) EventType() string { return OrderCreatedEventType }
+func (*OrderCreated) EventVersion() int { return 1 }
+
+func (e *OrderCreated) Validate() error {
+	if e.OrderID == 0 || e.UserID == 0 {
+		return errors.New("order_id and user_id are required")
+	}
+	if len(e.Items) == 0 {
+		return errors.New("items are required")
+	}
+	return nil
+}
+
+// OrderStatusChanged is published for every status transition, including
+// cancellation, which additionally publishes OrderCancelled.
+type OrderStatusChanged struct {
+	OrderID        uint   `json:"order_id"`
+	UserID         uint   `json:"user_id"`
+	PreviousStatus string `json:"previous_status"`
+	Status         string `json:"status"`
+}
+
+func (*OrderStatusChanged) EventType() string { return OrderStatusChangedEventType }
+func (*OrderStatusChanged) EventVersion() int { return 1 }
+
+func (e *OrderStatusChanged) Validate() error {
+	if e.OrderID == 0 || e.UserID == 0 {
+		return errors.New("order_id and user_id are required")
+	}
+	if e.Status == "" || e.Status == e.PreviousStatus {
+		return errors.New("status must differ from previous_status")
+	}
+	return nil
+}
+
+// OrderCancelled is published when an order is cancelled and its items are
+// returned to stock. CancelledBy is the ID of the user who cancelled it, the
+// customer or an admin.
+type OrderCancelled struct {
+	OrderID     uint        `json:"order_id"`
+	UserID      uint        `json:"user_id"`
+	CancelledBy uint        `json:"cancelled_by"`
+	TotalAmount float64     `json:"total_amount"`
+	Items       []OrderItem `json:"items"`
+}
+
+func (*OrderCancelled) EventType() string { return OrderCancelledEventType }
+func (*OrderCancelled) EventVersion() int { return 1 }
+
+func (e *OrderCancelled) Validate() error {
+	if e.OrderID == 0 || e.UserID == 0 {
+		return errors.New("order_id and user_id are required")
+	}
+	return nil
+}
+
+// ProductStock describes a product's stock after a change.
+type ProductStock struct {
+	ProductID     uint   `json:"product_id"`
+	SKU           string `json:"sku"`
+	Name          string `json:"name"`
+	PreviousStock int    `json:"previous_stock"`
+	Stock         int    `json:"stock"`
+	Threshold     int    `json:"threshold"`
+}
+
+func (p *ProductStock) Validate() error {
+	if p.ProductID == 0 {
+		return errors.New("product_id is required")
+	}
+	return nil
+}
+
+// ProductStockLow is published when stock drops to the low stock threshold
+// or below, while staying above zero.
+type ProductStockLow struct {
+	ProductStock
+}
+
+func (*ProductStockLow) EventType() string { return ProductStockLowEventType }
+func (*ProductStockLow) EventVersion() int { return 1 }
+
+// ProductOutOfStock is published when stock drops to zero.
+type ProductOutOfStock struct {
+	ProductStock
+}
+
+func (*ProductOutOfStock) EventType() string { return ProductOutOfStockEventType }
+func (*ProductOutOfStock) EventVersion() int { return 1 }
+
+// StockEvents returns the event for a stock change that crosses the low
+// stock threshold or runs out, or nil when neither happened.
+func StockEvents(stock ProductStock) []Event {
+	switch {
+	case stock.Stock <= 0 && stock.PreviousStock > 0:
+		return []Event{&ProductOutOfStock{ProductStock: stock}}
+	case stock.Stock > 0 && stock.Stock <= stock.Threshold && stock.PreviousStock > stock.Threshold:
+		return []Event{&ProductStockLow{ProductStock: stock}}
+	default:
+		return nil
+	}
+}
+
+// ProductPriceChanged is published when an admin changes a product's price.
+type ProductPriceChanged struct {
+	ProductID     uint    `json:"product_id"`
+	SKU           string  `json:"sku"`
+	Name          string  `json:"name"`
+	PreviousPrice float64 `json:"previous_price"`
+	Price         float64 `json:"price"`
+}
+
+func (*ProductPriceChanged) EventType() string { return ProductPriceChangedEventType }
+func (*ProductPriceChanged) EventVersion() int { return 1 }
+
+func (e *ProductPriceChanged) Validate() error {
+	if e.ProductID == 0 {
+		return errors.New("product_id is required")
+	}
+	if e.Price == e.PreviousPrice {
+		return errors.New("price must differ from previous_price")
+	}
+	return nil
+}
+
+// WishlistAlert is sent once for every wishlist item subscribed to a
+// back-in-stock or price-drop alert.
+type WishlistAlert struct {
+	Email          string  `json:"email"`
+	Name           string  `json:"name"`
+	ProductID      uint    `json:"product_id"`
+	ProductName    string  `json:"product_name"`
+	OldPrice       float64 `json:"old_price"`
+	NewPrice       float64 `json:"new_price"`
+	Stock          int     `json:"stock"`
+	UnsubscribeURL string  `json:"unsubscribe_url"`
+	Locale         string  `json:"locale,omitempty"`
+}
+
+func (a *WishlistAlert) Validate() error {
+	if a.Email == "" {
+		return errors.New("email is required")
+	}
+	if a.ProductID == 0 {
+		return errors.New("product_id is required")
+	}
+	if a.UnsubscribeURL == "" {
+		return errors.New("unsubscribe_url is required")
+	}
+	return nil
+}
+
+type WishlistBackInStock struct {
+	WishlistAlert
+}
+
+func (*WishlistBackInStock) EventType() string { return WishlistBackInStockEventType }
+func (*WishlistBackInStock) EventVersion() int { return 1 }
+
+type WishlistPriceDrop struct {
+	WishlistAlert
+}
+
+func (*WishlistPriceDrop) EventType() string { return WishlistPriceDropEventType }
+func (*WishlistPriceDrop) EventVersion() int { return 1 }
+
+func (e *WishlistPriceDrop) Validate() error {
+	if err := e.WishlistAlert.Validate(); err != nil {
+		return err
+	}
+	if e.NewPrice >= e.OldPrice {
+		return errors.New("new_price must be lower than old_price")
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"strconv"
 
 	"github.com/ThreeDotsLabs/watermill"
 	"github.com/ThreeDotsLabs/watermill-aws/sqs"
@@ -11,15 +12,33 @@ import (
 
 	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/providers"
+	awsSqs "github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	_ "github.com/aws/smithy-go/endpoints"
 )
 
+const maxSQSDelaySeconds = 900
+
 type EventPublisher struct {
 	publisher message.Publisher
 	queueName string
 }
 
 func NewEventPublisher(ctx context.Context, cfg *appConfig.AWSConfig) (*EventPublisher, error) {
+	publisher, err := NewSQSPublisher(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EventPublisher{
+		publisher: publisher,
+		queueName: cfg.EventQueueName,
+	}, nil
+}
+
+// NewSQSPublisher creates a watermill publisher for SQS. A message carrying
+// delay_seconds metadata is delivered after that delay, at most 15 minutes.
+func NewSQSPublisher(ctx context.Context, cfg *appConfig.AWSConfig) (message.Publisher, error) {
 	logger := watermill.NewStdLogger(false, false)
 	awsConfig, err := providers.CreateAwsConfig(ctx, cfg)
 	if err != nil {
@@ -27,8 +46,9 @@ func NewEventPublisher(ctx context.Context, cfg *appConfig.AWSConfig) (*EventPub
 	}
 
 	publisherConfig := sqs.PublisherConfig{
-		AWSConfig: awsConfig,
-		Marshaler: nil,
+		AWSConfig:                awsConfig,
+		Marshaler:                nil,
+		GenerateSendMessageInput: generateDelayedSendMessageInput,
 	}
 
 	publisher, err := sqs.NewPublisher(publisherConfig, logger)
@@ -36,27 +56,54 @@ func NewEventPublisher(ctx context.Context, cfg *appConfig.AWSConfig) (*EventPub
 		return nil, fmt.Errorf("failed to create publisher: %w", err)
 	}
 
-	return &EventPublisher{
-		publisher: publisher,
-		queueName: cfg.EventQueueName,
-	}, nil
+	return publisher, nil
+}
+
+func generateDelayedSendMessageInput(ctx context.Context, queueURL sqs.QueueURL, msg *types.Message) (*awsSqs.SendMessageInput, error) {
+	input, err := sqs.GenerateSendMessageInputDefault(ctx, queueURL, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	attribute, ok := input.MessageAttributes[DelaySecondsMetadataKey]
+	if !ok {
+		return input, nil
+	}
+	delete(input.MessageAttributes, DelaySecondsMetadataKey)
+
+	if attribute.StringValue != nil {
+		if delay, err := strconv.Atoi(*attribute.StringValue); err == nil && delay > 0 {
+			input.DelaySeconds = int32(min(delay, maxSQSDelaySeconds))
+		}
+	}
+
+	return input, nil
 }
 
 func (e *EventPublisher) Publish(eventType string, payload any, metadata map[string]string) error {
-	data, err := json.Marshal(payload)
+	msg, err := newMessage(eventType, payload, metadata)
 	if err != nil {
 		return err
 	}
 
+	return e.publisher.Publish(e.queueName, msg)
+}
+
+func (e *EventPublisher) Close() error {
+	return e.publisher.Close()
+}
+
+func newMessage(eventType string, payload any, metadata map[string]string) (*message.Message, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
 	msg := message.NewMessage(watermill.NewUUID(), data)
 	msg.Metadata.Set("event_type", eventType)
 	for k, v := range metadata {
 		msg.Metadata.Set(k, v)
 	}
 
-	return e.publisher.Publish(e.queueName, msg)
-}
-
-func (e *EventPublisher) Close() error {
-	return e.publisher.Close()
+	return msg, nil
 }
@@ -0,0 +1,101 @@
+// Package identity signs users in with external identity providers. A
+// Provider runs the authorization code flow against one provider and returns
+// who the user is there; linking that identity to an account is up to the
+// caller.
+package identity
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"slices"
+)
+
+var (
+	ErrUnknownProvider     = errors.New("unknown identity provider")
+	ErrProviderUnavailable = errors.New("identity provider unavailable")
+	ErrExchangeFailed      = errors.New("authorization code rejected by the identity provider")
+	ErrInvalidIDToken      = errors.New("invalid ID token")
+)
+
+// AuthRequest holds what a login sends to the provider. The caller keeps
+// Nonce and CodeVerifier until the user comes back.
+type AuthRequest struct {
+	State         string
+	Nonce         string
+	CodeChallenge string
+}
+
+// Identity is a user as an identity provider knows them. Subject is stable
+// for a user of a provider; the email may change and is only trusted when
+// EmailVerified is set.
+type Identity struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Locale        string
+}
+
+// Provider is an external identity provider users can sign in with.
+type Provider interface {
+	Name() string
+	// AuthCodeURL returns the URL the user is sent to for signing in.
+	AuthCodeURL(ctx context.Context, req *AuthRequest) (string, error)
+	// Exchange redeems the code the user came back with. nonce and
+	// codeVerifier are those of the AuthRequest that started the login.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error)
+}
+
+// Registry holds the providers users can pick from.
+type Registry struct {
+	providers map[string]Provider
+}
+
+func NewRegistry(providers ...Provider) *Registry {
+	r := &Registry{providers: make(map[string]Provider, len(providers))}
+	for _, provider := range providers {
+		r.providers[provider.Name()] = provider
+	}
+	return r
+}
+
+// Provider returns the provider called name, or ErrUnknownProvider.
+func (r *Registry) Provider(name string) (Provider, error) {
+	provider, ok := r.providers[name]
+	if !ok {
+		return nil, ErrUnknownProvider
+	}
+	return provider, nil
+}
+
+// Names returns the name of every provider, sorted.
+func (r *Registry) Names() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// RandomToken returns 32 random bytes, base64url encoded. It is used for
+// states, nonces and PKCE code verifiers (RFC 7636 asks for 43 to 128
+// characters).
+func RandomToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// CodeChallenge derives the S256 PKCE challenge of a code verifier.
+func CodeChallenge(codeVerifier string) string {
+	sum := sha256.Sum256([]byte(codeVerifier))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
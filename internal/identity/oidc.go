@@ -0,0 +1,400 @@
+package identity
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/pkg/jwks"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// keysRefreshInterval is how often an unknown kid may trigger fetching the
+	// provider keys again, so garbage tokens cannot hammer the provider.
+	keysRefreshInterval = time.Minute
+	// clockSkew is the leeway on the time claims of ID tokens.
+	clockSkew = time.Minute
+	// maxResponseSize bounds what is read from a provider.
+	maxResponseSize = 1 << 20
+)
+
+var defaultScopes = []string{"openid", "email", "profile"}
+
+// OIDCConfig configures an OpenID Connect provider. The provider metadata
+// is discovered from IssuerURL on first use.
+type OIDCConfig struct {
+	Name         string
+	IssuerURL    string
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	HTTPClient   *http.Client
+}
+
+// discovery is the part of the provider metadata used here.
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcProvider struct {
+	cfg    OIDCConfig
+	client *http.Client
+
+	mu            sync.Mutex
+	metadata      *discovery
+	keys          map[string]*jwks.Key
+	keysFetchedAt time.Time
+}
+
+// NewOIDCProvider returns a provider for an OpenID Connect issuer. It uses
+// the authorization code flow with PKCE and authenticates with the client
+// secret (client_secret_basic).
+func NewOIDCProvider(cfg OIDCConfig) Provider {
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = defaultScopes
+	}
+	if !slices.Contains(cfg.Scopes, "openid") {
+		cfg.Scopes = append([]string{"openid"}, cfg.Scopes...)
+	}
+
+	client := cfg.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	return &oidcProvider{cfg: cfg, client: client}
+}
+
+func (p *oidcProvider) Name() string {
+	return p.cfg.Name
+}
+
+func (p *oidcProvider) AuthCodeURL(ctx context.Context, req *AuthRequest) (string, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("%w: invalid authorization endpoint: %v", ErrProviderUnavailable, err)
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.cfg.Scopes, " "))
+	query.Set("state", req.State)
+	query.Set("nonce", req.Nonce)
+	query.Set("code_challenge", req.CodeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+func (p *oidcProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Identity, error) {
+	metadata, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {p.cfg.RedirectURL},
+		"code_verifier": {codeVerifier},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, metadata.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	// RFC 6749 form-encodes the credentials before the basic scheme.
+	req.SetBasicAuth(url.QueryEscape(p.cfg.ClientID), url.QueryEscape(p.cfg.ClientSecret))
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	var token tokenResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(&token); err != nil {
+		return nil, fmt.Errorf("%w: token response: %v", ErrProviderUnavailable, err)
+	}
+	// Errors about the grant come back as 400 (RFC 6749 section 5.2); the
+	// rest is a provider problem.
+	if resp.StatusCode == http.StatusBadRequest && token.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrExchangeFailed, token.Error, token.ErrorDescription)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: token endpoint returned %d %s", ErrProviderUnavailable, resp.StatusCode, token.Error)
+	}
+	if token.IDToken == "" {
+		return nil, fmt.Errorf("%w: no ID token returned", ErrInvalidIDToken)
+	}
+
+	claims, err := p.verifyIDToken(ctx, metadata, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	// Some providers leave the profile out of the ID token.
+	if claims.Email == "" && metadata.UserinfoEndpoint != "" && token.AccessToken != "" {
+		if err := p.userinfo(ctx, metadata, token.AccessToken, claims); err != nil {
+			return nil, err
+		}
+	}
+
+	return &Identity{
+		Provider:      p.cfg.Name,
+		Subject:       claims.Subject,
+		Email:         strings.TrimSpace(claims.Email),
+		EmailVerified: bool(claims.EmailVerified),
+		GivenName:     claims.GivenName,
+		FamilyName:    claims.FamilyName,
+		Locale:        claims.Locale,
+	}, nil
+}
+
+// flexBool reads a boolean some providers send as a string.
+type flexBool bool
+
+func (b *flexBool) UnmarshalJSON(data []byte) error {
+	switch string(data) {
+	case "true", `"true"`:
+		*b = true
+	case "false", `"false"`, "null":
+		*b = false
+	default:
+		return fmt.Errorf("invalid boolean %s", data)
+	}
+	return nil
+}
+
+type idTokenClaims struct {
+	Nonce           string   `json:"nonce"`
+	AuthorizedParty string   `json:"azp"`
+	Email           string   `json:"email"`
+	EmailVerified   flexBool `json:"email_verified"`
+	GivenName       string   `json:"given_name"`
+	FamilyName      string   `json:"family_name"`
+	Locale          string   `json:"locale"`
+	jwt.RegisteredClaims
+}
+
+// verifyIDToken checks an ID token as OpenID Connect Core 3.1.3.7 says:
+// signed by the issuer, for this client, not expired, and bound to the
+// login by its nonce.
+func (p *oidcProvider) verifyIDToken(ctx context.Context, metadata *discovery, raw, nonce string) (*idTokenClaims, error) {
+	token, err := jwt.ParseWithClaims(raw, &idTokenClaims{}, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := p.key(ctx, metadata, kid)
+		if err != nil {
+			return nil, err
+		}
+		// The algorithm comes from the key, never from the token alone.
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("key %q does not sign with %s", kid, token.Method.Alg())
+		}
+		return key.Public, nil
+	},
+		jwt.WithValidMethods([]string{jwks.AlgRS256, jwks.AlgES256, jwks.AlgEdDSA}),
+		jwt.WithIssuer(metadata.Issuer),
+		jwt.WithAudience(p.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkew),
+	)
+	if errors.Is(err, ErrProviderUnavailable) {
+		return nil, err
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidIDToken, err)
+	}
+
+	claims, ok := token.Claims.(*idTokenClaims)
+	if !ok || !token.Valid {
+		return nil, ErrInvalidIDToken
+	}
+	if claims.Nonce != nonce {
+		return nil, fmt.Errorf("%w: nonce mismatch", ErrInvalidIDToken)
+	}
+	if len(claims.Audience) > 1 && claims.AuthorizedParty != p.cfg.ClientID {
+		return nil, fmt.Errorf("%w: issued to %q", ErrInvalidIDToken, claims.AuthorizedParty)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: no subject", ErrInvalidIDToken)
+	}
+	return claims, nil
+}
+
+type userinfoResponse struct {
+	Subject       string   `json:"sub"`
+	Email         string   `json:"email"`
+	EmailVerified flexBool `json:"email_verified"`
+	GivenName     string   `json:"given_name"`
+	FamilyName    string   `json:"family_name"`
+	Locale        string   `json:"locale"`
+}
+
+// userinfo completes claims from the userinfo endpoint. Its answer is only
+// used when it is about the subject of the ID token.
+func (p *oidcProvider) userinfo(ctx context.Context, metadata *discovery, accessToken string, claims *idTokenClaims) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.UserinfoEndpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Accept", "application/json")
+
+	var info userinfoResponse
+	if err := p.getJSON(req, &info); err != nil {
+		return err
+	}
+	if info.Subject != claims.Subject {
+		return fmt.Errorf("%w: userinfo is about another subject", ErrInvalidIDToken)
+	}
+
+	claims.Email = info.Email
+	claims.EmailVerified = info.EmailVerified
+	if claims.GivenName == "" && claims.FamilyName == "" {
+		claims.GivenName = info.GivenName
+		claims.FamilyName = info.FamilyName
+	}
+	if claims.Locale == "" {
+		claims.Locale = info.Locale
+	}
+	return nil
+}
+
+// discover fetches the provider metadata once. A failure is retried on the
+// next login rather than cached.
+func (p *oidcProvider) discover(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.metadata != nil {
+		return p.metadata, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(p.cfg.IssuerURL, "/")+"/.well-known/openid-configuration", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var metadata discovery
+	if err := p.getJSON(req, &metadata); err != nil {
+		return nil, err
+	}
+
+	// The issuer has to be the one configured, or anyone serving metadata
+	// at that URL could sign ID tokens for it.
+	if metadata.Issuer != p.cfg.IssuerURL {
+		return nil, fmt.Errorf("%w: metadata is for issuer %q, expected %q", ErrProviderUnavailable, metadata.Issuer, p.cfg.IssuerURL)
+	}
+	if metadata.AuthorizationEndpoint == "" || metadata.TokenEndpoint == "" || metadata.JWKSURI == "" {
+		return nil, fmt.Errorf("%w: incomplete metadata", ErrProviderUnavailable)
+	}
+
+	p.metadata = &metadata
+	return p.metadata, nil
+}
+
+// key returns the provider key kid, fetching the key set again when the
+// provider may have rotated its keys since.
+func (p *oidcProvider) key(ctx context.Context, metadata *discovery, kid string) (*jwks.Key, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if key := p.cachedKey(kid); key != nil {
+		return key, nil
+	}
+	if time.Since(p.keysFetchedAt) < keysRefreshInterval {
+		return nil, fmt.Errorf("unknown key %q", kid)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadata.JWKSURI, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	var set jwks.Set
+	if err := p.getJSON(req, &set); err != nil {
+		return nil, err
+	}
+
+	// Keys of a type not verified here are skipped; tokens they sign fail
+	// as signed by an unknown key.
+	keys := make(map[string]*jwks.Key, len(set.Keys))
+	for _, jwk := range set.Keys {
+		key, err := jwk.Key()
+		if err != nil {
+			continue
+		}
+		keys[key.ID] = key
+	}
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+
+	if key := p.cachedKey(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+// cachedKey looks kid up in the keys fetched last. A token without kid is
+// fine when the provider has a single key.
+func (p *oidcProvider) cachedKey(kid string) *jwks.Key {
+	if key, ok := p.keys[kid]; ok {
+		return key
+	}
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key
+		}
+	}
+	return nil
+}
+
+func (p *oidcProvider) getJSON(req *http.Request, v any) error {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: %s returned %d", ErrProviderUnavailable, req.URL.Redacted(), resp.StatusCode)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrProviderUnavailable, req.URL.Redacted(), err)
+	}
+	return nil
+}
@@ -0,0 +1,268 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/identity/oidctest"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	testClientID     = "ecommerce"
+	testClientSecret = "secret"
+	testRedirectURL  = "http://localhost:8080/api/v1/auth/oidc/dev/callback"
+)
+
+func newTestIssuer(t *testing.T) *oidctest.Issuer {
+	t.Helper()
+
+	issuer, server, err := oidctest.NewServer(testClientID, testClientSecret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Close)
+	return issuer
+}
+
+func newTestProvider(issuer *oidctest.Issuer) Provider {
+	return NewOIDCProvider(OIDCConfig{
+		Name:         "dev",
+		IssuerURL:    issuer.URL,
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+}
+
+// login is a login in progress: what the caller keeps and the code the
+// issuer sent the user back with.
+type login struct {
+	state        string
+	nonce        string
+	codeVerifier string
+	code         string
+}
+
+// authorize starts a login and follows the authorization URL the way a
+// browser would, up to the redirect back to us.
+func authorize(t *testing.T, provider Provider) *login {
+	t.Helper()
+
+	l := &login{}
+	for _, token := range []*string{&l.state, &l.nonce, &l.codeVerifier} {
+		var err error
+		if *token, err = RandomToken(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	authURL, err := provider.AuthCodeURL(context.Background(), &AuthRequest{
+		State:         l.state,
+		Nonce:         l.nonce,
+		CodeChallenge: CodeChallenge(l.codeVerifier),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	back := followAuthorization(t, authURL)
+	if back.Get("state") != l.state {
+		t.Fatalf("state %q came back, want %q", back.Get("state"), l.state)
+	}
+	if back.Get("error") != "" {
+		t.Fatalf("authorization failed: %s %s", back.Get("error"), back.Get("error_description"))
+	}
+	l.code = back.Get("code")
+	return l
+}
+
+// followAuthorization requests authURL and returns the query of the
+// redirect back.
+func followAuthorization(t *testing.T, authURL string) url.Values {
+	t.Helper()
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("no redirect back (status %d): %v", resp.StatusCode, err)
+	}
+	return location.Query()
+}
+
+func TestAuthCodeURLUsesPKCE(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := newTestProvider(issuer)
+
+	authURL, err := provider.AuthCodeURL(context.Background(), &AuthRequest{State: "state", Nonce: "nonce", CodeChallenge: "challenge"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	want := map[string]string{
+		"response_type":         "code",
+		"client_id":             testClientID,
+		"redirect_uri":          testRedirectURL,
+		"scope":                 "openid email profile",
+		"state":                 "state",
+		"nonce":                 "nonce",
+		"code_challenge":        "challenge",
+		"code_challenge_method": "S256",
+	}
+	for name, value := range want {
+		if query.Get(name) != value {
+			t.Errorf("%s = %q, want %q", name, query.Get(name), value)
+		}
+	}
+}
+
+func TestExchange(t *testing.T) {
+	issuer := newTestIssuer(t)
+	issuer.SetUser(oidctest.User{
+		Subject:       "user-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		GivenName:     "Jane",
+		FamilyName:    "Doe",
+		Locale:        "fr-CA",
+	})
+	provider := newTestProvider(issuer)
+
+	l := authorize(t, provider)
+	external, err := provider.Exchange(context.Background(), l.code, l.codeVerifier, l.nonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Identity{
+		Provider:      "dev",
+		Subject:       "user-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		GivenName:     "Jane",
+		FamilyName:    "Doe",
+		Locale:        "fr-CA",
+	}
+	if *external != want {
+		t.Errorf("got %+v, want %+v", *external, want)
+	}
+
+	// Codes work once.
+	if _, err := provider.Exchange(context.Background(), l.code, l.codeVerifier, l.nonce); !errors.Is(err, ErrExchangeFailed) {
+		t.Errorf("second exchange: got %v, want ErrExchangeFailed", err)
+	}
+}
+
+func TestExchangeNeedsTheCodeVerifier(t *testing.T) {
+	provider := newTestProvider(newTestIssuer(t))
+
+	l := authorize(t, provider)
+	other, err := RandomToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := provider.Exchange(context.Background(), l.code, other, l.nonce); !errors.Is(err, ErrExchangeFailed) {
+		t.Fatalf("got %v, want ErrExchangeFailed", err)
+	}
+}
+
+func TestExchangeNeedsTheClientSecret(t *testing.T) {
+	issuer := newTestIssuer(t)
+	provider := NewOIDCProvider(OIDCConfig{
+		Name:         "dev",
+		IssuerURL:    issuer.URL,
+		ClientID:     testClientID,
+		ClientSecret: "wrong",
+		RedirectURL:  testRedirectURL,
+	})
+
+	l := authorize(t, provider)
+	if _, err := provider.Exchange(context.Background(), l.code, l.codeVerifier, l.nonce); !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("got %v, want ErrProviderUnavailable", err)
+	}
+}
+
+func TestExchangeRejectsNonceMismatch(t *testing.T) {
+	provider := newTestProvider(newTestIssuer(t))
+
+	l := authorize(t, provider)
+	if _, err := provider.Exchange(context.Background(), l.code, l.codeVerifier, "another-login"); !errors.Is(err, ErrInvalidIDToken) {
+		t.Fatalf("got %v, want ErrInvalidIDToken", err)
+	}
+}
+
+func TestExchangeChecksIDTokenClaims(t *testing.T) {
+	tests := []struct {
+		name   string
+		claims func(jwt.MapClaims)
+		ok     bool
+	}{
+		{"other audience", func(c jwt.MapClaims) { c["aud"] = "another-client" }, false},
+		{"several audiences without azp", func(c jwt.MapClaims) { c["aud"] = []string{testClientID, "another-client"} }, false},
+		{"several audiences for another party", func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "another-client"}
+			c["azp"] = "another-client"
+		}, false},
+		{"several audiences for us", func(c jwt.MapClaims) {
+			c["aud"] = []string{testClientID, "another-client"}
+			c["azp"] = testClientID
+		}, true},
+		{"other issuer", func(c jwt.MapClaims) { c["iss"] = "https://elsewhere.example.com" }, false},
+		{"expired", func(c jwt.MapClaims) { c["exp"] = time.Now().Add(-2 * clockSkew).Unix() }, false},
+		{"no expiry", func(c jwt.MapClaims) { delete(c, "exp") }, false},
+		{"issued in the future", func(c jwt.MapClaims) { c["iat"] = time.Now().Add(2 * clockSkew).Unix() }, false},
+		{"no subject", func(c jwt.MapClaims) { delete(c, "sub") }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issuer := newTestIssuer(t)
+			issuer.Claims = tt.claims
+			provider := newTestProvider(issuer)
+
+			l := authorize(t, provider)
+			_, err := provider.Exchange(context.Background(), l.code, l.codeVerifier, l.nonce)
+			if tt.ok && err != nil {
+				t.Fatalf("got %v, want no error", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidIDToken) {
+				t.Fatalf("got %v, want ErrInvalidIDToken", err)
+			}
+		})
+	}
+}
+
+func TestDiscoveryChecksTheIssuer(t *testing.T) {
+	issuer := newTestIssuer(t)
+	// Metadata served for another issuer than the one configured.
+	provider := NewOIDCProvider(OIDCConfig{
+		Name:         "dev",
+		IssuerURL:    issuer.URL + "/",
+		ClientID:     testClientID,
+		ClientSecret: testClientSecret,
+		RedirectURL:  testRedirectURL,
+	})
+
+	_, err := provider.AuthCodeURL(context.Background(), &AuthRequest{State: "state", Nonce: "nonce", CodeChallenge: "challenge"})
+	if !errors.Is(err, ErrProviderUnavailable) {
+		t.Fatalf("got %v, want ErrProviderUnavailable", err)
+	}
+}
@@ -0,0 +1,270 @@
+// Package oidctest runs a fake OpenID Connect issuer, so sign-in with an
+// external provider can be exercised locally without one. Logins are
+// approved right away for the user configured with SetUser; the code
+// exchange is as strict as a real provider: the client secret, redirect URI
+// and PKCE verifier must match and codes work once.
+package oidctest
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/pkg/jwks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// User is who the issuer signs in.
+type User struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	GivenName     string
+	FamilyName    string
+	Locale        string
+}
+
+// authorization is a code waiting to be exchanged.
+type authorization struct {
+	user          User
+	redirectURI   string
+	nonce         string
+	codeChallenge string
+	expiresAt     time.Time
+}
+
+// Issuer is a fake OpenID Connect provider. It implements http.Handler.
+type Issuer struct {
+	URL          string
+	ClientID     string
+	ClientSecret string
+	// TokenTTL is the lifetime of the ID tokens issued.
+	TokenTTL time.Duration
+	// Claims, when set, changes the claims of each ID token before it is
+	// signed, so clients can be tested against bad tokens.
+	Claims func(claims jwt.MapClaims)
+
+	key *jwks.Key
+	mux *http.ServeMux
+
+	mu    sync.Mutex
+	user  User
+	codes map[string]*authorization
+}
+
+// New returns an issuer served at issuerURL for a single client.
+func New(issuerURL, clientID, clientSecret string) (*Issuer, error) {
+	key, err := jwks.Generate(jwks.AlgRS256)
+	if err != nil {
+		return nil, err
+	}
+
+	issuer := &Issuer{
+		URL:          strings.TrimRight(issuerURL, "/"),
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenTTL:     5 * time.Minute,
+		key:          key,
+		codes:        make(map[string]*authorization),
+		user: User{
+			Subject:       "oidctest-user",
+			Email:         "oidc.user@example.com",
+			EmailVerified: true,
+			GivenName:     "Oidc",
+			FamilyName:    "User",
+		},
+	}
+
+	issuer.mux = http.NewServeMux()
+	issuer.mux.HandleFunc("GET /.well-known/openid-configuration", issuer.discovery)
+	issuer.mux.HandleFunc("GET /authorize", issuer.authorize)
+	issuer.mux.HandleFunc("POST /token", issuer.token)
+	issuer.mux.HandleFunc("GET /jwks", issuer.keys)
+
+	return issuer, nil
+}
+
+// NewServer starts an issuer on a local port. Close the server when done.
+func NewServer(clientID, clientSecret string) (*Issuer, *httptest.Server, error) {
+	var issuer *Issuer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.ServeHTTP(w, r)
+	}))
+
+	issuer, err := New(server.URL, clientID, clientSecret)
+	if err != nil {
+		server.Close()
+		return nil, nil, err
+	}
+	return issuer, server, nil
+}
+
+// SetUser changes who the next logins sign in as.
+func (i *Issuer) SetUser(user User) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.user = user
+}
+
+func (i *Issuer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	i.mux.ServeHTTP(w, r)
+}
+
+func (i *Issuer) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]any{
+		"issuer":                                i.URL,
+		"authorization_endpoint":                i.URL + "/authorize",
+		"token_endpoint":                        i.URL + "/token",
+		"jwks_uri":                              i.URL + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": []string{i.key.Algorithm},
+		"code_challenge_methods_supported":      []string{"S256"},
+		"token_endpoint_auth_methods_supported": []string{"client_secret_basic"},
+	})
+}
+
+// authorize approves the login and sends the user back with a code, or with
+// an error when the request is not one a real provider would accept.
+func (i *Issuer) authorize(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	redirectURI, err := url.Parse(query.Get("redirect_uri"))
+	if err != nil || !redirectURI.IsAbs() {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	if query.Get("client_id") != i.ClientID {
+		http.Error(w, "unknown client", http.StatusBadRequest)
+		return
+	}
+
+	back := redirectURI.Query()
+	back.Set("state", query.Get("state"))
+
+	switch {
+	case query.Get("response_type") != "code":
+		back.Set("error", "unsupported_response_type")
+	case !strings.Contains(" "+query.Get("scope")+" ", " openid "):
+		back.Set("error", "invalid_scope")
+	case query.Get("code_challenge") == "" || query.Get("code_challenge_method") != "S256":
+		back.Set("error", "invalid_request")
+		back.Set("error_description", "PKCE with S256 is required")
+	default:
+		code := uuid.NewString()
+		i.mu.Lock()
+		i.codes[code] = &authorization{
+			user:          i.user,
+			redirectURI:   redirectURI.String(),
+			nonce:         query.Get("nonce"),
+			codeChallenge: query.Get("code_challenge"),
+			expiresAt:     time.Now().Add(time.Minute),
+		}
+		i.mu.Unlock()
+		back.Set("code", code)
+	}
+
+	redirectURI.RawQuery = back.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (i *Issuer) token(w http.ResponseWriter, r *http.Request) {
+	clientID, clientSecret, ok := r.BasicAuth()
+	if ok {
+		clientID, _ = url.QueryUnescape(clientID)
+		clientSecret, _ = url.QueryUnescape(clientSecret)
+	}
+	if !ok || clientID != i.ClientID || subtle.ConstantTimeCompare([]byte(clientSecret), []byte(i.ClientSecret)) != 1 {
+		writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid_client"})
+		return
+	}
+
+	if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "authorization_code" {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "unsupported_grant_type"})
+		return
+	}
+
+	code := r.PostForm.Get("code")
+	i.mu.Lock()
+	auth, found := i.codes[code]
+	delete(i.codes, code)
+	i.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	switch {
+	case !found || auth.expiresAt.Before(time.Now()):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "unknown or expired code"})
+		return
+	case auth.redirectURI != r.PostForm.Get("redirect_uri"):
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "redirect_uri mismatch"})
+		return
+	case base64.RawURLEncoding.EncodeToString(sum[:]) != auth.codeChallenge:
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant", "error_description": "PKCE verification failed"})
+		return
+	}
+
+	idToken, err := i.IDToken(auth.user, auth.nonce)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": uuid.NewString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(i.TokenTTL.Seconds()),
+		"id_token":     idToken,
+	})
+}
+
+// IDToken signs an ID token for user, as the token endpoint does.
+func (i *Issuer) IDToken(user User, nonce string) (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":            i.URL,
+		"sub":            user.Subject,
+		"aud":            i.ClientID,
+		"iat":            now.Unix(),
+		"exp":            now.Add(i.TokenTTL).Unix(),
+		"email":          user.Email,
+		"email_verified": user.EmailVerified,
+		"given_name":     user.GivenName,
+		"family_name":    user.FamilyName,
+	}
+	if nonce != "" {
+		claims["nonce"] = nonce
+	}
+	if user.Locale != "" {
+		claims["locale"] = user.Locale
+	}
+	if i.Claims != nil {
+		i.Claims(claims)
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(i.key.Algorithm), claims)
+	token.Header["kid"] = i.key.ID
+	return token.SignedString(i.key.Private)
+}
+
+func (i *Issuer) keys(w http.ResponseWriter, r *http.Request) {
+	keys, err := jwks.NewKeySet(i.key.ID, i.key)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, keys.JWKS())
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
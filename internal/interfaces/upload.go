@@ -1,8 +1,37 @@
 package interfaces
 
-import "mime/multipart"
+import (
+	"errors"
+	"io"
+	"mime/multipart"
+	"time"
+)
 
+var ErrObjectNotFound = errors.New("object not found")
+
+// PresignedRequest lets a client talk to storage directly. Headers must be
+// sent as-is with the request for the signature to match.
+type PresignedRequest struct {
+	URL       string
+	Method    string
+	Headers   map[string]string
+	ExpiresAt time.Time
+}
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+// Upload stores files under a key. Upload methods return that key; turning
+// it into a public URL is up to storage.URLResolver.
 type Upload interface {
+	Name() string
 	UploadFile(file *multipart.FileHeader, path string) (string, error)
+	UploadObject(body io.Reader, path, contentType string) (string, error)
 	DeleteFile(filename string) error
+	PresignUpload(path, contentType string, expires time.Duration) (*PresignedRequest, error)
+	PresignDownload(path string, expires time.Duration) (*PresignedRequest, error)
+	StatObject(path string) (*ObjectInfo, error)
+	OpenObject(path string) (io.ReadCloser, error)
 }
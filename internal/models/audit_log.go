@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditUserDeactivated         AuditAction = "user.deactivated"
+	AuditUserReactivated         AuditAction = "user.reactivated"
+	AuditUserRoleChanged         AuditAction = "user.role_changed"
+	AuditUserPasswordResetForced AuditAction = "user.password_reset_forced"
+	AuditUserUnlocked            AuditAction = "user.unlocked"
+)
+
+// AuditLog records an action staff took on a user account. It is written in
+// the same transaction as the change and never updated.
+type AuditLog struct {
+	ID        uint        `json:"id" gorm:"primaryKey"`
+	ActorID   uint        `json:"actor_id" gorm:"not null"`
+	UserID    uint        `json:"user_id" gorm:"not null"`
+	Action    AuditAction `json:"action" gorm:"not null"`
+	Details   JSONMap     `json:"details" gorm:"type:jsonb;not null;default:'{}'"`
+	CreatedAt time.Time   `json:"created_at"`
+
+	// Relashionships
+	Actor User `json:"-" gorm:"foreignKey:ActorID;references:ID"`
+	User  User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
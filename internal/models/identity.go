@@ -0,0 +1,35 @@
+package models
+
+import "time"
+
+// UserIdentity links a user to their account at an external identity
+// provider. Subject is the provider's stable ID for the user; Email is the
+// one last seen there and is informative only.
+type UserIdentity struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	UserID      uint      `json:"user_id" gorm:"not null"`
+	Provider    string    `json:"provider" gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Subject     string    `json:"-" gorm:"not null;uniqueIndex:idx_user_identities_provider_subject"`
+	Email       string    `json:"email"`
+	LastLoginAt time.Time `json:"last_login_at"`
+	CreatedAt   time.Time `json:"created_at"`
+
+	// Relashionships
+	User User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
+
+// OIDCLoginState is a login sent to an identity provider and not back yet.
+// It is found by a hash of the state sent along, and keeps the nonce and
+// PKCE code verifier the callback needs.
+type OIDCLoginState struct {
+	StateHash    string    `json:"-" gorm:"primaryKey"`
+	Provider     string    `json:"provider" gorm:"not null"`
+	Nonce        string    `json:"-" gorm:"not null"`
+	CodeVerifier string    `json:"-" gorm:"not null"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"not null"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (OIDCLoginState) TableName() string {
+	return "oidc_login_states"
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+type ImportJob struct {
+	ID            uint            `json:"id" gorm:"primaryKey"`
+	UserID        uint            `json:"user_id" gorm:"not null"`
+	FileName      string          `json:"file_name" gorm:"not null"`
+	Status        ImportJobStatus `json:"status" gorm:"default:pending"`
+	TotalRows     int             `json:"total_rows" gorm:"default:0"`
+	ProcessedRows int             `json:"processed_rows" gorm:"default:0"`
+	CreatedCount  int             `json:"created_count" gorm:"default:0"`
+	UpdatedCount  int             `json:"updated_count" gorm:"default:0"`
+	FailedCount   int             `json:"failed_count" gorm:"default:0"`
+	Errors        ImportRowErrors `json:"errors" gorm:"type:jsonb"`
+	Message       string          `json:"message"`
+	StartedAt     *time.Time      `json:"started_at"`
+	FinishedAt    *time.Time      `json:"finished_at"`
+	CreatedAt     time.Time       `json:"created_at"`
+	UpdatedAt     time.Time       `json:"updated_at"`
+
+	// Relashionships
+	User User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending    ImportJobStatus = "pending"
+	ImportJobStatusProcessing ImportJobStatus = "processing"
+	ImportJobStatusCompleted  ImportJobStatus = "completed"
+	ImportJobStatusFailed     ImportJobStatus = "failed"
+)
+
+// ImportRowError reports a problem with one field of one CSV row. Row is the
+// line number in the uploaded file, counting the header as line 1.
+type ImportRowError struct {
+	Row     int    `json:"row"`
+	SKU     string `json:"sku"`
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+type ImportRowErrors []ImportRowError
+
+func (e ImportRowErrors) Value() (driver.Value, error) {
+	if e == nil {
+		return "[]", nil
+	}
+
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func (e *ImportRowErrors) Scan(value any) error {
+	data, err := scanBytes(value)
+	if err != nil {
+		return err
+	}
+
+	result := ImportRowErrors{}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &result); err != nil {
+			return err
+		}
+	}
+
+	*e = result
+	return nil
+}
@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// LoginThrottle counts the recent failed logins of one key, an account or a
+// client IP. Logins for the key are refused until BlockedUntil.
+type LoginThrottle struct {
+	Key          string     `json:"key" gorm:"primaryKey"`
+	Failures     int        `json:"failures" gorm:"not null;default:0"`
+	LastFailedAt time.Time  `json:"last_failed_at" gorm:"not null"`
+	BlockedUntil *time.Time `json:"blocked_until"`
+}
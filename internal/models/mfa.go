@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// UserTOTP is the authenticator app of a user. Secret is encrypted, since it
+// has to be read back to check codes. Two-factor authentication is on once
+// the user confirmed a first code, which sets User.MFAEnabledAt; until then
+// the secret is only a pending enrolment. LastUsedStep keeps a code from
+// being used twice.
+type UserTOTP struct {
+	UserID       uint      `json:"user_id" gorm:"primaryKey"`
+	Secret       string    `json:"-" gorm:"not null"`
+	LastUsedStep int64     `json:"-" gorm:"not null;default:0"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// Relashionships
+	User User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
+
+// MFARecoveryCode is a single-use code that replaces an authenticator code
+// when the device is lost. Only a hash is stored.
+type MFARecoveryCode struct {
+	ID        uint       `json:"id" gorm:"primaryKey"`
+	UserID    uint       `json:"user_id" gorm:"not null"`
+	CodeHash  string     `json:"-" gorm:"unique;not null"`
+	UsedAt    *time.Time `json:"used_at"`
+	CreatedAt time.Time  `json:"created_at"`
+
+	// Relashionships
+	User User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+type OutboxStatus string
+
+const (
+	OutboxStatusPending   OutboxStatus = "pending"
+	OutboxStatusPublished OutboxStatus = "published"
+	OutboxStatusFailed    OutboxStatus = "failed"
+)
+
+// OutboxMessage is an event saved in the same transaction as the change that
+// raised it. The relay publishes pending messages and records the outcome.
+type OutboxMessage struct {
+	ID          uint         `json:"id" gorm:"primaryKey"`
+	EventType   string       `json:"event_type" gorm:"not null"`
+	Payload     string       `json:"payload" gorm:"type:jsonb;not null"`
+	Metadata    StringMap    `json:"metadata" gorm:"type:jsonb;not null"`
+	Status      OutboxStatus `json:"status" gorm:"not null;default:pending"`
+	Attempts    int          `json:"attempts" gorm:"not null;default:0"`
+	AvailableAt time.Time    `json:"available_at" gorm:"not null"`
+	PublishedAt *time.Time   `json:"published_at"`
+	LastError   string       `json:"last_error"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
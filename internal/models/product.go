@@ -3,6 +3,7 @@ package models
 import (
 	"time"
 
+	"github.com/anzhy11/go-e-commerce/internal/storage"
 	"gorm.io/gorm"
 )
 
@@ -16,7 +17,38 @@ type Category struct {
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relashionships
-	Products []Product `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
+	Products   []Product           `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
+	Attributes []CategoryAttribute `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
+}
+
+type AttributeType string
+
+const (
+	AttributeTypeText    AttributeType = "text"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeEnum    AttributeType = "enum"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// CategoryAttribute describes one entry of a category's attribute schema.
+// Products in the category store their values under Key.
+type CategoryAttribute struct {
+	ID           uint           `json:"id" gorm:"primaryKey"`
+	CategoryID   uint           `json:"category_id" gorm:"not null"`
+	Key          string         `json:"key" gorm:"not null"`
+	Label        string         `json:"label" gorm:"not null"`
+	Type         AttributeType  `json:"type" gorm:"not null"`
+	Unit         string         `json:"unit"`
+	Options      StringList     `json:"options" gorm:"type:jsonb"`
+	IsRequired   bool           `json:"is_required" gorm:"default:false"`
+	IsFilterable bool           `json:"is_filterable" gorm:"default:false"`
+	Position     int            `json:"position" gorm:"default:0"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relashionships
+	Category Category `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
 }
 
 type Product struct {
@@ -28,26 +60,54 @@ type Product struct {
 	Stock       int            `json:"stock" gorm:"default:0"`
 	SKU         string         `json:"sku" gorm:"unique;not null"`
 	IsActive    bool           `json:"is_active" gorm:"default:true"`
+	Attributes  JSONMap        `json:"attributes" gorm:"type:jsonb;not null;default:'{}'"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 
+	// Denormalised from approved reviews
+	AverageRating float64 `json:"average_rating" gorm:"default:0"`
+	ReviewCount   int     `json:"review_count" gorm:"default:0"`
+
 	// Relashionships
 	Category   Category       `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
 	Images     []ProductImage `json:"-" gorm:"foreignKey:ProductID;references:ID"`
 	OrderItems []OrderItem    `json:"-" gorm:"foreignKey:ProductID;references:ID"`
 	CartItems  []CartItem     `json:"-" gorm:"foreignKey:ProductID;references:ID"`
+	Reviews    []Review       `json:"-" gorm:"foreignKey:ProductID;references:ID"`
 }
 
 type ProductImage struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	ProductID uint           `json:"product_id" gorm:"not null"`
-	URL       string         `json:"url" gorm:"not null"`
-	AltText   string         `json:"alt_text"`
-	IsPrimary bool           `json:"is_primary" gorm:"default:false"`
-	CreatedAt time.Time      `json:"created_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	ProductID       uint           `json:"product_id" gorm:"not null"`
+	StorageProvider string         `json:"storage_provider" gorm:"not null;default:local"`
+	Key             string         `json:"key" gorm:"not null"`
+	ThumbnailKey    string         `json:"thumbnail_key"`
+	MediumKey       string         `json:"medium_key"`
+	LargeKey        string         `json:"large_key"`
+	Width           int            `json:"width"`
+	Height          int            `json:"height"`
+	AltText         string         `json:"alt_text"`
+	IsPrimary       bool           `json:"is_primary" gorm:"default:false"`
+	Position        int            `json:"position" gorm:"default:0"`
+	CreatedAt       time.Time      `json:"created_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relashionships
 	Product Product `json:"-" gorm:"foreignKey:ProductID;references:ID"`
 }
+
+// Object returns the stored file behind one of the image's keys.
+func (i *ProductImage) Object(key string) storage.Object {
+	return storage.Object{Provider: i.StorageProvider, Key: key}
+}
+
+// Objects lists every stored file of the image, original first.
+func (i *ProductImage) Objects() []storage.Object {
+	return []storage.Object{
+		i.Object(i.Key),
+		i.Object(i.ThumbnailKey),
+		i.Object(i.MediumKey),
+		i.Object(i.LargeKey),
+	}
+}
@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type Review struct {
+	ID             uint           `json:"id" gorm:"primaryKey"`
+	ProductID      uint           `json:"product_id" gorm:"not null"`
+	UserID         uint           `json:"user_id" gorm:"not null"`
+	Rating         int            `json:"rating" gorm:"not null"`
+	Title          string         `json:"title"`
+	Body           string         `json:"body" gorm:"not null"`
+	Status         ReviewStatus   `json:"status" gorm:"default:pending"`
+	ModerationNote string         `json:"moderation_note"`
+	ModeratedBy    *uint          `json:"moderated_by"`
+	ModeratedAt    *time.Time     `json:"moderated_at"`
+	CreatedAt      time.Time      `json:"created_at"`
+	UpdatedAt      time.Time      `json:"updated_at"`
+	DeletedAt      gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relashionships
+	Product Product `json:"-" gorm:"foreignKey:ProductID;references:ID"`
+	User    User    `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
+
+type ReviewStatus string
+
+const (
+	ReviewStatusPending  ReviewStatus = "pending"
+	ReviewStatusApproved ReviewStatus = "approved"
+	ReviewStatusRejected ReviewStatus = "rejected"
+)
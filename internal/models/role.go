@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// Permissions guard the staff routes. Each one is a row of the permissions
+// table too; a new permission needs a migration that adds it, and grants it
+// to the admin role.
+const (
+	PermissionCatalogWrite    = "catalog:write"
+	PermissionOrdersWrite     = "orders:write"
+	PermissionReviewsModerate = "reviews:moderate"
+	PermissionUsersRead       = "users:read"
+	PermissionUsersWrite      = "users:write"
+	PermissionRolesManage     = "roles:manage"
+)
+
+// Role is a named set of permissions; User.Role holds its name. System roles
+// come with the schema and cannot be changed or deleted: admin has every
+// permission and customer none.
+type Role struct {
+	Name        string    `json:"name" gorm:"primaryKey"`
+	Description string    `json:"description" gorm:"not null;default:''"`
+	IsSystem    bool      `json:"is_system" gorm:"not null;default:false"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Relashionships
+	Permissions []RolePermission `json:"permissions" gorm:"foreignKey:RoleName;references:Name"`
+}
+
+type Permission struct {
+	Name        string `json:"name" gorm:"primaryKey"`
+	Description string `json:"description" gorm:"not null;default:''"`
+}
+
+type RolePermission struct {
+	RoleName   string `json:"role_name" gorm:"primaryKey"`
+	Permission string `json:"permission" gorm:"primaryKey"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// Session is a signed-in device. Its ID is the family of the refresh tokens
+// issued to the device and the sid claim of its access tokens, so revoking
+// the session signs the device out.
+type Session struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	UserID     uint       `json:"user_id" gorm:"not null"`
+	IPAddress  string     `json:"ip_address"`
+	UserAgent  string     `json:"user_agent"`
+	LastUsedAt time.Time  `json:"last_used_at" gorm:"not null"`
+	ExpiresAt  time.Time  `json:"expires_at" gorm:"not null"`
+	RevokedAt  *time.Time `json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+
+	// Relashionships
+	User User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
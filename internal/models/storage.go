@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// StoredObject is a file kept by an upload provider under a content hash.
+// RefCount tracks how many product images point at it; objects left at zero
+// are removed by the storage-gc command.
+type StoredObject struct {
+	ID              uint      `json:"id" gorm:"primaryKey"`
+	StorageProvider string    `json:"storage_provider" gorm:"not null"`
+	Key             string    `json:"key" gorm:"not null"`
+	ContentType     string    `json:"content_type"`
+	Size            int64     `json:"size" gorm:"not null;default:0"`
+	RefCount        int       `json:"ref_count" gorm:"not null;default:0"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
@@ -7,20 +7,24 @@ import (
 )
 
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey"`
-	FirstName string         `json:"first_name" gorm:"not null"`
-	LastName  string         `json:"last_name" gorm:"not null"`
-	Phone     string         `json:"phone" gorm:"not null"`
-	Email     string         `json:"email" gorm:"unique;not null"`
-	Password  string         `json:"-"`
-	Role      string         `json:"role" gorm:"default:customer"`
-	IsActive  bool           `json:"is_active" gorm:"default:true"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID              uint           `json:"id" gorm:"primaryKey"`
+	FirstName       string         `json:"first_name" gorm:"not null"`
+	LastName        string         `json:"last_name" gorm:"not null"`
+	Phone           string         `json:"phone" gorm:"not null"`
+	Email           string         `json:"email" gorm:"unique;not null"`
+	Password        string         `json:"-"`
+	Role            string         `json:"role" gorm:"default:customer"`
+	IsActive        bool           `json:"is_active" gorm:"default:true"`
+	Locale          string         `json:"locale" gorm:"not null;default:en"`
+	EmailVerifiedAt *time.Time     `json:"email_verified_at"`
+	MFAEnabledAt    *time.Time     `json:"mfa_enabled_at"`
+	CreatedAt       time.Time      `json:"created_at"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// Relashionships
 	RefreshTokens []RefreshToken `json:"-" gorm:"foreignKey:UserID;references:ID"`
+	Sessions      []Session      `json:"-" gorm:"foreignKey:UserID;references:ID"`
 	Orders        []Order        `json:"-" gorm:"foreignKey:UserID;references:ID"`
 	Cart          Cart           `json:"-" gorm:"foreignKey:UserID;references:ID"`
 }
@@ -32,11 +36,19 @@ const (
 	RoleCustomer UserRole = "customer"
 )
 
+// DefaultLocale is the locale of users who have not picked one.
+const DefaultLocale = "en"
+
+// RefreshToken is one refresh token of a session. Tokens are rotated on
+// every refresh; all tokens of a session share a FamilyID, so reuse of a
+// rotated token can revoke the whole session. Only a hash is stored.
 type RefreshToken struct {
 	ID        uint           `json:"id" gorm:"primaryKey"`
-	Token     string         `json:"token" gorm:"unique;not null"`
+	TokenHash string         `json:"-" gorm:"unique;not null"`
+	FamilyID  string         `json:"family_id" gorm:"not null;index"`
 	UserID    uint           `json:"user_id" gorm:"not null"`
 	ExpiresAt time.Time      `json:"expires_at" gorm:"not null"`
+	RotatedAt *time.Time     `json:"rotated_at"`
 	CreatedAt time.Time      `json:"created_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
 
@@ -0,0 +1,30 @@
+package models
+
+import "time"
+
+type UserTokenPurpose string
+
+const (
+	UserTokenEmailVerification UserTokenPurpose = "email_verification"
+	UserTokenPasswordReset     UserTokenPurpose = "password_reset"
+	UserTokenMFAChallenge      UserTokenPurpose = "mfa_challenge"
+)
+
+// UserToken is a single-use token handed to a user, by email or, for MFA
+// challenges, in the login response. Only a hash of the token is stored, so
+// a leaked table cannot be used to act as a user.
+type UserToken struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	UserID    uint             `json:"user_id" gorm:"not null"`
+	Purpose   UserTokenPurpose `json:"purpose" gorm:"not null"`
+	TokenHash string           `json:"-" gorm:"unique;not null"`
+	ExpiresAt time.Time        `json:"expires_at" gorm:"not null"`
+	UsedAt    *time.Time       `json:"used_at"`
+	CreatedAt time.Time        `json:"created_at"`
+	// AuthMethod is the first factor of the login an MFA challenge
+	// continues, carried into the amr claim once the code is checked.
+	AuthMethod string `json:"-" gorm:"not null;default:''"`
+
+	// Relashionships
+	User User `json:"-" gorm:"foreignKey:UserID;references:ID"`
+}
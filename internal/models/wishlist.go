@@ -0,0 +1,23 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WishlistItem struct {
+	ID                uint           `json:"id" gorm:"primaryKey"`
+	UserID            uint           `json:"user_id" gorm:"not null"`
+	ProductID         uint           `json:"product_id" gorm:"not null"`
+	NotifyBackInStock bool           `json:"notify_back_in_stock" gorm:"not null"`
+	NotifyPriceDrop   bool           `json:"notify_price_drop" gorm:"not null"`
+	UnsubscribeToken  string         `json:"-" gorm:"unique;not null"`
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// Relashionships
+	User    User    `json:"-" gorm:"foreignKey:UserID;references:ID"`
+	Product Product `json:"-" gorm:"foreignKey:ProductID;references:ID"`
+}
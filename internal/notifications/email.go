@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"net"
 	"net/smtp"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/events"
 )
 
 type SMTPConfig struct {
@@ -14,21 +17,37 @@ type SMTPConfig struct {
 	From     string
 }
 
-type SimpleEmail struct {
+// Email is a message to one recipient. HTML is optional; Text is always sent.
+type Email struct {
 	To      string
 	Subject string
-	Body    string
+	Text    string
+	HTML    string
 }
 
 type EmailNotifier struct {
-	config *SMTPConfig
+	config   *SMTPConfig
+	renderer *Renderer
 }
 
-func NewEmailNotifier(config *SMTPConfig) *EmailNotifier {
-	return &EmailNotifier{config: config}
+func NewEmailNotifier(config *SMTPConfig) (*EmailNotifier, error) {
+	renderer, err := NewRenderer()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EmailNotifier{
+		config:   config,
+		renderer: renderer,
+	}, nil
 }
 
-func (e *EmailNotifier) SendSimpleEmail(email *SimpleEmail) error {
+func (e *EmailNotifier) Send(email *Email) error {
+	msg, err := buildMessage(e.config.From, email, time.Now())
+	if err != nil {
+		return err
+	}
+
 	addr := net.JoinHostPort(e.config.Host, fmt.Sprintf("%d", e.config.Port))
 	conn, err := net.Dial("tcp", addr)
 	if err != nil {
@@ -69,22 +88,56 @@ func (e *EmailNotifier) SendSimpleEmail(email *SimpleEmail) error {
 		return err
 	}
 
-	msg := fmt.Sprintf("To: %s\r\n", email.To)
-	msg += fmt.Sprintf("Subject: %s\r\n", email.Subject)
-	msg += fmt.Sprintf("From: %s\r\n", e.config.From)
-	msg += fmt.Sprintf("\r\n%s\r\n", email.Body)
-
-	if _, err := writer.Write([]byte(msg)); err != nil {
+	if _, err := writer.Write(msg); err != nil {
 		return err
 	}
 
 	return writer.Close()
 }
 
-func (e *EmailNotifier) SendLoginNotification(email, name string) error {
-	return e.SendSimpleEmail(&SimpleEmail{
-		To:      email,
-		Subject: "Login Notification",
-		Body:    fmt.Sprintf("Hello %s, you have successfully logged in to your account. If you did not make this request, please contact support.", name),
+// SendTemplate renders template name in the recipient's locale and sends it.
+func (e *EmailNotifier) SendTemplate(to, name, locale string, data any) error {
+	rendered, err := e.renderer.Render(name, locale, data)
+	if err != nil {
+		return err
+	}
+
+	return e.Send(&Email{
+		To:      to,
+		Subject: rendered.Subject,
+		Text:    rendered.Text,
+		HTML:    rendered.HTML,
 	})
 }
+
+func (e *EmailNotifier) SendLoginNotification(email, name, locale string) error {
+	return e.SendTemplate(email, TemplateLogin, locale, LoginData{Name: name})
+}
+
+func (e *EmailNotifier) SendVerificationEmail(email, locale string, data VerifyEmailData) error {
+	return e.SendTemplate(email, TemplateVerifyEmail, locale, data)
+}
+
+func (e *EmailNotifier) SendPasswordResetEmail(email, locale string, data PasswordResetData) error {
+	return e.SendTemplate(email, TemplatePasswordReset, locale, data)
+}
+
+func (e *EmailNotifier) SendPasswordChangedNotification(email, name, locale string) error {
+	return e.SendTemplate(email, TemplatePasswordChanged, locale, LoginData{Name: name})
+}
+
+func (e *EmailNotifier) SendSessionRevokedNotification(email, name, locale string) error {
+	return e.SendTemplate(email, TemplateSessionRevoked, locale, LoginData{Name: name})
+}
+
+func (e *EmailNotifier) SendAccountLockedNotification(email, locale string, data AccountLockedData) error {
+	return e.SendTemplate(email, TemplateAccountLocked, locale, data)
+}
+
+func (e *EmailNotifier) SendBackInStockNotification(alert *events.WishlistAlert) error {
+	return e.SendTemplate(alert.Email, TemplateBackInStock, alert.Locale, alert)
+}
+
+func (e *EmailNotifier) SendPriceDropNotification(alert *events.WishlistAlert) error {
+	return e.SendTemplate(alert.Email, TemplatePriceDrop, alert.Locale, alert)
+}
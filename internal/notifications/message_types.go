@@ -1,5 +0,0 @@
-package notifications
-
-const (
-	UserLoggedInEventType = "USER_LOGGED_IN"
-)
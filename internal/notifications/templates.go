@@ -0,0 +1,281 @@
+package notifications
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"fmt"
+	htmlTemplate "html/template"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	textTemplate "text/template"
+
+	"github.com/anzhy11/go-e-commerce/internal/events"
+)
+
+const (
+	TemplateLogin           = "login"
+	TemplateBackInStock     = "back_in_stock"
+	TemplatePriceDrop       = "price_drop"
+	TemplateVerifyEmail     = "verify_email"
+	TemplatePasswordReset   = "password_reset"
+	TemplatePasswordChanged = "password_changed"
+	TemplateSessionRevoked  = "session_revoked"
+	TemplateAccountLocked   = "account_locked"
+
+	DefaultLocale = "en"
+)
+
+// Templates lists every email template.
+var Templates = []string{
+	TemplateLogin,
+	TemplateVerifyEmail,
+	TemplatePasswordReset,
+	TemplatePasswordChanged,
+	TemplateSessionRevoked,
+	TemplateAccountLocked,
+	TemplateBackInStock,
+	TemplatePriceDrop,
+}
+
+//go:embed templates/*.html templates/*.txt
+var templateFS embed.FS
+
+//go:embed locales/*.json
+var localeFS embed.FS
+
+// LoginData is the data of the templates that only greet the user: login,
+// password changed and session revoked. The wishlist templates take an
+// *events.WishlistAlert.
+type LoginData struct {
+	Name string
+}
+
+// VerifyEmailData is the data of the email verification template.
+type VerifyEmailData struct {
+	Name           string
+	URL            string
+	ExpiresInHours int
+}
+
+// PasswordResetData is the data of the password reset template.
+type PasswordResetData struct {
+	Name             string
+	URL              string
+	ExpiresInMinutes int
+}
+
+// AccountLockedData is the data of the account locked template. IPAddress
+// may be empty.
+type AccountLockedData struct {
+	Name             string
+	IPAddress        string
+	LockedForMinutes int
+}
+
+// SampleData returns representative data for template name, for previews.
+func SampleData(name string) (any, error) {
+	switch name {
+	case TemplateLogin, TemplatePasswordChanged, TemplateSessionRevoked:
+		return LoginData{Name: "Jane Doe"}, nil
+	case TemplateVerifyEmail:
+		return VerifyEmailData{
+			Name:           "Jane Doe",
+			URL:            "http://localhost:8080/api/v1/auth/verify-email?token=sample",
+			ExpiresInHours: 24,
+		}, nil
+	case TemplatePasswordReset:
+		return PasswordResetData{
+			Name:             "Jane Doe",
+			URL:              "http://localhost:3000/reset-password?token=sample",
+			ExpiresInMinutes: 60,
+		}, nil
+	case TemplateAccountLocked:
+		return AccountLockedData{
+			Name:             "Jane Doe",
+			IPAddress:        "203.0.113.7",
+			LockedForMinutes: 15,
+		}, nil
+	case TemplateBackInStock, TemplatePriceDrop:
+		return &events.WishlistAlert{
+			Email:          "jane.doe@example.com",
+			Name:           "Jane Doe",
+			ProductID:      42,
+			ProductName:    "Wireless Headphones",
+			OldPrice:       129.99,
+			NewPrice:       99.5,
+			Stock:          12,
+			UnsubscribeURL: "http://localhost:8080/api/v1/users/wishlist/unsubscribe?token=sample",
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+}
+
+// RenderedEmail is a template rendered for one recipient.
+type RenderedEmail struct {
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Renderer renders the embedded email templates. Each template has an HTML
+// and a plain text version sharing a layout, and takes its wording from the
+// catalog of the recipient's locale.
+type Renderer struct {
+	html     map[string]*htmlTemplate.Template
+	text     map[string]*textTemplate.Template
+	catalogs map[string]map[string]string
+}
+
+func NewRenderer() (*Renderer, error) {
+	r := &Renderer{
+		html:     map[string]*htmlTemplate.Template{},
+		text:     map[string]*textTemplate.Template{},
+		catalogs: map[string]map[string]string{},
+	}
+
+	if err := r.loadCatalogs(); err != nil {
+		return nil, err
+	}
+
+	// The functions are replaced for every render; these only make the
+	// templates parse.
+	funcs := r.funcs(DefaultLocale)
+
+	for _, name := range Templates {
+		html, err := htmlTemplate.New(name).Funcs(funcs).ParseFS(templateFS, "templates/layout.html", "templates/"+name+".html")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s html template: %w", name, err)
+		}
+		r.html[name] = html
+
+		text, err := textTemplate.New(name).Funcs(funcs).ParseFS(templateFS, "templates/layout.txt", "templates/"+name+".txt")
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s text template: %w", name, err)
+		}
+		r.text[name] = text
+	}
+
+	return r, nil
+}
+
+// Locales lists the locales with a catalog.
+func (r *Renderer) Locales() []string {
+	locales := make([]string, 0, len(r.catalogs))
+	for locale := range r.catalogs {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales)
+	return locales
+}
+
+// Render renders template name for locale. Unknown locales and messages
+// missing from a catalog fall back to DefaultLocale.
+func (r *Renderer) Render(name, locale string, data any) (*RenderedEmail, error) {
+	html, ok := r.html[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown email template %q", name)
+	}
+
+	locale = r.resolveLocale(locale)
+	funcs := r.funcs(locale)
+
+	htmlClone, err := html.Clone()
+	if err != nil {
+		return nil, err
+	}
+	textClone, err := r.text[name].Clone()
+	if err != nil {
+		return nil, err
+	}
+	htmlClone.Funcs(funcs)
+	textClone.Funcs(funcs)
+
+	var subject, text, body bytes.Buffer
+	if err := textClone.ExecuteTemplate(&subject, "subject", data); err != nil {
+		return nil, err
+	}
+	if err := textClone.ExecuteTemplate(&text, "layout", data); err != nil {
+		return nil, err
+	}
+	if err := htmlClone.ExecuteTemplate(&body, "layout", data); err != nil {
+		return nil, err
+	}
+
+	return &RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    strings.TrimSpace(text.String()) + "\n",
+		HTML:    body.String(),
+	}, nil
+}
+
+func (r *Renderer) loadCatalogs() error {
+	files, err := localeFS.ReadDir("locales")
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		data, err := localeFS.ReadFile(path.Join("locales", file.Name()))
+		if err != nil {
+			return err
+		}
+
+		catalog := map[string]string{}
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			return fmt.Errorf("failed to parse locale %s: %w", file.Name(), err)
+		}
+		r.catalogs[strings.TrimSuffix(file.Name(), path.Ext(file.Name()))] = catalog
+	}
+
+	if _, ok := r.catalogs[DefaultLocale]; !ok {
+		return fmt.Errorf("missing catalog for default locale %q", DefaultLocale)
+	}
+	return nil
+}
+
+// resolveLocale maps a user locale such as "fr-CA" to a catalog.
+func (r *Renderer) resolveLocale(locale string) string {
+	locale = strings.ToLower(strings.ReplaceAll(locale, "_", "-"))
+	if _, ok := r.catalogs[locale]; ok {
+		return locale
+	}
+
+	language, _, _ := strings.Cut(locale, "-")
+	if _, ok := r.catalogs[language]; ok {
+		return language
+	}
+	return DefaultLocale
+}
+
+func (r *Renderer) translate(locale, key string) string {
+	if message, ok := r.catalogs[locale][key]; ok {
+		return message
+	}
+	if message, ok := r.catalogs[DefaultLocale][key]; ok {
+		return message
+	}
+	return key
+}
+
+func (r *Renderer) funcs(locale string) map[string]any {
+	return map[string]any{
+		"locale": func() string {
+			return locale
+		},
+		"t": func(key string, args ...any) string {
+			message := r.translate(locale, key)
+			if len(args) == 0 {
+				return message
+			}
+			return fmt.Sprintf(message, args...)
+		},
+		"price": func(amount float64) string {
+			formatted := strconv.FormatFloat(amount, 'f', 2, 64)
+			return strings.Replace(formatted, ".", r.translate(locale, "number.decimal_separator"), 1)
+		},
+	}
+}
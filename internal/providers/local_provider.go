@@ -1,24 +1,53 @@
 package providers
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
+	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	"github.com/anzhy11/go-e-commerce/internal/storage"
 )
 
+var (
+	ErrInvalidSignature = errors.New("invalid signature")
+	ErrSignatureExpired = errors.New("signature expired")
+	ErrInvalidPath      = errors.New("invalid path")
+)
+
+// LocalSignedPath is where the API serves presigned requests for the local
+// provider, standing in for the storage endpoint S3 would provide.
+const LocalSignedPath = "/api/v1/storage/"
+
 type LocalUploadProvider struct {
-	basePath string
+	basePath      string
+	baseURL       string
+	signingSecret []byte
 }
 
-func NewLocalUploadProvider(basePath string) interfaces.Upload {
+func NewLocalUploadProvider(basePath, baseURL, signingSecret string) interfaces.Upload {
 	return &LocalUploadProvider{
-		basePath: basePath,
+		basePath:      basePath,
+		baseURL:       strings.TrimRight(baseURL, "/"),
+		signingSecret: []byte(signingSecret),
 	}
 }
 
+func (l *LocalUploadProvider) Name() string {
+	return storage.ProviderLocal
+}
+
 func (l *LocalUploadProvider) UploadFile(file *multipart.FileHeader, path string) (string, error) {
 	fullPath := filepath.Join(l.basePath, path)
 
@@ -50,10 +79,162 @@ func (l *LocalUploadProvider) UploadFile(file *multipart.FileHeader, path string
 		return "", err
 	}
 
-	return fmt.Sprintf("/uploads/%s", path), nil
+	return path, nil
+}
+
+func (l *LocalUploadProvider) UploadObject(body io.Reader, path, _ string) (string, error) {
+	fullPath := filepath.Join(l.basePath, path)
+
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0o755); err != nil {
+		return "", err
+	}
+
+	dest, destErr := os.Create(fullPath)
+	if destErr != nil {
+		return "", destErr
+	}
+	defer func() {
+		if err := dest.Close(); err != nil {
+			fmt.Println("Error closing file:", err)
+		}
+	}()
+
+	if _, err := dest.ReadFrom(body); err != nil {
+		return "", err
+	}
+
+	return path, nil
 }
 
 func (l *LocalUploadProvider) DeleteFile(filename string) error {
 	fullPath := filepath.Join(l.basePath, filename)
 	return os.Remove(fullPath)
 }
+
+func (l *LocalUploadProvider) PresignUpload(path, contentType string, expires time.Duration) (*interfaces.PresignedRequest, error) {
+	request, err := l.presign(http.MethodPut, path, contentType, expires)
+	if err != nil {
+		return nil, err
+	}
+
+	request.Headers = map[string]string{"Content-Type": contentType}
+	return request, nil
+}
+
+func (l *LocalUploadProvider) PresignDownload(path string, expires time.Duration) (*interfaces.PresignedRequest, error) {
+	return l.presign(http.MethodGet, path, "", expires)
+}
+
+func (l *LocalUploadProvider) StatObject(path string) (*interfaces.ObjectInfo, error) {
+	fullPath, err := l.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, interfaces.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	// Files on disk carry no metadata, so sniff the type from the content.
+	file, err := os.Open(fullPath)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			fmt.Println("Error closing file:", err)
+		}
+	}()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return &interfaces.ObjectInfo{
+		Size:        info.Size(),
+		ContentType: http.DetectContentType(head[:n]),
+	}, nil
+}
+
+func (l *LocalUploadProvider) OpenObject(path string) (io.ReadCloser, error) {
+	fullPath, err := l.resolve(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, interfaces.ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return file, nil
+}
+
+// VerifySignedRequest checks a request made to a URL issued by PresignUpload
+// or PresignDownload. contentType is only part of the signature for uploads.
+func (l *LocalUploadProvider) VerifySignedRequest(method, path, contentType, expires, signature string) error {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+
+	expected := l.sign(method, path, contentType, expiresAt)
+	if !hmac.Equal([]byte(expected), []byte(signature)) {
+		return ErrInvalidSignature
+	}
+
+	if time.Now().Unix() > expiresAt {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+// FilePath returns the location on disk of a stored object.
+func (l *LocalUploadProvider) FilePath(path string) (string, error) {
+	return l.resolve(path)
+}
+
+// Helper
+func (l *LocalUploadProvider) presign(method, path, contentType string, expires time.Duration) (*interfaces.PresignedRequest, error) {
+	if _, err := l.resolve(path); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(expires)
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expiresAt.Unix(), 10))
+	query.Set("signature", l.sign(method, path, contentType, expiresAt.Unix()))
+
+	return &interfaces.PresignedRequest{
+		URL:       fmt.Sprintf("%s%s%s?%s", l.baseURL, LocalSignedPath, path, query.Encode()),
+		Method:    method,
+		Headers:   map[string]string{},
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (l *LocalUploadProvider) sign(method, path, contentType string, expiresAt int64) string {
+	mac := hmac.New(sha256.New, l.signingSecret)
+	fmt.Fprintf(mac, "%s\n%s\n%s\n%d", method, path, contentType, expiresAt)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// resolve maps an object path to the filesystem, refusing anything that
+// would escape the base directory.
+func (l *LocalUploadProvider) resolve(path string) (string, error) {
+	cleaned := filepath.Clean("/" + path)
+	if cleaned == "/" || cleaned != "/"+path {
+		return "", ErrInvalidPath
+	}
+	return filepath.Join(l.basePath, cleaned), nil
+}
@@ -2,28 +2,34 @@ package providers
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log"
 	"mime/multipart"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/feature/s3/manager"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 
 	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	"github.com/anzhy11/go-e-commerce/internal/storage"
 )
 
 type S3UploadProvider struct {
 	client     *s3.Client
+	presigner  *s3.PresignClient
 	uploader   *manager.Uploader
 	bucketName string
 	endpoint   string
 }
 
-func NewS3UploadProvider(cfg *appConfig.Config) interfaces.Upload {
+func NewS3UploadProvider(cfg *appConfig.Config) (interfaces.Upload, error) {
 	awsConfig, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion(cfg.AWS.Region),
 		config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(
@@ -34,7 +40,7 @@ func NewS3UploadProvider(cfg *appConfig.Config) interfaces.Upload {
 	)
 
 	if err != nil {
-		panic("Failed to load AWS config: " + err.Error())
+		return nil, fmt.Errorf("failed to load aws config: %w", err)
 	}
 
 	client := s3.NewFromConfig(awsConfig, func(o *s3.Options) {
@@ -46,10 +52,15 @@ func NewS3UploadProvider(cfg *appConfig.Config) interfaces.Upload {
 
 	return &S3UploadProvider{
 		client:     client,
+		presigner:  s3.NewPresignClient(client),
 		uploader:   manager.NewUploader(client),
 		bucketName: cfg.AWS.S3Bucket,
 		endpoint:   cfg.AWS.S3Endpoint,
-	}
+	}, nil
+}
+
+func (s *S3UploadProvider) Name() string {
+	return storage.ProviderS3
 }
 
 func (s *S3UploadProvider) UploadFile(file *multipart.FileHeader, path string) (string, error) {
@@ -67,7 +78,7 @@ func (s *S3UploadProvider) UploadFile(file *multipart.FileHeader, path string) (
 
 	result, err := s.uploader.Upload(context.TODO(), &s3.PutObjectInput{
 		Bucket: aws.String(s.bucketName),
-		Key:    aws.String(path + file.Filename),
+		Key:    aws.String(path),
 		Body:   src,
 	})
 
@@ -78,6 +89,21 @@ func (s *S3UploadProvider) UploadFile(file *multipart.FileHeader, path string) (
 	return *result.Key, nil
 }
 
+func (s *S3UploadProvider) UploadObject(body io.Reader, path, contentType string) (string, error) {
+	result, err := s.uploader.Upload(context.TODO(), &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(path),
+		Body:        body,
+		ContentType: aws.String(contentType),
+	})
+
+	if err != nil {
+		return "", err
+	}
+
+	return *result.Key, nil
+}
+
 func (s *S3UploadProvider) DeleteFile(filename string) error {
 	_, err := s.client.DeleteObject(context.TODO(), &s3.DeleteObjectInput{
 		Bucket: aws.String(s.bucketName),
@@ -86,3 +112,82 @@ func (s *S3UploadProvider) DeleteFile(filename string) error {
 
 	return err
 }
+
+func (s *S3UploadProvider) PresignUpload(path, contentType string, expires time.Duration) (*interfaces.PresignedRequest, error) {
+	request, err := s.presigner.PresignPutObject(context.TODO(), &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucketName),
+		Key:         aws.String(path),
+		ContentType: aws.String(contentType),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, err
+	}
+
+	return toPresignedRequest(request.URL, request.Method, request.SignedHeader, expires), nil
+}
+
+func (s *S3UploadProvider) PresignDownload(path string, expires time.Duration) (*interfaces.PresignedRequest, error) {
+	request, err := s.presigner.PresignGetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(path),
+	}, s3.WithPresignExpires(expires))
+	if err != nil {
+		return nil, err
+	}
+
+	return toPresignedRequest(request.URL, request.Method, request.SignedHeader, expires), nil
+}
+
+func (s *S3UploadProvider) StatObject(path string) (*interfaces.ObjectInfo, error) {
+	result, err := s.client.HeadObject(context.TODO(), &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		var notFound *types.NotFound
+		if errors.As(err, &notFound) {
+			return nil, interfaces.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return &interfaces.ObjectInfo{
+		Size:        aws.ToInt64(result.ContentLength),
+		ContentType: aws.ToString(result.ContentType),
+	}, nil
+}
+
+func (s *S3UploadProvider) OpenObject(path string) (io.ReadCloser, error) {
+	result, err := s.client.GetObject(context.TODO(), &s3.GetObjectInput{
+		Bucket: aws.String(s.bucketName),
+		Key:    aws.String(path),
+	})
+	if err != nil {
+		var noSuchKey *types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, interfaces.ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return result.Body, nil
+}
+
+// Helper
+func toPresignedRequest(url, method string, signedHeader map[string][]string, expires time.Duration) *interfaces.PresignedRequest {
+	headers := make(map[string]string, len(signedHeader))
+	for name, values := range signedHeader {
+		// Host is set by the HTTP client from the URL.
+		if name == "Host" || len(values) == 0 {
+			continue
+		}
+		headers[name] = values[0]
+	}
+
+	return &interfaces.PresignedRequest{
+		URL:       url,
+		Method:    method,
+		Headers:   headers,
+		ExpiresAt: time.Now().Add(expires),
+	}
+}
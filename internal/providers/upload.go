@@ -0,0 +1,44 @@
+package providers
+
+import (
+	"errors"
+	"fmt"
+
+	appConfig "github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	"github.com/anzhy11/go-e-commerce/internal/storage"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+)
+
+var ErrNoSigningSecret = errors.New("UPLOAD_SIGNING_SECRET is required by the local upload provider")
+
+// NewUploadProvider returns the provider selected by UPLOAD_PROVIDER. Outside
+// release mode the local provider signs with a secret generated for this
+// process when UPLOAD_SIGNING_SECRET is empty, so its URLs stop working on
+// restart.
+func NewUploadProvider(cfg *appConfig.Config, log *zerolog.Logger) (interfaces.Upload, error) {
+	switch cfg.Upload.Provider {
+	case storage.ProviderLocal, "":
+		secret := cfg.Upload.SigningSecret
+		if secret == "" {
+			if cfg.Server.GinMode == gin.ReleaseMode {
+				return nil, ErrNoSigningSecret
+			}
+
+			var err error
+			if secret, err = encryption.GenerateRandomString(32); err != nil {
+				return nil, err
+			}
+			log.Warn().Msg("No upload signing secret set, signing with a temporary secret")
+		}
+		return NewLocalUploadProvider(cfg.Upload.Path, cfg.Server.AppURL, secret), nil
+	case storage.ProviderS3:
+		return NewS3UploadProvider(cfg)
+	case storage.ProviderMemory:
+		return NewMemoryUploadProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown upload provider %q", cfg.Upload.Provider)
+	}
+}
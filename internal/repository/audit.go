@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type AuditRepositoryInterface interface {
+	CreateAuditLogTx(data *models.AuditLog, tx *gorm.DB) error
+	GetAuditLogs(filter *dto.AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error)
+}
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepo(db *gorm.DB) AuditRepositoryInterface {
+	return &AuditRepository{
+		db: db,
+	}
+}
+
+func (r *AuditRepository) CreateAuditLogTx(data *models.AuditLog, tx *gorm.DB) error {
+	return tx.Omit("Actor", "User").Create(data).Error
+}
+
+// GetAuditLogs returns the newest entries first, with their actor, deleted
+// or not.
+func (r *AuditRepository) GetAuditLogs(filter *dto.AuditLogFilter, offset, limit int) ([]models.AuditLog, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.AuditLog{}).Scopes(filterAuditLogs(filter)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var logs []models.AuditLog
+	if err := r.db.Preload("Actor", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).
+		Scopes(filterAuditLogs(filter)).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&logs).Error; err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+func filterAuditLogs(filter *dto.AuditLogFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter == nil {
+			return db
+		}
+
+		if filter.UserID != 0 {
+			db = db.Where("user_id = ?", filter.UserID)
+		}
+		if filter.ActorID != 0 {
+			db = db.Where("actor_id = ?", filter.ActorID)
+		}
+		if filter.Action != "" {
+			db = db.Where("action = ?", filter.Action)
+		}
+		return db
+	}
+}
@@ -2,15 +2,17 @@ package repository
 
 import (
 	"errors"
+	"time"
 
 	"github.com/anzhy11/go-e-commerce/internal/models"
 	"gorm.io/gorm"
 )
 
 type AuthRepositoryInterface interface {
-	GetRefreshToken(token string) (*models.RefreshToken, error)
-	CreateRefreshToken(data *models.RefreshToken) error
-	DeleteRefreshToken(data *models.RefreshToken)
+	GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error)
+	CreateRefreshTokenTx(data *models.RefreshToken, tx *gorm.DB) error
+	RotateRefreshTokenTx(data *models.RefreshToken, tx *gorm.DB) (bool, error)
+	DeleteExpiredRefreshTokens(before time.Time) (int64, error)
 }
 
 type AuthRepository struct {
@@ -23,19 +25,41 @@ func NewAuthRepo(db *gorm.DB) AuthRepositoryInterface {
 	}
 }
 
-func (r *AuthRepository) GetRefreshToken(token string) (*models.RefreshToken, error) {
+func (r *AuthRepository) GetRefreshTokenByHash(tokenHash string) (*models.RefreshToken, error) {
 	var refreshToken models.RefreshToken
-	if err := r.db.Where("token = ?", token).First(&refreshToken).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+	if err := r.db.Where("token_hash = ?", tokenHash).First(&refreshToken).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
 		return nil, err
 	}
 
 	return &refreshToken, nil
 }
 
-func (r *AuthRepository) CreateRefreshToken(data *models.RefreshToken) error {
-	return r.db.Create(&data).Error
+func (r *AuthRepository) CreateRefreshTokenTx(data *models.RefreshToken, tx *gorm.DB) error {
+	return tx.Create(data).Error
 }
 
-func (r *AuthRepository) DeleteRefreshToken(data *models.RefreshToken) {
-	r.db.Delete(&data)
+// RotateRefreshTokenTx marks the token as exchanged for a new one. It reports
+// false when the token was already rotated, so two concurrent refreshes with
+// the same token cannot both succeed.
+func (r *AuthRepository) RotateRefreshTokenTx(data *models.RefreshToken, tx *gorm.DB) (bool, error) {
+	now := time.Now()
+	result := tx.Model(&models.RefreshToken{}).
+		Where("id = ? AND rotated_at IS NULL", data.ID).
+		Update("rotated_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	data.RotatedAt = &now
+	return true, nil
+}
+
+// DeleteExpiredRefreshTokens permanently deletes tokens, revoked or not, that
+// expired before the given time.
+func (r *AuthRepository) DeleteExpiredRefreshTokens(before time.Time) (int64, error) {
+	result := r.db.Unscoped().Where("expires_at < ?", before).Delete(&models.RefreshToken{})
+	return result.RowsAffected, result.Error
 }
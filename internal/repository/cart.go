@@ -7,6 +7,7 @@ import (
 
 type CartRepositoryInterface interface {
 	CreateCart(cart *models.Cart) error
+	CreateCartTx(cart *models.Cart, tx *gorm.DB) error
 	GetCartByUserID(userID uint) (*models.Cart, error)
 	UpdateCart(cart *models.Cart) error
 	GetCartItemByCartID(cartID, productID uint) (*models.CartItem, error)
@@ -29,6 +30,10 @@ func (r *CartRepository) CreateCart(cart *models.Cart) error {
 	return r.db.Create(&cart).Error
 }
 
+func (r *CartRepository) CreateCartTx(cart *models.Cart, tx *gorm.DB) error {
+	return tx.Create(cart).Error
+}
+
 func (r *CartRepository) GetCartByUserID(userID uint) (*models.Cart, error) {
 	var cart models.Cart
 	if err := r.db.Preload("CartItems.Product.Category").Where("user_id = ?", userID).First(&cart).Error; err != nil {
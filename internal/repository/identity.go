@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdentityRepositoryInterface interface {
+	GetUserIdentity(provider, subject string) (*models.UserIdentity, error)
+	CreateUserIdentityTx(data *models.UserIdentity, tx *gorm.DB) error
+	UpdateUserIdentityLoginTx(data *models.UserIdentity, tx *gorm.DB) error
+	CreateOIDCLoginState(data *models.OIDCLoginState) error
+	TakeOIDCLoginState(stateHash string) (*models.OIDCLoginState, error)
+	DeleteExpiredOIDCLoginStates(before time.Time) (int64, error)
+}
+
+type IdentityRepository struct {
+	db *gorm.DB
+}
+
+func NewIdentityRepo(db *gorm.DB) IdentityRepositoryInterface {
+	return &IdentityRepository{
+		db: db,
+	}
+}
+
+func (r *IdentityRepository) GetUserIdentity(provider, subject string) (*models.UserIdentity, error) {
+	var identity models.UserIdentity
+	if err := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &identity, nil
+}
+
+func (r *IdentityRepository) CreateUserIdentityTx(data *models.UserIdentity, tx *gorm.DB) error {
+	return tx.Create(data).Error
+}
+
+// UpdateUserIdentityLoginTx saves the email and last login of an identity.
+func (r *IdentityRepository) UpdateUserIdentityLoginTx(data *models.UserIdentity, tx *gorm.DB) error {
+	return tx.Model(data).Updates(map[string]any{"email": data.Email, "last_login_at": data.LastLoginAt}).Error
+}
+
+func (r *IdentityRepository) CreateOIDCLoginState(data *models.OIDCLoginState) error {
+	return r.db.Create(data).Error
+}
+
+// TakeOIDCLoginState deletes a login state and returns it, so a state is
+// only ever redeemed once. The state is empty when there was none.
+func (r *IdentityRepository) TakeOIDCLoginState(stateHash string) (*models.OIDCLoginState, error) {
+	var states []models.OIDCLoginState
+	if err := r.db.Clauses(clause.Returning{}).
+		Where("state_hash = ?", stateHash).
+		Delete(&states).Error; err != nil {
+		return nil, err
+	}
+
+	if len(states) == 0 {
+		return &models.OIDCLoginState{}, nil
+	}
+	return &states[0], nil
+}
+
+func (r *IdentityRepository) DeleteExpiredOIDCLoginStates(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.OIDCLoginState{})
+	return result.RowsAffected, result.Error
+}
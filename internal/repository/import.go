@@ -0,0 +1,54 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type ImportRepositoryInterface interface {
+	CreateImportJob(job *models.ImportJob) error
+	GetImportJobById(jobID uint) (*models.ImportJob, error)
+	UpdateImportJob(job *models.ImportJob) error
+	FailStaleImportJobs(before time.Time, message string) (int64, error)
+}
+
+type ImportRepository struct {
+	db *gorm.DB
+}
+
+func NewImportRepo(db *gorm.DB) ImportRepositoryInterface {
+	return &ImportRepository{
+		db: db,
+	}
+}
+
+func (r *ImportRepository) CreateImportJob(job *models.ImportJob) error {
+	return r.db.Create(job).Error
+}
+
+func (r *ImportRepository) GetImportJobById(jobID uint) (*models.ImportJob, error) {
+	var job models.ImportJob
+	if err := r.db.First(&job, jobID).Error; err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (r *ImportRepository) UpdateImportJob(job *models.ImportJob) error {
+	return r.db.Save(job).Error
+}
+
+// FailStaleImportJobs marks the pending and processing jobs not saved since
+// before as failed.
+func (r *ImportRepository) FailStaleImportJobs(before time.Time, message string) (int64, error) {
+	result := r.db.Model(&models.ImportJob{}).
+		Where("status IN ? AND updated_at < ?", []models.ImportJobStatus{models.ImportJobStatusPending, models.ImportJobStatusProcessing}, before).
+		Updates(map[string]any{
+			"status":      models.ImportJobStatusFailed,
+			"message":     message,
+			"finished_at": time.Now(),
+		})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LoginThrottleRepositoryInterface interface {
+	GetBlockedLoginThrottles(keys []string, now time.Time) ([]models.LoginThrottle, error)
+	LockLoginThrottleTx(key string, tx *gorm.DB) (*models.LoginThrottle, error)
+	SaveLoginThrottleTx(throttle *models.LoginThrottle, tx *gorm.DB) error
+	DeleteLoginThrottle(key string) error
+	DeleteStaleLoginThrottles(before time.Time) (int64, error)
+}
+
+type LoginThrottleRepository struct {
+	db *gorm.DB
+}
+
+func NewLoginThrottleRepo(db *gorm.DB) LoginThrottleRepositoryInterface {
+	return &LoginThrottleRepository{
+		db: db,
+	}
+}
+
+// GetBlockedLoginThrottles returns the throttles among keys that refuse logins
+// at now.
+func (r *LoginThrottleRepository) GetBlockedLoginThrottles(keys []string, now time.Time) ([]models.LoginThrottle, error) {
+	var throttles []models.LoginThrottle
+	if err := r.db.Where("key IN ? AND blocked_until > ?", keys, now).Find(&throttles).Error; err != nil {
+		return nil, err
+	}
+	return throttles, nil
+}
+
+// LockLoginThrottleTx locks the throttle of key for the rest of the
+// transaction, creating it first if needed, so concurrent failures are all
+// counted.
+func (r *LoginThrottleRepository) LockLoginThrottleTx(key string, tx *gorm.DB) (*models.LoginThrottle, error) {
+	if err := tx.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&models.LoginThrottle{Key: key, LastFailedAt: time.Now()}).Error; err != nil {
+		return nil, err
+	}
+
+	var throttle models.LoginThrottle
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("key = ?", key).
+		First(&throttle).Error; err != nil {
+		return nil, err
+	}
+	return &throttle, nil
+}
+
+func (r *LoginThrottleRepository) SaveLoginThrottleTx(throttle *models.LoginThrottle, tx *gorm.DB) error {
+	return tx.Save(throttle).Error
+}
+
+func (r *LoginThrottleRepository) DeleteLoginThrottle(key string) error {
+	return r.db.Where("key = ?", key).Delete(&models.LoginThrottle{}).Error
+}
+
+// DeleteStaleLoginThrottles deletes the throttles with no failure and no
+// block after before.
+func (r *LoginThrottleRepository) DeleteStaleLoginThrottles(before time.Time) (int64, error) {
+	result := r.db.Where("last_failed_at < ? AND (blocked_until IS NULL OR blocked_until < ?)", before, before).
+		Delete(&models.LoginThrottle{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type MFARepositoryInterface interface {
+	GetUserTOTP(userID uint) (*models.UserTOTP, error)
+	SaveUserTOTP(data *models.UserTOTP) error
+	UseTOTPStepTx(userID uint, step int64, tx *gorm.DB) (bool, error)
+	DeleteUserTOTPTx(userID uint, tx *gorm.DB) error
+	CountRecoveryCodes(userID uint) (int64, error)
+	ReplaceRecoveryCodesTx(userID uint, codeHashes []string, tx *gorm.DB) error
+	UseRecoveryCodeTx(userID uint, codeHash string, tx *gorm.DB) (bool, error)
+}
+
+type MFARepository struct {
+	db *gorm.DB
+}
+
+func NewMFARepo(db *gorm.DB) MFARepositoryInterface {
+	return &MFARepository{
+		db: db,
+	}
+}
+
+func (r *MFARepository) GetUserTOTP(userID uint) (*models.UserTOTP, error) {
+	var totp models.UserTOTP
+	if err := r.db.Where("user_id = ?", userID).First(&totp).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &totp, nil
+}
+
+// SaveUserTOTP creates the authenticator of a user or replaces its secret.
+func (r *MFARepository) SaveUserTOTP(data *models.UserTOTP) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"secret", "last_used_step", "updated_at"}),
+	}).Create(data).Error
+}
+
+// UseTOTPStepTx records that the code of step was used. It reports false when
+// that step or a later one was already used, so a code works only once even
+// under concurrent requests.
+func (r *MFARepository) UseTOTPStepTx(userID uint, step int64, tx *gorm.DB) (bool, error) {
+	result := tx.Model(&models.UserTOTP{}).
+		Where("user_id = ? AND last_used_step < ?", userID, step).
+		Updates(map[string]any{"last_used_step": step, "updated_at": time.Now()})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *MFARepository) DeleteUserTOTPTx(userID uint, tx *gorm.DB) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("user_id = ?", userID).Delete(&models.UserTOTP{}).Error
+}
+
+// CountRecoveryCodes counts the unused recovery codes of a user.
+func (r *MFARepository) CountRecoveryCodes(userID uint) (int64, error) {
+	var count int64
+	err := r.db.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND used_at IS NULL", userID).
+		Count(&count).Error
+	return count, err
+}
+
+// ReplaceRecoveryCodesTx deletes every recovery code of a user and stores
+// the given hashes instead.
+func (r *MFARepository) ReplaceRecoveryCodesTx(userID uint, codeHashes []string, tx *gorm.DB) error {
+	if err := tx.Where("user_id = ?", userID).Delete(&models.MFARecoveryCode{}).Error; err != nil {
+		return err
+	}
+
+	codes := make([]models.MFARecoveryCode, len(codeHashes))
+	for i, hash := range codeHashes {
+		codes[i] = models.MFARecoveryCode{UserID: userID, CodeHash: hash}
+	}
+	return tx.Create(&codes).Error
+}
+
+// UseRecoveryCodeTx marks a recovery code used. It reports false when the
+// user has no such unused code.
+func (r *MFARepository) UseRecoveryCodeTx(userID uint, codeHash string, tx *gorm.DB) (bool, error) {
+	result := tx.Model(&models.MFARecoveryCode{}).
+		Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
@@ -3,6 +3,7 @@ package repository
 import (
 	"github.com/anzhy11/go-e-commerce/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OrderRepositoryInterface interface {
@@ -12,10 +13,11 @@ type OrderRepositoryInterface interface {
 	CreateOrderTX(data *models.Order, tx *gorm.DB) error
 	GetOrderByIdTx(orderID uint, tx *gorm.DB) (*models.Order, error)
 	GetCartByUserIDTx(userID uint, tx *gorm.DB) (*models.Cart, error)
+	GetOrderForUpdateTx(orderID uint, tx *gorm.DB) (*models.Order, error)
+	UpdateOrderStatusTx(order *models.Order, tx *gorm.DB) error
 	BeginTx() *gorm.DB
-	CommitTx(tx *gorm.DB)
+	CommitTx(tx *gorm.DB) error
 	RollbackTx(tx *gorm.DB)
-	UpdateProductStockTx(product *models.Product, tx *gorm.DB) error
 	ClearCartTx(cartID uint, tx *gorm.DB) error
 }
 
@@ -71,18 +73,31 @@ func (r *OrderRepository) GetOrderByIdTx(orderID uint, tx *gorm.DB) (*models.Ord
 	return &order, nil
 }
 
+// GetOrderForUpdateTx loads an order with its items and locks the order row
+// until the transaction ends.
+func (r *OrderRepository) GetOrderForUpdateTx(orderID uint, tx *gorm.DB) (*models.Order, error) {
+	var order models.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Table: clause.Table{Name: clause.CurrentTable}}).
+		Preload("OrderItems.Product.Category").
+		Where("id = ?", orderID).
+		First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *OrderRepository) UpdateOrderStatusTx(order *models.Order, tx *gorm.DB) error {
+	return tx.Model(order).Update("status", order.Status).Error
+}
+
 func (r *OrderRepository) GetCartByUserIDTx(userID uint, tx *gorm.DB) (*models.Cart, error) {
 	var cart models.Cart
-	if err := tx.Preload("CartItems.Product").Where("user_id = ?", userID).First(&cart).Error; err != nil {
+	if err := tx.Preload("CartItems").Where("user_id = ?", userID).First(&cart).Error; err != nil {
 		return nil, err
 	}
 	return &cart, nil
 }
 
-func (r *OrderRepository) UpdateProductStockTx(product *models.Product, tx *gorm.DB) error {
-	return tx.Save(product).Error
-}
-
 func (r *OrderRepository) ClearCartTx(cartID uint, tx *gorm.DB) error {
 	return tx.Where("cart_id = ?", cartID).Delete(&models.CartItem{}).Error
 }
@@ -94,8 +109,8 @@ func (r *OrderRepository) BeginTx() *gorm.DB {
 }
 
 // commit
-func (r *OrderRepository) CommitTx(tx *gorm.DB) {
-	tx.Commit()
+func (r *OrderRepository) CommitTx(tx *gorm.DB) error {
+	return tx.Commit().Error
 }
 
 // rollback
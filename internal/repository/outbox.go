@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type OutboxRepositoryInterface interface {
+	CreateOutboxMessagesTx(messages []models.OutboxMessage, tx *gorm.DB) error
+	ClaimOutboxMessages(limit int, lease time.Duration) ([]models.OutboxMessage, error)
+	MarkOutboxMessagePublished(messageID uint) error
+	MarkOutboxMessageFailed(message *models.OutboxMessage) error
+	DeletePublishedOutboxMessages(before time.Time) (int64, error)
+}
+
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepo(db *gorm.DB) OutboxRepositoryInterface {
+	return &OutboxRepository{
+		db: db,
+	}
+}
+
+func (r *OutboxRepository) CreateOutboxMessagesTx(messages []models.OutboxMessage, tx *gorm.DB) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	return tx.Create(&messages).Error
+}
+
+// ClaimOutboxMessages picks pending messages that are due and pushes their
+// available_at past the lease, so other relays skip them while this one
+// publishes. A relay that dies mid-batch leaves them to be retried once the
+// lease runs out.
+func (r *OutboxRepository) ClaimOutboxMessages(limit int, lease time.Duration) ([]models.OutboxMessage, error) {
+	var messages []models.OutboxMessage
+
+	err := r.db.Raw(`
+		UPDATE outbox
+		SET available_at = ?
+		WHERE id IN (
+			SELECT id FROM outbox
+			WHERE status = ? AND available_at <= ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`,
+		time.Now().Add(lease), models.OutboxStatusPending, time.Now(), limit,
+	).Scan(&messages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return messages, nil
+}
+
+func (r *OutboxRepository) MarkOutboxMessagePublished(messageID uint) error {
+	return r.db.Model(&models.OutboxMessage{}).
+		Where("id = ?", messageID).
+		Updates(map[string]any{
+			"status":       models.OutboxStatusPublished,
+			"published_at": time.Now(),
+			"last_error":   "",
+		}).Error
+}
+
+// MarkOutboxMessageFailed saves the attempt count, next attempt time, error
+// and status set by the relay.
+func (r *OutboxRepository) MarkOutboxMessageFailed(message *models.OutboxMessage) error {
+	return r.db.Model(&models.OutboxMessage{}).
+		Where("id = ?", message.ID).
+		Updates(map[string]any{
+			"status":       message.Status,
+			"attempts":     message.Attempts,
+			"available_at": message.AvailableAt,
+			"last_error":   message.LastError,
+		}).Error
+}
+
+func (r *OutboxRepository) DeletePublishedOutboxMessages(before time.Time) (int64, error) {
+	result := r.db.
+		Where("status = ? AND published_at < ?", models.OutboxStatusPublished, before).
+		Delete(&models.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
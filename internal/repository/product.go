@@ -1,8 +1,10 @@
 package repository
 
 import (
+	"github.com/anzhy11/go-e-commerce/internal/dto"
 	"github.com/anzhy11/go-e-commerce/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ProductRepositoryInterface interface {
@@ -11,15 +13,35 @@ type ProductRepositoryInterface interface {
 	GetCategoryById(categoryID uint) (*models.Category, error)
 	UpdateCategory(category *models.Category) error
 	DeleteCategory(categoryID uint) error
+	CreateCategoryAttribute(attribute *models.CategoryAttribute) error
+	GetCategoryAttributes(categoryID uint) ([]models.CategoryAttribute, error)
+	GetAllCategoryAttributes() ([]models.CategoryAttribute, error)
+	GetCategoryAttributeById(categoryID, attributeID uint) (*models.CategoryAttribute, error)
+	UpdateCategoryAttribute(attribute *models.CategoryAttribute) error
+	DeleteCategoryAttribute(categoryID, attributeID uint) error
 	CreateProduct(product *models.Product) error
-	GetProducts(offset, limit int) ([]models.Product, int64, error)
-	GetProductsCount() int64
+	CreateProductTx(product *models.Product, tx *gorm.DB) error
+	GetProducts(filter *dto.ProductFilter, offset, limit int) ([]models.Product, int64, error)
+	GetProductsCount(filter *dto.ProductFilter) int64
 	GetProductById(productID uint) (*models.Product, error)
+	GetProductBySKU(sku string) (*models.Product, error)
+	GetProductsInBatches(batchSize int, fn func(products []models.Product) error) error
 	UpdateProduct(product *models.Product) error
+	UpdateProductTx(product *models.Product, tx *gorm.DB) error
+	UpdateProductStockTx(product *models.Product, tx *gorm.DB) error
 	DeleteProduct(productID uint) error
-	UploadProductImage(image *models.ProductImage) error
-	DeleteProductImage(imageID uint) error
-	CountProductImage(productID uint) int64
+	LockProductTx(productID uint, tx *gorm.DB) error
+	GetProductForUpdateTx(productID uint, tx *gorm.DB) (*models.Product, error)
+	CreateProductImageTx(image *models.ProductImage, tx *gorm.DB) error
+	GetProductImages(productID uint) ([]models.ProductImage, error)
+	GetProductImage(productID, imageID uint) (*models.ProductImage, error)
+	FirstProductImageTx(productID uint, tx *gorm.DB) (*models.ProductImage, error)
+	UpdateProductImage(image *models.ProductImage) error
+	DeleteProductImageTx(image *models.ProductImage, tx *gorm.DB) error
+	CountProductImagesTx(productID uint, tx *gorm.DB) (int64, error)
+	NextProductImagePositionTx(productID uint, tx *gorm.DB) (int, error)
+	SetPrimaryProductImageTx(productID, imageID uint, tx *gorm.DB) error
+	ReorderProductImages(productID uint, imageIDs []uint) error
 }
 
 type ProductRepository struct {
@@ -61,19 +83,62 @@ func (r *ProductRepository) DeleteCategory(categoryID uint) error {
 	return r.db.Delete(categoryID).Error
 }
 
+// Category attributes
+func (r *ProductRepository) CreateCategoryAttribute(attribute *models.CategoryAttribute) error {
+	return r.db.Create(attribute).Error
+}
+
+func (r *ProductRepository) GetCategoryAttributes(categoryID uint) ([]models.CategoryAttribute, error) {
+	var attributes []models.CategoryAttribute
+	if err := r.db.Where("category_id = ?", categoryID).
+		Order("position, id").
+		Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func (r *ProductRepository) GetAllCategoryAttributes() ([]models.CategoryAttribute, error) {
+	var attributes []models.CategoryAttribute
+	if err := r.db.Order("category_id, position, id").Find(&attributes).Error; err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+func (r *ProductRepository) GetCategoryAttributeById(categoryID, attributeID uint) (*models.CategoryAttribute, error) {
+	var attribute models.CategoryAttribute
+	if err := r.db.Where("category_id = ? AND id = ?", categoryID, attributeID).First(&attribute).Error; err != nil {
+		return nil, err
+	}
+	return &attribute, nil
+}
+
+func (r *ProductRepository) UpdateCategoryAttribute(attribute *models.CategoryAttribute) error {
+	return r.db.Save(attribute).Error
+}
+
+func (r *ProductRepository) DeleteCategoryAttribute(categoryID, attributeID uint) error {
+	return r.db.Where("category_id = ? AND id = ?", categoryID, attributeID).Delete(&models.CategoryAttribute{}).Error
+}
+
 // Product
 
 func (r *ProductRepository) CreateProduct(product *models.Product) error {
 	return r.db.Create(product).Error
 }
 
-func (r *ProductRepository) GetProducts(offset, limit int) ([]models.Product, int64, error) {
+func (r *ProductRepository) CreateProductTx(product *models.Product, tx *gorm.DB) error {
+	return tx.Create(product).Error
+}
+
+func (r *ProductRepository) GetProducts(filter *dto.ProductFilter, offset, limit int) ([]models.Product, int64, error) {
 	var products []models.Product
 
-	total := r.GetProductsCount()
+	total := r.GetProductsCount(filter)
 
-	if err := r.db.Preload("Category").Preload("Images").
-		Where("is_active = ?", true).
+	if err := r.db.Preload("Category.Attributes", orderByPosition).Preload("Images", orderByPosition).
+		Scopes(filterProducts(filter)).
 		Offset(offset).Limit(limit).
 		Find(&products).Error; err != nil {
 		return nil, 0, err
@@ -81,39 +146,214 @@ func (r *ProductRepository) GetProducts(offset, limit int) ([]models.Product, in
 	return products, total, nil
 }
 
-func (r *ProductRepository) GetProductsCount() int64 {
+func (r *ProductRepository) GetProductsCount(filter *dto.ProductFilter) int64 {
 	var total int64
-	r.db.Model(&models.Product{}).Where("is_active = ?", true).Count(&total)
+	r.db.Model(&models.Product{}).Scopes(filterProducts(filter)).Count(&total)
 
 	return total
 }
 
 func (r *ProductRepository) GetProductById(productID uint) (*models.Product, error) {
 	var product models.Product
-	if err := r.db.Preload("Category").Preload("Images").First(&product, productID).Error; err != nil {
+	if err := r.db.Preload("Category.Attributes", orderByPosition).Preload("Images", orderByPosition).First(&product, productID).Error; err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
 
+func (r *ProductRepository) GetProductBySKU(sku string) (*models.Product, error) {
+	var product models.Product
+	if err := r.db.Where("sku = ?", sku).First(&product).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *ProductRepository) GetProductsInBatches(batchSize int, fn func(products []models.Product) error) error {
+	var products []models.Product
+	return r.db.Preload("Category").
+		FindInBatches(&products, batchSize, func(_ *gorm.DB, _ int) error {
+			return fn(products)
+		}).Error
+}
+
+// UpdateProduct leaves the rating summary alone; it is owned by the review repository.
 func (r *ProductRepository) UpdateProduct(product *models.Product) error {
-	return r.db.Save(product).Error
+	return r.db.Omit("AverageRating", "ReviewCount").Save(product).Error
+}
+
+func (r *ProductRepository) UpdateProductTx(product *models.Product, tx *gorm.DB) error {
+	return tx.Omit("AverageRating", "ReviewCount").Save(product).Error
+}
+
+// UpdateProductStockTx writes only the stock, so the review aggregates kept
+// on the same row are not overwritten.
+func (r *ProductRepository) UpdateProductStockTx(product *models.Product, tx *gorm.DB) error {
+	return tx.Model(product).Update("stock", product.Stock).Error
 }
 
 func (r *ProductRepository) DeleteProduct(productID uint) error {
 	return r.db.Delete(productID).Error
 }
 
-func (r *ProductRepository) UploadProductImage(image *models.ProductImage) error {
-	return r.db.Create(&image).Error
+// LockProductTx locks the product row until the transaction ends, so changes
+// to its images are made one at a time. It fails with gorm.ErrRecordNotFound
+// when there is no such product.
+func (r *ProductRepository) LockProductTx(productID uint, tx *gorm.DB) error {
+	var product models.Product
+	return tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&product, productID).Error
+}
+
+// GetProductForUpdateTx loads a product and locks its row until the
+// transaction ends, so stock read here cannot change under the caller.
+func (r *ProductRepository) GetProductForUpdateTx(productID uint, tx *gorm.DB) (*models.Product, error) {
+	var product models.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// CreateProductImageTx creates the image and takes a reference on each of its
+// stored objects.
+func (r *ProductRepository) CreateProductImageTx(image *models.ProductImage, tx *gorm.DB) error {
+	if err := tx.Create(image).Error; err != nil {
+		return err
+	}
+	return adjustObjectReferences(tx, image.Objects(), 1)
+}
+
+func (r *ProductRepository) GetProductImages(productID uint) ([]models.ProductImage, error) {
+	var images []models.ProductImage
+	if err := r.db.Scopes(orderByPosition).Where("product_id = ?", productID).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *ProductRepository) GetProductImage(productID, imageID uint) (*models.ProductImage, error) {
+	var image models.ProductImage
+	if err := r.db.Where("product_id = ?", productID).First(&image, imageID).Error; err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// FirstProductImageTx returns the first image in order, empty when the
+// product has none.
+func (r *ProductRepository) FirstProductImageTx(productID uint, tx *gorm.DB) (*models.ProductImage, error) {
+	var images []models.ProductImage
+	if err := tx.Scopes(orderByPosition).Where("product_id = ?", productID).Limit(1).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	if len(images) == 0 {
+		return &models.ProductImage{}, nil
+	}
+	return &images[0], nil
+}
+
+func (r *ProductRepository) UpdateProductImage(image *models.ProductImage) error {
+	return r.db.Omit("Product").Save(image).Error
 }
 
-func (r *ProductRepository) DeleteProductImage(imageID uint) error {
-	return r.db.Delete(&models.ProductImage{}, imageID).Error
+// DeleteProductImageTx deletes the image and releases its stored objects.
+// The files themselves are removed later by the storage-gc command. It fails
+// with gorm.ErrRecordNotFound when the image is already gone, so its objects
+// are released only once.
+func (r *ProductRepository) DeleteProductImageTx(image *models.ProductImage, tx *gorm.DB) error {
+	result := tx.Delete(&models.ProductImage{}, image.ID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return adjustObjectReferences(tx, image.Objects(), -1)
 }
 
-func (r *ProductRepository) CountProductImage(productID uint) int64 {
+func (r *ProductRepository) CountProductImagesTx(productID uint, tx *gorm.DB) (int64, error) {
 	var count int64
-	r.db.Model(&models.ProductImage{}).Where("product_id = ?", productID).Count(&count)
-	return count
+	err := tx.Model(&models.ProductImage{}).Where("product_id = ?", productID).Count(&count).Error
+	return count, err
+}
+
+func (r *ProductRepository) NextProductImagePositionTx(productID uint, tx *gorm.DB) (int, error) {
+	var position int
+	err := tx.Model(&models.ProductImage{}).
+		Where("product_id = ?", productID).
+		Select("COALESCE(MAX(position), -1) + 1").
+		Scan(&position).Error
+	return position, err
+}
+
+// SetPrimaryProductImageTx clears the flag on the other images first, so the
+// partial unique index on primary images is never violated.
+func (r *ProductRepository) SetPrimaryProductImageTx(productID, imageID uint, tx *gorm.DB) error {
+	if err := tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND id <> ? AND is_primary = ?", productID, imageID, true).
+		Update("is_primary", false).Error; err != nil {
+		return err
+	}
+
+	result := tx.Model(&models.ProductImage{}).
+		Where("product_id = ? AND id = ?", productID, imageID).
+		Update("is_primary", true)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReorderProductImages sets each image's position to its index in imageIDs.
+func (r *ProductRepository) ReorderProductImages(productID uint, imageIDs []uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for position, imageID := range imageIDs {
+			if err := tx.Model(&models.ProductImage{}).
+				Where("product_id = ? AND id = ?", productID, imageID).
+				Update("position", position).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Helper
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+func filterProducts(filter *dto.ProductFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		db = db.Where("is_active = ?", true)
+		if filter == nil {
+			return db
+		}
+
+		if filter.CategoryID != 0 {
+			db = db.Where("category_id = ?", filter.CategoryID)
+		}
+
+		// Containment compares JSON values, so 10 matches a stored 10.0 and
+		// true only matches a boolean.
+		if len(filter.AttributeMatches) > 0 {
+			db = db.Where("attributes @> ?::jsonb", models.JSONMap(filter.AttributeMatches))
+		}
+
+		// CASE guarantees the cast only runs on numeric values.
+		for key, value := range filter.AttributeMin {
+			db = db.Where("CASE WHEN jsonb_typeof(attributes -> ?) = 'number' THEN (attributes ->> ?)::numeric END >= ?", key, key, value)
+		}
+
+		for key, value := range filter.AttributeMax {
+			db = db.Where("CASE WHEN jsonb_typeof(attributes -> ?) = 'number' THEN (attributes ->> ?)::numeric END <= ?", key, key, value)
+		}
+
+		return db
+	}
 }
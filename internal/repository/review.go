@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type ReviewRepositoryInterface interface {
+	CreateReview(review *models.Review) error
+	GetReviewById(reviewID uint) (*models.Review, error)
+	GetUserReview(userID, productID uint) (*models.Review, error)
+	GetProductReviews(productID uint, status models.ReviewStatus, offset, limit int) ([]models.Review, int64, error)
+	GetReviewsByStatus(status models.ReviewStatus, offset, limit int) ([]models.Review, int64, error)
+	UpdateReview(review *models.Review) error
+	DeleteReview(review *models.Review) error
+	HasDeliveredPurchase(userID, productID uint) (bool, error)
+	RefreshProductRating(productID uint) error
+}
+
+type ReviewRepository struct {
+	db *gorm.DB
+}
+
+func NewReviewRepo(db *gorm.DB) ReviewRepositoryInterface {
+	return &ReviewRepository{
+		db: db,
+	}
+}
+
+func (r *ReviewRepository) CreateReview(review *models.Review) error {
+	return r.db.Create(review).Error
+}
+
+func (r *ReviewRepository) GetReviewById(reviewID uint) (*models.Review, error) {
+	var review models.Review
+	if err := r.db.Preload("User").First(&review, reviewID).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepository) GetUserReview(userID, productID uint) (*models.Review, error) {
+	var review models.Review
+	if err := r.db.Preload("User").
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		First(&review).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+func (r *ReviewRepository) GetProductReviews(productID uint, status models.ReviewStatus, offset, limit int) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var total int64
+
+	query := r.db.Model(&models.Review{}).Where("product_id = ? AND status = ?", productID, status).Session(&gorm.Session{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("User").
+		Order("created_at DESC").
+		Offset(offset).Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *ReviewRepository) GetReviewsByStatus(status models.ReviewStatus, offset, limit int) ([]models.Review, int64, error) {
+	var reviews []models.Review
+	var total int64
+
+	query := r.db.Model(&models.Review{}).Where("status = ?", status).Session(&gorm.Session{})
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Preload("User").
+		Order("created_at").
+		Offset(offset).Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+	return reviews, total, nil
+}
+
+func (r *ReviewRepository) UpdateReview(review *models.Review) error {
+	return r.db.Omit("User", "Product").Save(review).Error
+}
+
+func (r *ReviewRepository) DeleteReview(review *models.Review) error {
+	return r.db.Delete(review).Error
+}
+
+// HasDeliveredPurchase reports whether the user has received the product in
+// at least one delivered order.
+func (r *ReviewRepository) HasDeliveredPurchase(userID, productID uint) (bool, error) {
+	var count int64
+	if err := r.db.Model(&models.OrderItem{}).
+		Joins("JOIN orders ON order_items.order_id = orders.id").
+		Where("orders.user_id = ? AND orders.status = ? AND orders.deleted_at IS NULL", userID, models.OrderStatusDelivered).
+		Where("order_items.product_id = ?", productID).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// RefreshProductRating recomputes the product's rating summary from its
+// approved reviews.
+func (r *ReviewRepository) RefreshProductRating(productID uint) error {
+	return r.db.Exec(`
+		UPDATE products SET
+			average_rating = COALESCE(summary.average, 0),
+			review_count = summary.total
+		FROM (
+			SELECT AVG(rating) AS average, COUNT(*) AS total
+			FROM reviews
+			WHERE product_id = ? AND status = ? AND deleted_at IS NULL
+		) AS summary
+		WHERE products.id = ?`,
+		productID, models.ReviewStatusApproved, productID,
+	).Error
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"errors"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type RoleRepositoryInterface interface {
+	GetRoles() ([]models.Role, error)
+	GetRole(name string) (*models.Role, error)
+	GetPermissions() ([]models.Permission, error)
+	GetRolePermissions(name string) ([]string, error)
+	CreateRoleTx(data *models.Role, tx *gorm.DB) error
+	UpdateRoleTx(data *models.Role, tx *gorm.DB) error
+	ReplaceRolePermissionsTx(name string, permissions []string, tx *gorm.DB) error
+	DeleteRoleTx(name string, tx *gorm.DB) error
+	CountRoleUsers(name string) (int64, error)
+}
+
+type RoleRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleRepo(db *gorm.DB) RoleRepositoryInterface {
+	return &RoleRepository{
+		db: db,
+	}
+}
+
+func (r *RoleRepository) GetRoles() ([]models.Role, error) {
+	var roles []models.Role
+	if err := r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("permission")
+	}).Order("name").Find(&roles).Error; err != nil {
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (r *RoleRepository) GetRole(name string) (*models.Role, error) {
+	var role models.Role
+	if err := r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("permission")
+	}).Where("name = ?", name).First(&role).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &role, nil
+}
+
+func (r *RoleRepository) GetPermissions() ([]models.Permission, error) {
+	var permissions []models.Permission
+	if err := r.db.Order("name").Find(&permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+// GetRolePermissions returns the permissions of a role, none when the role
+// does not exist.
+func (r *RoleRepository) GetRolePermissions(name string) ([]string, error) {
+	var permissions []string
+	if err := r.db.Model(&models.RolePermission{}).
+		Where("role_name = ?", name).
+		Order("permission").
+		Pluck("permission", &permissions).Error; err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func (r *RoleRepository) CreateRoleTx(data *models.Role, tx *gorm.DB) error {
+	return tx.Omit("Permissions").Create(data).Error
+}
+
+func (r *RoleRepository) UpdateRoleTx(data *models.Role, tx *gorm.DB) error {
+	return tx.Model(data).Update("description", data.Description).Error
+}
+
+// ReplaceRolePermissionsTx sets the permissions of a role to exactly the
+// given ones.
+func (r *RoleRepository) ReplaceRolePermissionsTx(name string, permissions []string, tx *gorm.DB) error {
+	if err := tx.Where("role_name = ?", name).Delete(&models.RolePermission{}).Error; err != nil {
+		return err
+	}
+	if len(permissions) == 0 {
+		return nil
+	}
+
+	rows := make([]models.RolePermission, len(permissions))
+	for i, permission := range permissions {
+		rows[i] = models.RolePermission{RoleName: name, Permission: permission}
+	}
+	return tx.Create(&rows).Error
+}
+
+func (r *RoleRepository) DeleteRoleTx(name string, tx *gorm.DB) error {
+	return tx.Where("name = ?", name).Delete(&models.Role{}).Error
+}
+
+// CountRoleUsers counts the users with a role, deleted ones included since
+// they still reference it.
+func (r *RoleRepository) CountRoleUsers(name string) (int64, error) {
+	var count int64
+	err := r.db.Unscoped().Model(&models.User{}).Where("role = ?", name).Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type SessionRepositoryInterface interface {
+	GetSession(userID uint, sessionID string) (*models.Session, error)
+	GetActiveSessions(userID uint) ([]models.Session, error)
+	CreateSessionTx(data *models.Session, tx *gorm.DB) error
+	RefreshSessionTx(data *models.Session, tx *gorm.DB) error
+	TouchActiveSession(sessionID string) (bool, error)
+	RevokeSessionTx(sessionID string, tx *gorm.DB) error
+	RevokeUserSessionsTx(userID uint, exceptSessionID string, tx *gorm.DB) (int64, error)
+	DeleteExpiredSessions(before time.Time) (int64, error)
+}
+
+type SessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepo(db *gorm.DB) SessionRepositoryInterface {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+func (r *SessionRepository) GetSession(userID uint, sessionID string) (*models.Session, error) {
+	var session models.Session
+	if err := r.db.Where("id = ? AND user_id = ?", sessionID, userID).First(&session).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &session, nil
+}
+
+// GetActiveSessions returns the sessions of the user that are neither revoked
+// nor expired, most recently used first.
+func (r *SessionRepository) GetActiveSessions(userID uint) ([]models.Session, error) {
+	var sessions []models.Session
+	if err := r.db.
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&sessions).Error; err != nil {
+		return nil, err
+	}
+
+	return sessions, nil
+}
+
+func (r *SessionRepository) CreateSessionTx(data *models.Session, tx *gorm.DB) error {
+	return tx.Create(data).Error
+}
+
+// RefreshSessionTx records that the session exchanged its refresh token.
+func (r *SessionRepository) RefreshSessionTx(data *models.Session, tx *gorm.DB) error {
+	return tx.Model(&models.Session{}).
+		Where("id = ?", data.ID).
+		Updates(map[string]any{
+			"ip_address":   data.IPAddress,
+			"user_agent":   data.UserAgent,
+			"last_used_at": data.LastUsedAt,
+			"expires_at":   data.ExpiresAt,
+		}).Error
+}
+
+// TouchActiveSession updates the last use of the session and reports whether
+// it can still be used: not revoked, not expired and owned by an active user.
+func (r *SessionRepository) TouchActiveSession(sessionID string) (bool, error) {
+	result := r.db.Exec(`
+		UPDATE sessions
+		SET last_used_at = ?
+		FROM users
+		WHERE sessions.id = ?
+		  AND sessions.user_id = users.id
+		  AND sessions.revoked_at IS NULL
+		  AND sessions.expires_at > ?
+		  AND users.is_active = true
+		  AND users.deleted_at IS NULL`,
+		time.Now(), sessionID, time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	return result.RowsAffected > 0, nil
+}
+
+// RevokeSessionTx revokes the session and its refresh tokens.
+func (r *SessionRepository) RevokeSessionTx(sessionID string, tx *gorm.DB) error {
+	if err := tx.Model(&models.Session{}).
+		Where("id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", time.Now()).Error; err != nil {
+		return err
+	}
+
+	return tx.Where("family_id = ?", sessionID).Delete(&models.RefreshToken{}).Error
+}
+
+// RevokeUserSessionsTx revokes every session of the user but exceptSessionID,
+// along with their refresh tokens, and returns how many were revoked.
+func (r *SessionRepository) RevokeUserSessionsTx(userID uint, exceptSessionID string, tx *gorm.DB) (int64, error) {
+	result := tx.Model(&models.Session{}).
+		Where("user_id = ? AND id <> ? AND revoked_at IS NULL", userID, exceptSessionID).
+		Update("revoked_at", time.Now())
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	if err := tx.Where("user_id = ? AND family_id <> ?", userID, exceptSessionID).Delete(&models.RefreshToken{}).Error; err != nil {
+		return 0, err
+	}
+
+	return result.RowsAffected, nil
+}
+
+// DeleteExpiredSessions permanently deletes sessions that expired before the
+// given time, along with their refresh tokens.
+func (r *SessionRepository) DeleteExpiredSessions(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.Session{})
+	return result.RowsAffected, result.Error
+}
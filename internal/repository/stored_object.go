@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/storage"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type StoredObjectRepositoryInterface interface {
+	TouchStoredObject(provider, key string) (bool, error)
+	CreateStoredObject(object *models.StoredObject) error
+	RecountReferences() error
+	GetUnreferencedObjects(afterID uint, before time.Time, limit int) ([]models.StoredObject, error)
+	DeleteUnreferencedObject(objectID uint, before time.Time, fn func(object *models.StoredObject) error) (bool, error)
+}
+
+type StoredObjectRepository struct {
+	db *gorm.DB
+}
+
+func NewStoredObjectRepo(db *gorm.DB) StoredObjectRepositoryInterface {
+	return &StoredObjectRepository{
+		db: db,
+	}
+}
+
+// TouchStoredObject bumps updated_at of an existing object so a concurrent
+// garbage collection run leaves it alone. It reports whether the object exists.
+func (r *StoredObjectRepository) TouchStoredObject(provider, key string) (bool, error) {
+	result := r.db.Model(&models.StoredObject{}).
+		Where("storage_provider = ? AND key = ?", provider, key).
+		Update("updated_at", time.Now())
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *StoredObjectRepository) CreateStoredObject(object *models.StoredObject) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "storage_provider"}, {Name: "key"}},
+		DoUpdates: clause.Assignments(map[string]any{"updated_at": time.Now()}),
+	}).Create(object).Error
+}
+
+// RecountReferences recomputes every ref_count from product_images, repairing
+// counters that drifted.
+func (r *StoredObjectRepository) RecountReferences() error {
+	return r.db.Exec(`
+		UPDATE stored_objects so
+		SET ref_count = COALESCE(refs.count, 0)
+		FROM stored_objects target
+		LEFT JOIN (
+			SELECT storage_provider, object_key, COUNT(*) AS count
+			FROM (
+				SELECT DISTINCT id, storage_provider, unnest(ARRAY[key, thumbnail_key, medium_key, large_key]) AS object_key
+				FROM product_images
+				WHERE deleted_at IS NULL
+			) AS image_keys
+			GROUP BY storage_provider, object_key
+		) AS refs ON refs.storage_provider = target.storage_provider AND refs.object_key = target.key
+		WHERE so.id = target.id AND so.ref_count <> COALESCE(refs.count, 0)
+	`).Error
+}
+
+func (r *StoredObjectRepository) GetUnreferencedObjects(afterID uint, before time.Time, limit int) ([]models.StoredObject, error) {
+	var objects []models.StoredObject
+	if err := r.db.Where("id > ? AND ref_count <= 0 AND updated_at < ?", afterID, before).
+		Order("id").
+		Limit(limit).
+		Find(&objects).Error; err != nil {
+		return nil, err
+	}
+	return objects, nil
+}
+
+// DeleteUnreferencedObject locks the object, checks that it is still
+// unreferenced and calls fn to remove the file before deleting the row. The
+// row stays when fn fails, so the next run retries.
+func (r *StoredObjectRepository) DeleteUnreferencedObject(objectID uint, before time.Time, fn func(object *models.StoredObject) error) (bool, error) {
+	deleted := false
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var object models.StoredObject
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND ref_count <= 0 AND updated_at < ?", objectID, before).
+			First(&object).Error
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil
+			}
+			return err
+		}
+
+		if err := fn(&object); err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&object).Error; err != nil {
+			return err
+		}
+
+		deleted = true
+		return nil
+	})
+
+	return deleted, err
+}
+
+// Helper
+
+// adjustObjectReferences adds delta to the reference count of each distinct
+// object. It runs inside the caller's transaction.
+func adjustObjectReferences(tx *gorm.DB, objects []storage.Object, delta int) error {
+	seen := make(map[storage.Object]bool, len(objects))
+
+	for _, object := range objects {
+		if object.Key == "" || seen[object] {
+			continue
+		}
+		seen[object] = true
+
+		if err := tx.Model(&models.StoredObject{}).
+			Where("storage_provider = ? AND key = ?", object.Provider, object.Key).
+			Update("ref_count", gorm.Expr("ref_count + ?", delta)).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
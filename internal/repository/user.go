@@ -2,16 +2,28 @@ package repository
 
 import (
 	"errors"
+	"strings"
+	"time"
 
+	"github.com/anzhy11/go-e-commerce/internal/dto"
 	"github.com/anzhy11/go-e-commerce/internal/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepositoryInterface interface {
 	GetUserByEmail(email string) (*models.User, error)
 	GetUserById(userID uint) (*models.User, error)
+	GetUsers(filter *dto.UserFilter, offset, limit int) ([]models.User, int64, error)
 	CreateUser(data *models.User) error
+	CreateUserTx(data *models.User, tx *gorm.DB) error
 	UpdateUser(data *models.User) error
+	MarkEmailVerifiedTx(data *models.User, tx *gorm.DB) error
+	UpdatePasswordTx(data *models.User, tx *gorm.DB) error
+	SetMFAEnabledTx(data *models.User, tx *gorm.DB) error
+	SetRoleTx(data *models.User, tx *gorm.DB) error
+	SetActiveTx(data *models.User, tx *gorm.DB) error
+	LockActiveUsersWithRoleTx(role string, tx *gorm.DB) ([]uint, error)
 }
 
 type UserRpository struct {
@@ -42,10 +54,102 @@ func (r *UserRpository) GetUserById(userID uint) (*models.User, error) {
 	return &user, nil
 }
 
+// GetUsers returns the users matching the filter, newest first, with the
+// number of matches.
+func (r *UserRpository) GetUsers(filter *dto.UserFilter, offset, limit int) ([]models.User, int64, error) {
+	var total int64
+	if err := r.db.Model(&models.User{}).Scopes(filterUsers(filter)).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var users []models.User
+	if err := r.db.Scopes(filterUsers(filter)).
+		Order("created_at DESC, id DESC").
+		Offset(offset).Limit(limit).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
+
 func (r *UserRpository) CreateUser(data *models.User) error {
 	return r.db.Create(&data).Error
 }
 
+func (r *UserRpository) CreateUserTx(data *models.User, tx *gorm.DB) error {
+	return tx.Create(data).Error
+}
+
+// UpdateUser writes the profile columns only, so a profile edit racing an
+// admin change cannot restore the role, status or password it read.
 func (r *UserRpository) UpdateUser(data *models.User) error {
-	return r.db.Save(&data).Error
+	return r.db.Model(data).Select("first_name", "last_name", "phone", "locale").Updates(data).Error
 }
+
+func (r *UserRpository) MarkEmailVerifiedTx(data *models.User, tx *gorm.DB) error {
+	now := time.Now()
+	if err := tx.Model(data).Where("email_verified_at IS NULL").Update("email_verified_at", now).Error; err != nil {
+		return err
+	}
+
+	if data.EmailVerifiedAt == nil {
+		data.EmailVerifiedAt = &now
+	}
+	return nil
+}
+
+func (r *UserRpository) UpdatePasswordTx(data *models.User, tx *gorm.DB) error {
+	return tx.Model(data).Update("password", data.Password).Error
+}
+
+// SetMFAEnabledTx saves MFAEnabledAt, which is nil when two-factor
+// authentication is turned off.
+func (r *UserRpository) SetMFAEnabledTx(data *models.User, tx *gorm.DB) error {
+	return tx.Model(data).Update("mfa_enabled_at", data.MFAEnabledAt).Error
+}
+
+func (r *UserRpository) SetRoleTx(data *models.User, tx *gorm.DB) error {
+	return tx.Model(data).Update("role", data.Role).Error
+}
+
+func (r *UserRpository) SetActiveTx(data *models.User, tx *gorm.DB) error {
+	return tx.Model(data).Update("is_active", data.IsActive).Error
+}
+
+// LockActiveUsersWithRoleTx returns the IDs of the active users with a role
+// and locks them for the rest of the transaction, so two concurrent changes
+// cannot both see the other user still holding the role.
+func (r *UserRpository) LockActiveUsersWithRoleTx(role string, tx *gorm.DB) ([]uint, error) {
+	var ids []uint
+	if err := tx.Model(&models.User{}).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("role = ? AND is_active = ?", role, true).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func filterUsers(filter *dto.UserFilter) func(db *gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter == nil {
+			return db
+		}
+
+		if search := strings.TrimSpace(filter.Search); search != "" {
+			pattern := "%" + likeEscaper.Replace(search) + "%"
+			db = db.Where("email ILIKE ? OR first_name ILIKE ? OR last_name ILIKE ? OR first_name || ' ' || last_name ILIKE ?",
+				pattern, pattern, pattern, pattern)
+		}
+		if filter.Role != "" {
+			db = db.Where("role = ?", filter.Role)
+		}
+		if filter.IsActive != nil {
+			db = db.Where("is_active = ?", *filter.IsActive)
+		}
+		return db
+	}
+}
+
+// likeEscaper makes a search term match literally in a LIKE pattern.
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
@@ -0,0 +1,71 @@
+package repository
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type UserTokenRepositoryInterface interface {
+	GetUserToken(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error)
+	CreateUserTokenTx(data *models.UserToken, tx *gorm.DB) error
+	UseUserTokenTx(data *models.UserToken, tx *gorm.DB) (bool, error)
+	RevokeUserTokensTx(userID uint, purpose models.UserTokenPurpose, tx *gorm.DB) error
+	DeleteExpiredUserTokens(before time.Time) (int64, error)
+}
+
+type UserTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewUserTokenRepo(db *gorm.DB) UserTokenRepositoryInterface {
+	return &UserTokenRepository{
+		db: db,
+	}
+}
+
+func (r *UserTokenRepository) GetUserToken(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error) {
+	var token models.UserToken
+	if err := r.db.Where("purpose = ? AND token_hash = ?", purpose, tokenHash).First(&token).Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+func (r *UserTokenRepository) CreateUserTokenTx(data *models.UserToken, tx *gorm.DB) error {
+	return tx.Create(data).Error
+}
+
+// UseUserTokenTx marks the token used. It reports false when the token was
+// already used, so two concurrent requests cannot both redeem it.
+func (r *UserTokenRepository) UseUserTokenTx(data *models.UserToken, tx *gorm.DB) (bool, error) {
+	now := time.Now()
+	result := tx.Model(&models.UserToken{}).
+		Where("id = ? AND used_at IS NULL", data.ID).
+		Update("used_at", now)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected == 0 {
+		return false, nil
+	}
+
+	data.UsedAt = &now
+	return true, nil
+}
+
+// RevokeUserTokensTx marks the user's unused tokens for purpose as used.
+func (r *UserTokenRepository) RevokeUserTokensTx(userID uint, purpose models.UserTokenPurpose, tx *gorm.DB) error {
+	return tx.Model(&models.UserToken{}).
+		Where("user_id = ? AND purpose = ? AND used_at IS NULL", userID, purpose).
+		Update("used_at", time.Now()).Error
+}
+
+// DeleteExpiredUserTokens deletes tokens that expired before the given time.
+func (r *UserTokenRepository) DeleteExpiredUserTokens(before time.Time) (int64, error) {
+	result := r.db.Where("expires_at < ?", before).Delete(&models.UserToken{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,95 @@
+package repository
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"gorm.io/gorm"
+)
+
+type WishlistRepositoryInterface interface {
+	GetWishlistItems(userID uint) ([]models.WishlistItem, error)
+	GetWishlistItem(userID, productID uint) (*models.WishlistItem, error)
+	GetWishlistItemByToken(token string) (*models.WishlistItem, error)
+	CreateWishlistItem(item *models.WishlistItem) error
+	UpdateWishlistItem(item *models.WishlistItem) error
+	DeleteWishlistItem(userID, productID uint) error
+	GetBackInStockSubscribers(productID uint) ([]models.WishlistItem, error)
+	GetPriceDropSubscribers(productID uint) ([]models.WishlistItem, error)
+}
+
+type WishlistRepository struct {
+	db *gorm.DB
+}
+
+func NewWishlistRepo(db *gorm.DB) WishlistRepositoryInterface {
+	return &WishlistRepository{
+		db: db,
+	}
+}
+
+func (r *WishlistRepository) GetWishlistItems(userID uint) ([]models.WishlistItem, error) {
+	var items []models.WishlistItem
+	if err := r.db.Preload("Product.Category").Preload("Product.Images", orderByPosition).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *WishlistRepository) GetWishlistItem(userID, productID uint) (*models.WishlistItem, error) {
+	var item models.WishlistItem
+	if err := r.db.Preload("Product.Category").Preload("Product.Images", orderByPosition).
+		Where("user_id = ? AND product_id = ?", userID, productID).
+		First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *WishlistRepository) GetWishlistItemByToken(token string) (*models.WishlistItem, error) {
+	var item models.WishlistItem
+	if err := r.db.Where("unsubscribe_token = ?", token).First(&item).Error; err != nil {
+		return nil, err
+	}
+	return &item, nil
+}
+
+func (r *WishlistRepository) CreateWishlistItem(item *models.WishlistItem) error {
+	return r.db.Create(item).Error
+}
+
+func (r *WishlistRepository) UpdateWishlistItem(item *models.WishlistItem) error {
+	return r.db.Omit("User", "Product").Save(item).Error
+}
+
+func (r *WishlistRepository) DeleteWishlistItem(userID, productID uint) error {
+	result := r.db.Where("user_id = ? AND product_id = ?", userID, productID).Delete(&models.WishlistItem{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *WishlistRepository) GetBackInStockSubscribers(productID uint) ([]models.WishlistItem, error) {
+	return r.getSubscribers(productID, "wishlist_items.notify_back_in_stock = ?")
+}
+
+func (r *WishlistRepository) GetPriceDropSubscribers(productID uint) ([]models.WishlistItem, error) {
+	return r.getSubscribers(productID, "wishlist_items.notify_price_drop = ?")
+}
+
+func (r *WishlistRepository) getSubscribers(productID uint, alertCondition string) ([]models.WishlistItem, error) {
+	var items []models.WishlistItem
+	if err := r.db.Joins("User").
+		Where("wishlist_items.product_id = ?", productID).
+		Where(alertCondition, true).
+		Where(`"User".is_active = ?`, true).
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -1,10 +1,16 @@
 package authHandler
 
 import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+
 	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/dto"
-	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/identity"
 	authService "github.com/anzhy11/go-e-commerce/internal/services/auth"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
@@ -16,15 +22,34 @@ type AuthHandlerInterface interface {
 	Login(c *gin.Context)
 	RefreshToken(c *gin.Context)
 	Logout(c *gin.Context)
+	VerifyEmailLink(c *gin.Context)
+	VerifyEmail(c *gin.Context)
+	ResendVerificationEmail(c *gin.Context)
+	ForgotPassword(c *gin.Context)
+	ResetPassword(c *gin.Context)
+	ChangePassword(c *gin.Context)
+	UnlockAccount(c *gin.Context)
+	ForcePasswordReset(c *gin.Context)
+	GetMFAStatus(c *gin.Context)
+	EnrollMFA(c *gin.Context)
+	ConfirmMFA(c *gin.Context)
+	DisableMFA(c *gin.Context)
+	RegenerateRecoveryCodes(c *gin.Context)
+	VerifyMFA(c *gin.Context)
+	ListOIDCProviders(c *gin.Context)
+	StartOIDCLogin(c *gin.Context)
+	OIDCCallback(c *gin.Context)
 }
 
 type authHandler struct {
-	as authService.AuthServiceInterface
+	as            authService.AuthServiceInterface
+	secureCookies bool
 }
 
-func New(db *gorm.DB, cfg *config.Config, log *zerolog.Logger, eventPub events.PublisherInterface) AuthHandlerInterface {
+func New(db *gorm.DB, cfg *config.Config, log *zerolog.Logger, tokens *utils.TokenManager, sessions sessionService.SessionServiceInterface) AuthHandlerInterface {
 	return &authHandler{
-		as: authService.New(db, cfg, log, eventPub),
+		as:            authService.New(db, cfg, log, tokens, sessions),
+		secureCookies: strings.HasPrefix(cfg.Server.AppURL, "https://"),
 	}
 }
 
@@ -45,7 +70,7 @@ func (h *authHandler) Register(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.as.Register(&req)
+	resp, err := h.as.Register(&req, clientInfo(c))
 	if err != nil {
 		utils.InternalServerError(c, "failed to register user", err)
 		return
@@ -55,13 +80,16 @@ func (h *authHandler) Register(c *gin.Context) {
 }
 
 // @Summary Login user
-// @Description Login user with email and password
+// @Description Login user with email and password. Repeated failures delay and then lock the account for a while; the Retry-After header tells when to try again. Accounts with two-factor authentication get an MFA challenge to complete at /auth/mfa/verify instead of tokens
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body dto.LoginRequest true "User login data"
-// @Success 200 {object} utils.Response{data=dto.AuthResponse} "User logged in successfully"
-// @Failure 400 {object} utils.Response "Invalid request data or user not found"
+// @Success 200 {object} utils.Response{data=dto.LoginResponse} "User logged in successfully, or MFA challenge issued"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 401 {object} utils.Response "Invalid email or password"
+// @Failure 403 {object} utils.Response "Account deactivated"
+// @Failure 429 {object} utils.Response "Too many failed login attempts"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /auth/login [post]
 func (h *authHandler) Login(c *gin.Context) {
@@ -71,9 +99,9 @@ func (h *authHandler) Login(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.as.Login(&req)
+	resp, err := h.as.Login(&req, clientInfo(c))
 	if err != nil {
-		utils.InternalServerError(c, "failed to login user", err)
+		handleAuthError(c, "failed to login user", err)
 		return
 	}
 
@@ -88,6 +116,8 @@ func (h *authHandler) Login(c *gin.Context) {
 // @Param request body dto.RefreshTokenRequest true "Refresh token data"
 // @Success 200 {object} utils.Response{data=dto.AuthResponse} "Token refreshed successfully"
 // @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 401 {object} utils.Response "Invalid or expired refresh token, or reuse of a rotated token"
+// @Failure 403 {object} utils.Response "Account deactivated"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /auth/refresh [post]
 func (h *authHandler) RefreshToken(c *gin.Context) {
@@ -97,9 +127,9 @@ func (h *authHandler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	resp, err := h.as.RefreshToken(&req)
+	resp, err := h.as.RefreshToken(&req, clientInfo(c))
 	if err != nil {
-		utils.InternalServerError(c, "failed to refresh token", err)
+		handleAuthError(c, "failed to refresh token", err)
 		return
 	}
 
@@ -131,3 +161,259 @@ func (h *authHandler) Logout(c *gin.Context) {
 
 	utils.SuccessResponse(c, "user logged out successfully", nil)
 }
+
+// @Summary Verify email from link
+// @Description Verify the user's email address with the token from the verification email
+// @Tags Authentication
+// @Produce json
+// @Param token query string true "Verification token"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "Email verified successfully"
+// @Failure 400 {object} utils.Response "Invalid or expired token"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/verify-email [get]
+func (h *authHandler) VerifyEmailLink(c *gin.Context) {
+	token := c.Query("token")
+	if token == "" {
+		utils.BadRequest(c, "invalid request", errors.New("token is required"))
+		return
+	}
+
+	h.verifyEmail(c, token)
+}
+
+// @Summary Verify email
+// @Description Verify the user's email address with the token from the verification email
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.VerifyEmailRequest true "Verification token"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "Email verified successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or invalid or expired token"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/verify-email [post]
+func (h *authHandler) VerifyEmail(c *gin.Context) {
+	var req dto.VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	h.verifyEmail(c, req.Token)
+}
+
+// @Summary Resend verification email
+// @Description Send a new email verification link to the authenticated user. Earlier links stop working
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 202 {object} utils.Response "Verification email sent"
+// @Failure 400 {object} utils.Response "Email already verified"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/verify-email/resend [post]
+func (h *authHandler) ResendVerificationEmail(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if err := h.as.ResendVerificationEmail(userID); err != nil {
+		handleAuthError(c, "failed to resend verification email", err)
+		return
+	}
+
+	utils.AcceptedResponse(c, "verification email sent", nil)
+}
+
+// @Summary Forgot password
+// @Description Email a password reset link. The response is the same whether or not the email belongs to an account
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ForgotPasswordRequest true "Account email"
+// @Success 202 {object} utils.Response "Password reset email sent if the account exists"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/forgot-password [post]
+func (h *authHandler) ForgotPassword(c *gin.Context) {
+	var req dto.ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.as.ForgotPassword(&req); err != nil {
+		handleAuthError(c, "failed to request password reset", err)
+		return
+	}
+
+	utils.AcceptedResponse(c, "if the account exists, a password reset email has been sent", nil)
+}
+
+// @Summary Reset password
+// @Description Set a new password with the token from a password reset email. Signs the user out of every session
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.Response "Password reset successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or invalid or expired token"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/reset-password [post]
+func (h *authHandler) ResetPassword(c *gin.Context) {
+	var req dto.ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.as.ResetPassword(&req); err != nil {
+		handleAuthError(c, "failed to reset password", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "password reset successfully", nil)
+}
+
+// @Summary Change password
+// @Description Change the authenticated user's password. Signs the user out of every session
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response "Password changed successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or wrong current password"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/change-password [post]
+func (h *authHandler) ChangePassword(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req dto.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.as.ChangePassword(userID, &req); err != nil {
+		handleAuthError(c, "failed to change password", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "password changed successfully", nil)
+}
+
+func (h *authHandler) verifyEmail(c *gin.Context, token string) {
+	resp, err := h.as.VerifyEmail(token)
+	if err != nil {
+		handleAuthError(c, "failed to verify email", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "email verified successfully", resp)
+}
+
+func clientInfo(c *gin.Context) *dto.ClientInfo {
+	return &dto.ClientInfo{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+	}
+}
+
+// @Summary Unlock account
+// @Description Lift the lockout caused by failed logins and clear the failed attempts of a user
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Success 200 {object} utils.Response "Account unlocked"
+// @Failure 400 {object} utils.Response "Invalid user ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/unlock [post]
+func (h *authHandler) UnlockAccount(c *gin.Context) {
+	actorID := c.GetUint("user_id")
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user ID", err)
+		return
+	}
+
+	if err := h.as.UnlockAccount(actorID, uint(userID)); err != nil {
+		handleAuthError(c, "failed to unlock account", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "account unlocked", nil)
+}
+
+// @Summary Force password reset
+// @Description Invalidate a user's password, sign them out of every session and email them a reset link
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Success 200 {object} utils.Response "Password reset forced"
+// @Failure 400 {object} utils.Response "Invalid user ID"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/password-reset [post]
+func (h *authHandler) ForcePasswordReset(c *gin.Context) {
+	actorID := c.GetUint("user_id")
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user ID", err)
+		return
+	}
+
+	if err := h.as.ForcePasswordReset(actorID, uint(userID)); err != nil {
+		handleAuthError(c, "failed to force password reset", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "password reset forced", nil)
+}
+
+func handleAuthError(c *gin.Context, message string, err error) {
+	var throttled *authService.LoginThrottledError
+	switch {
+	case errors.As(err, &throttled):
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		utils.TooManyRequests(c, message, err)
+	case errors.Is(err, authService.ErrUserNotFound),
+		errors.Is(err, identity.ErrUnknownProvider):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, authService.ErrMFAUnavailable),
+		errors.Is(err, identity.ErrProviderUnavailable):
+		utils.ServiceUnavailable(c, message, err)
+	case errors.Is(err, authService.ErrMFARequired),
+		errors.Is(err, authService.ErrAccountDeactivated),
+		errors.Is(err, authService.ErrIdentityEmailUnverified),
+		errors.Is(err, authService.ErrAccountEmailUnverified):
+		utils.Forbidden(c, message, err)
+	case errors.Is(err, authService.ErrInvalidCredentials),
+		errors.Is(err, authService.ErrInvalidRefreshToken),
+		errors.Is(err, authService.ErrRefreshTokenReused),
+		errors.Is(err, authService.ErrOIDCLoginDenied),
+		errors.Is(err, identity.ErrExchangeFailed),
+		errors.Is(err, identity.ErrInvalidIDToken):
+		utils.Unauthorized(c, message, err)
+	case errors.Is(err, authService.ErrInvalidOIDCState),
+		errors.Is(err, authService.ErrInvalidToken),
+		errors.Is(err, authService.ErrEmailAlreadyVerified),
+		errors.Is(err, authService.ErrInvalidPassword),
+		errors.Is(err, authService.ErrMFAAlreadyEnabled),
+		errors.Is(err, authService.ErrMFANotEnabled),
+		errors.Is(err, authService.ErrMFANotEnrolled),
+		errors.Is(err, authService.ErrInvalidMFACode):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
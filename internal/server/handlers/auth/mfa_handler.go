@@ -0,0 +1,160 @@
+package authHandler
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Get two-factor authentication status
+// @Description Tell whether two-factor authentication is enabled or required, and how many recovery codes are left
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.MFAStatusResponse} "Two-factor authentication status"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/mfa [get]
+func (h *authHandler) GetMFAStatus(c *gin.Context) {
+	resp, err := h.as.GetMFAStatus(c.GetUint("user_id"))
+	if err != nil {
+		handleAuthError(c, "failed to get two-factor authentication status", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "two-factor authentication status fetched successfully", resp)
+}
+
+// @Summary Enroll an authenticator
+// @Description Create the secret of an authenticator app. Two-factor authentication is only enabled once a code is confirmed; enrolling again replaces an unconfirmed secret
+// @Tags Authentication
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.MFAEnrollmentResponse} "Authenticator secret created"
+// @Failure 400 {object} utils.Response "Two-factor authentication already enabled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Failure 503 {object} utils.Response "Two-factor authentication not configured"
+// @Router /auth/mfa/enroll [post]
+func (h *authHandler) EnrollMFA(c *gin.Context) {
+	resp, err := h.as.EnrollMFA(c.GetUint("user_id"))
+	if err != nil {
+		handleAuthError(c, "failed to enroll authenticator", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "authenticator enrolled, confirm it with a code", resp)
+}
+
+// @Summary Confirm an authenticator
+// @Description Enable two-factor authentication with a first code from the enrolled authenticator. The recovery codes are only shown in this response
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "Authenticator code"
+// @Success 200 {object} utils.Response{data=dto.MFARecoveryCodesResponse} "Two-factor authentication enabled"
+// @Failure 400 {object} utils.Response "Invalid code, nothing enrolled or already enabled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/mfa/confirm [post]
+func (h *authHandler) ConfirmMFA(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	resp, err := h.as.ConfirmMFA(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		handleAuthError(c, "failed to enable two-factor authentication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "two-factor authentication enabled", resp)
+}
+
+// @Summary Disable two-factor authentication
+// @Description Disable two-factor authentication with the password and an authenticator or recovery code. Staff cannot when it is required for them
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.DisableMFARequest true "Password and code"
+// @Success 200 {object} utils.Response "Two-factor authentication disabled"
+// @Failure 400 {object} utils.Response "Invalid password or code, or not enabled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 403 {object} utils.Response "Two-factor authentication is required for this account"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/mfa/disable [post]
+func (h *authHandler) DisableMFA(c *gin.Context) {
+	var req dto.DisableMFARequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if err := h.as.DisableMFA(c.GetUint("user_id"), &req); err != nil {
+		handleAuthError(c, "failed to disable two-factor authentication", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "two-factor authentication disabled", nil)
+}
+
+// @Summary Regenerate recovery codes
+// @Description Replace every recovery code with new ones, confirmed with an authenticator code
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.MFACodeRequest true "Authenticator code"
+// @Success 200 {object} utils.Response{data=dto.MFARecoveryCodesResponse} "Recovery codes replaced"
+// @Failure 400 {object} utils.Response "Invalid code or not enabled"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/mfa/recovery-codes [post]
+func (h *authHandler) RegenerateRecoveryCodes(c *gin.Context) {
+	var req dto.MFACodeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	resp, err := h.as.RegenerateRecoveryCodes(c.GetUint("user_id"), req.Code)
+	if err != nil {
+		handleAuthError(c, "failed to regenerate recovery codes", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "recovery codes regenerated", resp)
+}
+
+// @Summary Complete a login with two-factor authentication
+// @Description Exchange the MFA challenge of a login and an authenticator or recovery code for tokens. Wrong codes count as failed logins
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param request body dto.MFAVerifyRequest true "MFA challenge and code"
+// @Success 200 {object} utils.Response{data=dto.AuthResponse} "User logged in successfully"
+// @Failure 400 {object} utils.Response "Invalid or expired challenge, or invalid code"
+// @Failure 403 {object} utils.Response "Account deactivated"
+// @Failure 429 {object} utils.Response "Too many failed login attempts"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /auth/mfa/verify [post]
+func (h *authHandler) VerifyMFA(c *gin.Context) {
+	var req dto.MFAVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	resp, err := h.as.VerifyMFA(&req, clientInfo(c))
+	if err != nil {
+		handleAuthError(c, "failed to verify code", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "user logged in successfully", resp)
+}
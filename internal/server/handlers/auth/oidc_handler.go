@@ -0,0 +1,94 @@
+package authHandler
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	authService "github.com/anzhy11/go-e-commerce/internal/services/auth"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// oidcStateCookie binds a login with an identity provider to the browser
+// that started it, so a callback URL sent to someone else signs no one in.
+const oidcStateCookie = "oidc_state"
+
+// @Summary List identity providers
+// @Description List the external identity providers users can sign in with
+// @Tags Authentication
+// @Produce json
+// @Success 200 {object} utils.Response{data=dto.OIDCProvidersResponse} "Identity providers"
+// @Router /auth/oidc/providers [get]
+func (h *authHandler) ListOIDCProviders(c *gin.Context) {
+	utils.SuccessResponse(c, "identity providers fetched successfully", h.as.ListOIDCProviders())
+}
+
+// @Summary Sign in with an identity provider
+// @Description Redirect the browser to the identity provider to sign in. It comes back to /auth/oidc/{provider}/callback
+// @Tags Authentication
+// @Param provider path string true "Identity provider"
+// @Success 302 "Redirect to the identity provider"
+// @Failure 404 {object} utils.Response "Unknown identity provider"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Failure 503 {object} utils.Response "Identity provider unavailable"
+// @Router /auth/oidc/{provider}/login [get]
+func (h *authHandler) StartOIDCLogin(c *gin.Context) {
+	login, err := h.as.StartOIDCLogin(c.Request.Context(), c.Param("provider"))
+	if err != nil {
+		handleAuthError(c, "failed to start sign-in", err)
+		return
+	}
+
+	h.setOIDCStateCookie(c, login.State, int(time.Until(login.ExpiresAt).Seconds()))
+	c.Redirect(http.StatusFound, login.AuthorizationURL)
+}
+
+// @Summary Complete a sign-in with an identity provider
+// @Description Where the identity provider sends the browser back. Signs in the user linked to the identity; on first sign-in the identity is linked to the account with the same verified email, or a new account is created. Accounts with two-factor authentication get an MFA challenge instead of tokens
+// @Tags Authentication
+// @Produce json
+// @Param provider path string true "Identity provider"
+// @Param state query string true "State of the login"
+// @Param code query string false "Authorization code"
+// @Param error query string false "Error from the identity provider"
+// @Success 200 {object} utils.Response{data=dto.LoginResponse} "User logged in successfully, or MFA challenge issued"
+// @Failure 400 {object} utils.Response "Invalid or expired login state"
+// @Failure 401 {object} utils.Response "Sign-in refused or not proven by the identity provider"
+// @Failure 403 {object} utils.Response "Email not verified by the identity provider or on the existing account, or account deactivated"
+// @Failure 404 {object} utils.Response "Unknown identity provider"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Failure 503 {object} utils.Response "Identity provider unavailable"
+// @Router /auth/oidc/{provider}/callback [get]
+func (h *authHandler) OIDCCallback(c *gin.Context) {
+	var req dto.OIDCCallbackRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	state, err := c.Cookie(oidcStateCookie)
+	h.setOIDCStateCookie(c, "", -1)
+	if err != nil || subtle.ConstantTimeCompare([]byte(state), []byte(req.State)) != 1 {
+		utils.BadRequest(c, "failed to complete sign-in", fmt.Errorf("%w: state does not match this browser", authService.ErrInvalidOIDCState))
+		return
+	}
+
+	resp, err := h.as.CompleteOIDCLogin(c.Request.Context(), c.Param("provider"), &req, clientInfo(c))
+	if err != nil {
+		handleAuthError(c, "failed to complete sign-in", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "user logged in successfully", resp)
+}
+
+// setOIDCStateCookie scopes the cookie to the provider's routes. It has to
+// be Lax rather than Strict to come along on the redirect back from the
+// provider.
+func (h *authHandler) setOIDCStateCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(oidcStateCookie, value, maxAge, "/api/v1/auth/oidc/"+c.Param("provider"), "", h.secureCookies, true)
+}
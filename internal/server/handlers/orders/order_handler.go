@@ -1,12 +1,15 @@
 package orderHandler
 
 import (
+	"errors"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 
-	_ "github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
 	orderService "github.com/anzhy11/go-e-commerce/internal/services/orders"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"gorm.io/gorm"
 )
@@ -15,15 +18,17 @@ type OrderHandlerInterface interface {
 	CreateOrder(c *gin.Context)
 	GetOrders(c *gin.Context)
 	GetOrder(c *gin.Context)
+	UpdateOrderStatus(c *gin.Context)
+	CancelOrder(c *gin.Context)
 }
 
 type orderHandler struct {
 	orderService orderService.OrderServiceInterface
 }
 
-func New(db *gorm.DB) OrderHandlerInterface {
+func New(db *gorm.DB, cfg *config.Config, products productService.ProductServiceInterface) OrderHandlerInterface {
 	return &orderHandler{
-		orderService: orderService.New(db),
+		orderService: orderService.New(db, cfg, products),
 	}
 }
 
@@ -33,6 +38,8 @@ func New(db *gorm.DB) OrderHandlerInterface {
 // @Produce json
 // @Security BearerAuth
 // @Success 201 {object} utils.Response{data=dto.OrderResponse} "Order created successfully"
+// @Failure 400 {object} utils.Response "Cart is empty or stock is insufficient"
+// @Failure 403 {object} utils.Response "Email not verified and checkout requires it"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /orders [post]
 func (h *orderHandler) CreateOrder(c *gin.Context) {
@@ -40,7 +47,7 @@ func (h *orderHandler) CreateOrder(c *gin.Context) {
 
 	orderResponse, err := h.orderService.CreateOrder(userID)
 	if err != nil {
-		utils.InternalServerError(c, "failed to create order", err)
+		handleOrderError(c, "failed to create order", err)
 		return
 	}
 
@@ -100,3 +107,85 @@ func (h *orderHandler) GetOrder(c *gin.Context) {
 
 	utils.SuccessResponse(c, "Order fetched successfully", orderResponse)
 }
+
+// @Summary Update order status
+// @Description Move an order to a new status. Orders go from pending to confirmed, shipped and delivered, and can be cancelled until they ship. Cancelling returns the items to stock.
+// @Tags Orders
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Order ID"
+// @Param order body dto.UpdateOrderStatusRequest true "New status"
+// @Success 200 {object} utils.Response{data=dto.OrderResponse} "Order status updated successfully"
+// @Failure 400 {object} utils.Response "Invalid status transition"
+// @Failure 403 {object} utils.Response "Admin access required"
+// @Failure 404 {object} utils.Response "Order not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /orders/{id}/status [put]
+func (h *orderHandler) UpdateOrderStatus(c *gin.Context) {
+	actorID := c.GetUint("user_id")
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid order ID", err)
+		return
+	}
+
+	var req dto.UpdateOrderStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request body", err)
+		return
+	}
+
+	orderResponse, err := h.orderService.UpdateOrderStatus(actorID, uint(orderID), &req)
+	if err != nil {
+		handleOrderError(c, "failed to update order status", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Order status updated successfully", orderResponse)
+}
+
+// @Summary Cancel order
+// @Description Cancel one of your orders before it ships. The items are returned to stock.
+// @Tags Orders
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Order ID"
+// @Success 200 {object} utils.Response{data=dto.OrderResponse} "Order cancelled successfully"
+// @Failure 400 {object} utils.Response "Order can no longer be cancelled"
+// @Failure 404 {object} utils.Response "Order not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /orders/{id}/cancel [post]
+func (h *orderHandler) CancelOrder(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	orderID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid order ID", err)
+		return
+	}
+
+	orderResponse, err := h.orderService.CancelOrder(userID, uint(orderID))
+	if err != nil {
+		handleOrderError(c, "failed to cancel order", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Order cancelled successfully", orderResponse)
+}
+
+func handleOrderError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, orderService.ErrOrderNotFound):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, orderService.ErrEmailNotVerified):
+		utils.Forbidden(c, message, err)
+	case errors.Is(err, orderService.ErrCartEmpty),
+		errors.Is(err, orderService.ErrInsufficientStock),
+		errors.Is(err, orderService.ErrInvalidStatusTransition):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
@@ -1,15 +1,22 @@
 package productHandler
 
 import (
+	"errors"
+	"fmt"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/dto"
 	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	importService "github.com/anzhy11/go-e-commerce/internal/services/imports"
 	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
 	uploadService "github.com/anzhy11/go-e-commerce/internal/services/upload"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
 
@@ -18,26 +25,42 @@ type ProductHandlerInterface interface {
 	GetCategories(c *gin.Context)
 	UpdateCategory(c *gin.Context)
 	DeleteCategory(c *gin.Context)
+	CreateCategoryAttribute(c *gin.Context)
+	GetCategoryAttributes(c *gin.Context)
+	UpdateCategoryAttribute(c *gin.Context)
+	DeleteCategoryAttribute(c *gin.Context)
 	CreateProduct(c *gin.Context)
 	GetProducts(c *gin.Context)
 	GetProductById(c *gin.Context)
 	UpdateProduct(c *gin.Context)
 	DeleteProduct(c *gin.Context)
 	UploadProductImage(c *gin.Context)
+	RequestProductImageUpload(c *gin.Context)
+	ConfirmProductImageUpload(c *gin.Context)
+	GetProductImages(c *gin.Context)
+	UpdateProductImage(c *gin.Context)
+	SetPrimaryProductImage(c *gin.Context)
+	ReorderProductImages(c *gin.Context)
+	DeleteProductImage(c *gin.Context)
+	ImportProducts(c *gin.Context)
+	GetImportJob(c *gin.Context)
+	ExportProducts(c *gin.Context)
 }
 
 type productHandler struct {
 	pd  productService.ProductServiceInterface
+	is  importService.ImportServiceInterface
 	db  *gorm.DB
 	us  *uploadService.UploadService
 	cfg *config.Config
 }
 
-func New(db *gorm.DB, cfg *config.Config, up interfaces.Upload) ProductHandlerInterface {
-	us := uploadService.NewUploadService(up)
+func New(db *gorm.DB, cfg *config.Config, log *zerolog.Logger, up interfaces.Upload, imports *importService.Worker, products productService.ProductServiceInterface) ProductHandlerInterface {
+	us := uploadService.NewUploadService(db, up, &cfg.Upload)
 
 	return &productHandler{
-		pd:  productService.New(db),
+		pd:  products,
+		is:  importService.New(db, log, imports, products),
 		db:  db,
 		cfg: cfg,
 		us:  us,
@@ -148,6 +171,139 @@ func (h *productHandler) DeleteCategory(c *gin.Context) {
 	utils.SuccessResponse(c, "Category deleted successfully", nil)
 }
 
+// Category attributes
+
+// @Summary Create category attribute
+// @Description Add an attribute to a category's specification schema
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Category ID"
+// @Param request body dto.CreateCategoryAttributeRequest true "Attribute data"
+// @Success 201 {object} utils.Response{data=dto.CategoryAttributeResponse} "Category attribute created successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/categories/{id}/attributes [post]
+func (h *productHandler) CreateCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid category id", err)
+		return
+	}
+
+	var req dto.CreateCategoryAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	attribute, attrErr := h.pd.CreateCategoryAttribute(uint(categoryID), &req)
+	if attrErr != nil {
+		handleAttributeError(c, "failed to create category attribute", attrErr)
+		return
+	}
+
+	utils.CreatedResponse(c, "Category attribute created successfully", attribute)
+}
+
+// @Summary Get category attributes
+// @Description Get a category's specification schema
+// @Tags Products
+// @Produce json
+// @Param id path uint true "Category ID"
+// @Success 200 {object} utils.Response{data=[]dto.CategoryAttributeResponse} "Category attributes fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/categories/{id}/attributes [get]
+func (h *productHandler) GetCategoryAttributes(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid category id", err)
+		return
+	}
+
+	attributes, err := h.pd.GetCategoryAttributes(uint(categoryID))
+	if err != nil {
+		utils.InternalServerError(c, "failed to get category attributes", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Category attributes fetched successfully", attributes)
+}
+
+// @Summary Update category attribute
+// @Description Update a category attribute. The key and type cannot be changed.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Category ID"
+// @Param attributeId path uint true "Attribute ID"
+// @Param request body dto.UpdateCategoryAttributeRequest true "Attribute data"
+// @Success 200 {object} utils.Response{data=dto.CategoryAttributeResponse} "Category attribute updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/categories/{id}/attributes/{attributeId} [put]
+func (h *productHandler) UpdateCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid category id", err)
+		return
+	}
+
+	attributeID, err := strconv.ParseUint(c.Param("attributeId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid attribute id", err)
+		return
+	}
+
+	var req dto.UpdateCategoryAttributeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	attribute, attrErr := h.pd.UpdateCategoryAttribute(uint(categoryID), uint(attributeID), &req)
+	if attrErr != nil {
+		handleAttributeError(c, "failed to update category attribute", attrErr)
+		return
+	}
+
+	utils.SuccessResponse(c, "Category attribute updated successfully", attribute)
+}
+
+// @Summary Delete category attribute
+// @Description Delete a category attribute
+// @Tags Products
+// @Security BearerAuth
+// @Param id path uint true "Category ID"
+// @Param attributeId path uint true "Attribute ID"
+// @Success 200 {object} utils.Response "Category attribute deleted successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/categories/{id}/attributes/{attributeId} [delete]
+func (h *productHandler) DeleteCategoryAttribute(c *gin.Context) {
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid category id", err)
+		return
+	}
+
+	attributeID, err := strconv.ParseUint(c.Param("attributeId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid attribute id", err)
+		return
+	}
+
+	if err := h.pd.DeleteCategoryAttribute(uint(categoryID), uint(attributeID)); err != nil {
+		utils.InternalServerError(c, "failed to delete category attribute", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Category attribute deleted successfully", nil)
+}
+
 // Product
 
 // @Summary Create product
@@ -170,7 +326,7 @@ func (h *productHandler) CreateProduct(c *gin.Context) {
 
 	product, err := h.pd.CreateProduct(&req)
 	if err != nil {
-		utils.InternalServerError(c, "failed to create product", err)
+		handleAttributeError(c, "failed to create product", err)
 		return
 	}
 
@@ -178,19 +334,29 @@ func (h *productHandler) CreateProduct(c *gin.Context) {
 }
 
 // @Summary Get products
-// @Description Get products
+// @Description Get products. Filter on attributes with attr[key]=value for exact matches and attr_min[key] / attr_max[key] for numeric ranges. Attribute filters need category_id and only apply to filterable attributes.
 // @Tags Products
 // @Produce json
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Param category_id query int false "Category ID"
 // @Success 200 {object} utils.Response{data=dto.ProductResponse} "Products fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid filter"
 // @Failure 500 {object} utils.Response "Internal server error"
 // @Router /products [get]
 func (h *productHandler) GetProducts(c *gin.Context) {
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
 
-	products, meta, err := h.pd.GetProducts(page, limit)
+	filter, err := parseProductFilter(c)
+	if err != nil {
+		utils.BadRequest(c, "invalid filter", err)
+		return
+	}
+
+	products, meta, err := h.pd.GetProducts(filter, page, limit)
 	if err != nil {
-		utils.InternalServerError(c, "failed to get products", err)
+		handleAttributeError(c, "failed to get products", err)
 		return
 	}
 
@@ -254,7 +420,7 @@ func (h *productHandler) UpdateProduct(c *gin.Context) {
 
 	product, prdErr := h.pd.UpdateProduct(uint(productID), &req)
 	if prdErr != nil {
-		utils.InternalServerError(c, "failed to update product", prdErr)
+		handleAttributeError(c, "failed to update product", prdErr)
 		return
 	}
 
@@ -285,16 +451,19 @@ func (h *productHandler) DeleteProduct(c *gin.Context) {
 }
 
 // @Summary Upload product image
-// @Description Upload product image
+// @Description Upload a JPEG, PNG, GIF or WebP product image. The file type is detected from its content, metadata is stripped and thumbnail, medium and large renditions are generated.
 // @Tags Products
 // @Accept multipart/form-data
+// @Produce json
 // @Security BearerAuth
 // @Param id path uint true "Product ID"
 // @Param image formData file true "Image"
-// @Success 200 {object} utils.Response "Image uploaded successfully"
+// @Param alt_text formData string false "Alternative text"
+// @Success 201 {object} utils.Response{data=dto.ProductImageResponse} "Image uploaded successfully"
 // @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Product not found"
 // @Failure 500 {object} utils.Response "Internal server error"
-// @Router /products/{id}/image [post]
+// @Router /products/{id}/upload [post]
 func (h *productHandler) UploadProductImage(c *gin.Context) {
 	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
@@ -308,16 +477,452 @@ func (h *productHandler) UploadProductImage(c *gin.Context) {
 		return
 	}
 
-	url, err := h.us.UploadProductImage(uint(productID), file)
+	altText := strings.TrimSpace(c.PostForm("alt_text"))
+	if len(altText) > 255 {
+		utils.BadRequest(c, "invalid alt text", errors.New("alt text must be at most 255 characters"))
+		return
+	}
+
+	uploaded, err := h.us.UploadProductImage(uint(productID), file)
 	if err != nil {
+		if errors.Is(err, uploadService.ErrInvalidImage) || errors.Is(err, uploadService.ErrFileTooLarge) {
+			utils.BadRequest(c, "invalid image", err)
+			return
+		}
 		utils.InternalServerError(c, "failed to upload image", err)
 		return
 	}
 
-	if err := h.pd.AddProductImage(uint(productID), url, ""); err != nil {
-		utils.InternalServerError(c, "failed to upload image", err)
+	image, err := h.pd.AddProductImage(uint(productID), uploaded, altText)
+	if err != nil {
+		handleProductImageError(c, "failed to upload image", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Image uploaded successfully", image)
+}
+
+// @Summary Request product image upload URL
+// @Description Get a presigned URL to upload a product image straight to storage. Send the file with the returned method and headers, then confirm the upload with the returned key.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param request body dto.ProductImageUploadURLRequest true "Upload details"
+// @Success 200 {object} utils.Response{data=dto.ProductImageUploadURLResponse} "Upload URL created successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Product not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images/upload-url [post]
+func (h *productHandler) RequestProductImageUpload(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	var req dto.ProductImageUploadURLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	if _, err := h.pd.GetProductById(uint(productID)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			utils.NotFound(c, "product not found", err)
+			return
+		}
+		utils.InternalServerError(c, "failed to create upload url", err)
+		return
+	}
+
+	upload, err := h.us.RequestProductImageUpload(uint(productID), &req)
+	if err != nil {
+		if errors.Is(err, uploadService.ErrFileTooLarge) {
+			utils.BadRequest(c, "invalid image", err)
+			return
+		}
+		utils.InternalServerError(c, "failed to create upload url", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Upload URL created successfully", upload)
+}
+
+// @Summary Confirm product image upload
+// @Description Verify an image uploaded through a presigned URL and attach it to the product. The file is checked and processed the same way as a direct upload.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param request body dto.ConfirmProductImageUploadRequest true "Uploaded image"
+// @Success 201 {object} utils.Response{data=dto.ProductImageResponse} "Image uploaded successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Upload not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images/confirm [post]
+func (h *productHandler) ConfirmProductImageUpload(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	var req dto.ConfirmProductImageUploadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	uploaded, err := h.us.ConfirmProductImageUpload(uint(productID), req.Key)
+	if err != nil {
+		switch {
+		case errors.Is(err, uploadService.ErrUploadNotFound):
+			utils.NotFound(c, "upload not found", err)
+		case errors.Is(err, uploadService.ErrInvalidImage), errors.Is(err, uploadService.ErrFileTooLarge):
+			utils.BadRequest(c, "invalid image", err)
+		default:
+			utils.InternalServerError(c, "failed to confirm upload", err)
+		}
+		return
+	}
+
+	image, err := h.pd.AddProductImage(uint(productID), uploaded, strings.TrimSpace(req.AltText))
+	if err != nil {
+		handleProductImageError(c, "failed to confirm upload", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Image uploaded successfully", image)
+}
+
+// @Summary Get product images
+// @Description Get the images of a product in display order
+// @Tags Products
+// @Produce json
+// @Param id path uint true "Product ID"
+// @Success 200 {object} utils.Response{data=[]dto.ProductImageResponse} "Images fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Product not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images [get]
+func (h *productHandler) GetProductImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
 		return
 	}
 
-	utils.SuccessResponse(c, "Image uploaded successfully", nil)
+	images, err := h.pd.GetProductImages(uint(productID))
+	if err != nil {
+		handleProductImageError(c, "failed to get images", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Images fetched successfully", images)
+}
+
+// @Summary Update product image
+// @Description Edit the alt text of a product image
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param imageId path uint true "Image ID"
+// @Param request body dto.UpdateProductImageRequest true "Image data"
+// @Success 200 {object} utils.Response{data=dto.ProductImageResponse} "Image updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Image not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images/{imageId} [put]
+func (h *productHandler) UpdateProductImage(c *gin.Context) {
+	productID, imageID, err := parseProductImageParams(c)
+	if err != nil {
+		utils.BadRequest(c, "invalid image id", err)
+		return
+	}
+
+	var req dto.UpdateProductImageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	image, err := h.pd.UpdateProductImage(productID, imageID, &req)
+	if err != nil {
+		handleProductImageError(c, "failed to update image", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Image updated successfully", image)
+}
+
+// @Summary Set primary product image
+// @Description Make an image the product's only primary image
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param imageId path uint true "Image ID"
+// @Success 200 {object} utils.Response{data=[]dto.ProductImageResponse} "Primary image updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Image not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images/{imageId}/primary [put]
+func (h *productHandler) SetPrimaryProductImage(c *gin.Context) {
+	productID, imageID, err := parseProductImageParams(c)
+	if err != nil {
+		utils.BadRequest(c, "invalid image id", err)
+		return
+	}
+
+	images, err := h.pd.SetPrimaryProductImage(productID, imageID)
+	if err != nil {
+		handleProductImageError(c, "failed to set primary image", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Primary image updated successfully", images)
+}
+
+// @Summary Reorder product images
+// @Description Set the display order of a product's images. Every image of the product must be listed exactly once.
+// @Tags Products
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param request body dto.ReorderProductImagesRequest true "Image IDs in display order"
+// @Success 200 {object} utils.Response{data=[]dto.ProductImageResponse} "Images reordered successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images/order [put]
+func (h *productHandler) ReorderProductImages(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	var req dto.ReorderProductImagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	images, err := h.pd.ReorderProductImages(uint(productID), &req)
+	if err != nil {
+		handleProductImageError(c, "failed to reorder images", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Images reordered successfully", images)
+}
+
+// @Summary Delete product image
+// @Description Delete a product image. If it was the primary image, the next image becomes primary. Stored files are removed by the storage garbage collector once no image uses them.
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param imageId path uint true "Image ID"
+// @Success 200 {object} utils.Response "Image deleted successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Image not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/images/{imageId} [delete]
+func (h *productHandler) DeleteProductImage(c *gin.Context) {
+	productID, imageID, err := parseProductImageParams(c)
+	if err != nil {
+		utils.BadRequest(c, "invalid image id", err)
+		return
+	}
+
+	if err := h.pd.DeleteProductImage(productID, imageID); err != nil {
+		handleProductImageError(c, "failed to delete image", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Image deleted successfully", nil)
+}
+
+// Import / export
+
+// @Summary Import products
+// @Description Upload a CSV of products. Rows are created or updated by SKU and categories are matched by name. The import runs in the background; poll the returned job for progress and per-row errors.
+// @Tags Products
+// @Accept multipart/form-data
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file true "CSV file"
+// @Success 202 {object} utils.Response{data=dto.ImportJobResponse} "Import started"
+// @Failure 400 {object} utils.Response "Invalid file"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/import [post]
+func (h *productHandler) ImportProducts(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.BadRequest(c, "invalid file", err)
+		return
+	}
+
+	if strings.ToLower(filepath.Ext(file.Filename)) != ".csv" {
+		utils.BadRequest(c, "invalid file", errors.New("file must be a .csv"))
+		return
+	}
+
+	if file.Size > h.cfg.Upload.MaxUploadSize {
+		utils.BadRequest(c, "invalid file", errors.New("file is too large"))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		utils.BadRequest(c, "invalid file", err)
+		return
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	job, err := h.is.StartProductImport(userID, file.Filename, src)
+	if err != nil {
+		if errors.Is(err, importService.ErrInvalidImportFile) {
+			utils.BadRequest(c, "invalid file", err)
+			return
+		}
+		utils.InternalServerError(c, "failed to start import", err)
+		return
+	}
+
+	utils.AcceptedResponse(c, "Import started", job)
+}
+
+// @Summary Get import job
+// @Description Get the status, progress and row errors of a product import
+// @Tags Products
+// @Produce json
+// @Security BearerAuth
+// @Param jobId path uint true "Import job ID"
+// @Success 200 {object} utils.Response{data=dto.ImportJobResponse} "Import job fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Import job not found"
+// @Router /products/import/{jobId} [get]
+func (h *productHandler) GetImportJob(c *gin.Context) {
+	jobID, err := strconv.ParseUint(c.Param("jobId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid job id", err)
+		return
+	}
+
+	job, err := h.is.GetImportJob(uint(jobID))
+	if err != nil {
+		utils.NotFound(c, "import job not found", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Import job fetched successfully", job)
+}
+
+// @Summary Export products
+// @Description Download the whole catalog as CSV in the same layout accepted by the import
+// @Tags Products
+// @Produce text/csv
+// @Security BearerAuth
+// @Success 200 {file} file "CSV file"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/export [get]
+func (h *productHandler) ExportProducts(c *gin.Context) {
+	fileName := fmt.Sprintf("products-%s.csv", time.Now().Format("20060102-150405"))
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+
+	if err := h.is.ExportProducts(c.Writer); err != nil {
+		if c.Writer.Written() {
+			// The status line is already sent; all that is left is to log.
+			_ = c.Error(err)
+			return
+		}
+
+		c.Writer.Header().Del("Content-Type")
+		c.Writer.Header().Del("Content-Disposition")
+		utils.InternalServerError(c, "failed to export products", err)
+	}
+}
+
+// Helper
+func parseProductImageParams(c *gin.Context) (productID, imageID uint, err error) {
+	pid, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	iid, err := strconv.ParseUint(c.Param("imageId"), 10, 32)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return uint(pid), uint(iid), nil
+}
+
+func handleProductImageError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, productService.ErrProductNotFound),
+		errors.Is(err, productService.ErrProductImageNotFound):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, productService.ErrImageLimitReached),
+		errors.Is(err, productService.ErrInvalidImageOrder):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
+
+// handleAttributeError answers attribute values or definitions that break
+// the category schema with 400.
+func handleAttributeError(c *gin.Context, message string, err error) {
+	if errors.Is(err, productService.ErrInvalidAttribute) {
+		utils.BadRequest(c, message, err)
+		return
+	}
+	utils.InternalServerError(c, message, err)
+}
+
+func parseProductFilter(c *gin.Context) (*dto.ProductFilter, error) {
+	filter := &dto.ProductFilter{
+		Attributes:   c.QueryMap("attr"),
+		AttributeMin: map[string]float64{},
+		AttributeMax: map[string]float64{},
+	}
+
+	if categoryID := c.Query("category_id"); categoryID != "" {
+		id, err := strconv.ParseUint(categoryID, 10, 32)
+		if err != nil {
+			return nil, err
+		}
+		filter.CategoryID = uint(id)
+	}
+
+	for key, value := range c.QueryMap("attr_min") {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		filter.AttributeMin[key] = number
+	}
+
+	for key, value := range c.QueryMap("attr_max") {
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, err
+		}
+		filter.AttributeMax[key] = number
+	}
+
+	return filter, nil
 }
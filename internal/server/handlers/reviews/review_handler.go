@@ -0,0 +1,285 @@
+package reviewHandler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	reviewService "github.com/anzhy11/go-e-commerce/internal/services/reviews"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ReviewHandlerInterface interface {
+	GetProductReviews(c *gin.Context)
+	GetMyReview(c *gin.Context)
+	CreateReview(c *gin.Context)
+	UpdateReview(c *gin.Context)
+	DeleteReview(c *gin.Context)
+	GetModerationQueue(c *gin.Context)
+	ModerateReview(c *gin.Context)
+}
+
+type reviewHandler struct {
+	reviewService reviewService.ReviewServiceInterface
+}
+
+func New(db *gorm.DB) ReviewHandlerInterface {
+	return &reviewHandler{
+		reviewService: reviewService.New(db),
+	}
+}
+
+// @Summary Get product reviews
+// @Description Get the approved reviews of a product
+// @Tags Reviews
+// @Produce json
+// @Param id path uint true "Product ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} utils.Response{data=[]dto.ReviewResponse} "Reviews fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/reviews [get]
+func (h *reviewHandler) GetProductReviews(c *gin.Context) {
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	reviews, meta, err := h.reviewService.GetProductReviews(uint(productID), page, limit)
+	if err != nil {
+		utils.InternalServerError(c, "failed to get reviews", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Reviews fetched successfully", gin.H{
+		"reviews": reviews,
+		"meta":    meta,
+	})
+}
+
+// @Summary Get my review
+// @Description Get the current user's review of a product, whatever its moderation status
+// @Tags Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Success 200 {object} utils.Response{data=dto.ReviewResponse} "Review fetched successfully"
+// @Failure 404 {object} utils.Response "Review not found"
+// @Router /products/{id}/reviews/me [get]
+func (h *reviewHandler) GetMyReview(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	review, err := h.reviewService.GetUserReview(userID, uint(productID))
+	if err != nil {
+		handleReviewError(c, "failed to get review", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Review fetched successfully", review)
+}
+
+// @Summary Create review
+// @Description Review a product from a delivered order. New reviews are published after moderation.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param request body dto.CreateReviewRequest true "Review data"
+// @Success 201 {object} utils.Response{data=dto.ReviewResponse} "Review submitted successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or already reviewed"
+// @Failure 403 {object} utils.Response "Product not purchased"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/reviews [post]
+func (h *reviewHandler) CreateReview(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	var req dto.CreateReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	review, err := h.reviewService.CreateReview(userID, uint(productID), &req)
+	if err != nil {
+		handleReviewError(c, "failed to create review", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Review submitted successfully", review)
+}
+
+// @Summary Update review
+// @Description Edit your own review. Edited reviews go back to moderation.
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param reviewId path uint true "Review ID"
+// @Param request body dto.UpdateReviewRequest true "Review data"
+// @Success 200 {object} utils.Response{data=dto.ReviewResponse} "Review updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Review not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/reviews/{reviewId} [put]
+func (h *reviewHandler) UpdateReview(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid review id", err)
+		return
+	}
+
+	var req dto.UpdateReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	review, err := h.reviewService.UpdateReview(userID, uint(productID), uint(reviewID), &req)
+	if err != nil {
+		handleReviewError(c, "failed to update review", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Review updated successfully", review)
+}
+
+// @Summary Delete review
+// @Description Delete your own review
+// @Tags Reviews
+// @Security BearerAuth
+// @Param id path uint true "Product ID"
+// @Param reviewId path uint true "Review ID"
+// @Success 200 {object} utils.Response "Review deleted successfully"
+// @Failure 404 {object} utils.Response "Review not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /products/{id}/reviews/{reviewId} [delete]
+func (h *reviewHandler) DeleteReview(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	reviewID, err := strconv.ParseUint(c.Param("reviewId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid review id", err)
+		return
+	}
+
+	if err := h.reviewService.DeleteReview(userID, uint(productID), uint(reviewID)); err != nil {
+		handleReviewError(c, "failed to delete review", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Review deleted successfully", nil)
+}
+
+// @Summary Get moderation queue
+// @Description List reviews by moderation status, oldest first
+// @Tags Reviews
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Review status" Enums(pending, approved, rejected)
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} utils.Response{data=[]dto.ReviewResponse} "Reviews fetched successfully"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/reviews [get]
+func (h *reviewHandler) GetModerationQueue(c *gin.Context) {
+	status := c.DefaultQuery("status", "pending")
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	reviews, meta, err := h.reviewService.GetModerationQueue(status, page, limit)
+	if err != nil {
+		utils.InternalServerError(c, "failed to get reviews", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Reviews fetched successfully", gin.H{
+		"reviews": reviews,
+		"meta":    meta,
+	})
+}
+
+// @Summary Moderate review
+// @Description Approve or reject a review
+// @Tags Reviews
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "Review ID"
+// @Param request body dto.ModerateReviewRequest true "Moderation decision"
+// @Success 200 {object} utils.Response{data=dto.ReviewResponse} "Review moderated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Review not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/reviews/{id}/moderate [put]
+func (h *reviewHandler) ModerateReview(c *gin.Context) {
+	moderatorID := c.GetUint("user_id")
+
+	reviewID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid review id", err)
+		return
+	}
+
+	var req dto.ModerateReviewRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	review, err := h.reviewService.ModerateReview(moderatorID, uint(reviewID), &req)
+	if err != nil {
+		handleReviewError(c, "failed to moderate review", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Review moderated successfully", review)
+}
+
+// Helper
+func handleReviewError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, reviewService.ErrReviewNotFound):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, reviewService.ErrNotVerifiedPurchase):
+		utils.Forbidden(c, message, err)
+	case errors.Is(err, reviewService.ErrAlreadyReviewed):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
@@ -0,0 +1,224 @@
+package roleHandler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	roleService "github.com/anzhy11/go-e-commerce/internal/services/roles"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type RoleHandlerInterface interface {
+	GetPermissions(c *gin.Context)
+	GetRoles(c *gin.Context)
+	GetRole(c *gin.Context)
+	CreateRole(c *gin.Context)
+	UpdateRole(c *gin.Context)
+	DeleteRole(c *gin.Context)
+	AssignUserRole(c *gin.Context)
+}
+
+type roleHandler struct {
+	roleService roleService.RoleServiceInterface
+}
+
+func New(roles roleService.RoleServiceInterface) RoleHandlerInterface {
+	return &roleHandler{
+		roleService: roles,
+	}
+}
+
+// @Summary Get permissions
+// @Description List the permissions roles can be given
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.PermissionResponse} "Permissions fetched successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/permissions [get]
+func (h *roleHandler) GetPermissions(c *gin.Context) {
+	permissions, err := h.roleService.GetPermissions()
+	if err != nil {
+		utils.InternalServerError(c, "failed to get permissions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Permissions fetched successfully", permissions)
+}
+
+// @Summary Get roles
+// @Description List the roles with their permissions
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.RoleResponse} "Roles fetched successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/roles [get]
+func (h *roleHandler) GetRoles(c *gin.Context) {
+	roles, err := h.roleService.GetRoles()
+	if err != nil {
+		utils.InternalServerError(c, "failed to get roles", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Roles fetched successfully", roles)
+}
+
+// @Summary Get role
+// @Description Get a role with its permissions
+// @Tags Roles
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Success 200 {object} utils.Response{data=dto.RoleResponse} "Role fetched successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "Role not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/roles/{name} [get]
+func (h *roleHandler) GetRole(c *gin.Context) {
+	role, err := h.roleService.GetRole(c.Param("name"))
+	if err != nil {
+		handleRoleError(c, "failed to get role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Role fetched successfully", role)
+}
+
+// @Summary Create role
+// @Description Create a role with a set of permissions
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.CreateRoleRequest true "Role data"
+// @Success 201 {object} utils.Response{data=dto.RoleResponse} "Role created successfully"
+// @Failure 400 {object} utils.Response "Invalid request data, role exists or unknown permission"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/roles [post]
+func (h *roleHandler) CreateRole(c *gin.Context) {
+	var req dto.CreateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	role, err := h.roleService.CreateRole(&req)
+	if err != nil {
+		handleRoleError(c, "failed to create role", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Role created successfully", role)
+}
+
+// @Summary Update role
+// @Description Replace the description and permissions of a role. System roles cannot be changed.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Param request body dto.UpdateRoleRequest true "Role data"
+// @Success 200 {object} utils.Response{data=dto.RoleResponse} "Role updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data, system role or unknown permission"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "Role not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/roles/{name} [put]
+func (h *roleHandler) UpdateRole(c *gin.Context) {
+	var req dto.UpdateRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	role, err := h.roleService.UpdateRole(c.Param("name"), &req)
+	if err != nil {
+		handleRoleError(c, "failed to update role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Role updated successfully", role)
+}
+
+// @Summary Delete role
+// @Description Delete a role no user has. System roles cannot be deleted.
+// @Tags Roles
+// @Security BearerAuth
+// @Param name path string true "Role name"
+// @Success 200 {object} utils.Response "Role deleted successfully"
+// @Failure 400 {object} utils.Response "System role or role still assigned"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "Role not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/roles/{name} [delete]
+func (h *roleHandler) DeleteRole(c *gin.Context) {
+	if err := h.roleService.DeleteRole(c.Param("name")); err != nil {
+		handleRoleError(c, "failed to delete role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Role deleted successfully", nil)
+}
+
+// @Summary Assign user role
+// @Description Give a user another role. The user is signed out of all their sessions.
+// @Tags Roles
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Param request body dto.AssignRoleRequest true "Role"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "Role assigned successfully"
+// @Failure 400 {object} utils.Response "Invalid request data or last admin"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User or role not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/role [put]
+func (h *roleHandler) AssignUserRole(c *gin.Context) {
+	actorID := c.GetUint("user_id")
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user id", err)
+		return
+	}
+
+	var req dto.AssignRoleRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	user, err := h.roleService.AssignUserRole(actorID, uint(userID), &req)
+	if err != nil {
+		handleRoleError(c, "failed to assign role", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Role assigned successfully", user)
+}
+
+// Helper
+func handleRoleError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, roleService.ErrRoleNotFound),
+		errors.Is(err, roleService.ErrUserNotFound):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, roleService.ErrRoleExists),
+		errors.Is(err, roleService.ErrInvalidRoleName),
+		errors.Is(err, roleService.ErrUnknownPermission),
+		errors.Is(err, roleService.ErrSystemRole),
+		errors.Is(err, roleService.ErrRoleInUse),
+		errors.Is(err, roleService.ErrLastAdmin):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
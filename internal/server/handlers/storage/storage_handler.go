@@ -0,0 +1,105 @@
+package storageHandler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/providers"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/gin-gonic/gin"
+)
+
+type StorageHandlerInterface interface {
+	UploadObject(c *gin.Context)
+	DownloadObject(c *gin.Context)
+}
+
+// storageHandler serves presigned requests for the local upload provider,
+// so clients use the same request-then-confirm flow as with S3.
+type storageHandler struct {
+	provider *providers.LocalUploadProvider
+	cfg      *config.Config
+}
+
+func New(cfg *config.Config, provider *providers.LocalUploadProvider) StorageHandlerInterface {
+	return &storageHandler{
+		provider: provider,
+		cfg:      cfg,
+	}
+}
+
+// @Summary Upload object with a presigned URL
+// @Description Store a file at a URL issued by the local upload provider. Only available when UPLOAD_PROVIDER is local.
+// @Tags Storage
+// @Accept octet-stream
+// @Produce json
+// @Param path path string true "Object path"
+// @Param expires query int true "Expiry timestamp"
+// @Param signature query string true "Signature"
+// @Success 200 {object} utils.Response "Object uploaded successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 403 {object} utils.Response "Invalid or expired signature"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /storage/{path} [put]
+func (h *storageHandler) UploadObject(c *gin.Context) {
+	path := strings.TrimPrefix(c.Param("path"), "/")
+	contentType := c.GetHeader("Content-Type")
+
+	if err := h.provider.VerifySignedRequest(http.MethodPut, path, contentType, c.Query("expires"), c.Query("signature")); err != nil {
+		utils.Forbidden(c, "invalid signature", err)
+		return
+	}
+
+	body := c.Request.Body
+	if h.cfg.Upload.MaxUploadSize > 0 {
+		body = http.MaxBytesReader(c.Writer, body, h.cfg.Upload.MaxUploadSize)
+	}
+
+	if _, err := h.provider.UploadObject(body, path, contentType); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			_ = h.provider.DeleteFile(path)
+			utils.BadRequest(c, "file is too large", err)
+			return
+		}
+		utils.InternalServerError(c, "failed to upload object", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Object uploaded successfully", nil)
+}
+
+// @Summary Download object with a presigned URL
+// @Description Fetch a file from a URL issued by the local upload provider. Only available when UPLOAD_PROVIDER is local.
+// @Tags Storage
+// @Produce octet-stream
+// @Param path path string true "Object path"
+// @Param expires query int true "Expiry timestamp"
+// @Param signature query string true "Signature"
+// @Success 200 {file} file "Object content"
+// @Failure 403 {object} utils.Response "Invalid or expired signature"
+// @Failure 404 {object} utils.Response "Object not found"
+// @Router /storage/{path} [get]
+func (h *storageHandler) DownloadObject(c *gin.Context) {
+	path := strings.TrimPrefix(c.Param("path"), "/")
+
+	if err := h.provider.VerifySignedRequest(http.MethodGet, path, "", c.Query("expires"), c.Query("signature")); err != nil {
+		utils.Forbidden(c, "invalid signature", err)
+		return
+	}
+
+	if _, err := h.provider.StatObject(path); err != nil {
+		utils.NotFound(c, "object not found", err)
+		return
+	}
+
+	filePath, err := h.provider.FilePath(path)
+	if err != nil {
+		utils.NotFound(c, "object not found", err)
+		return
+	}
+
+	c.File(filePath)
+}
@@ -1,7 +1,15 @@
 package userHandler
 
 import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/dto"
+	orderService "github.com/anzhy11/go-e-commerce/internal/services/orders"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
 	userService "github.com/anzhy11/go-e-commerce/internal/services/users"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
@@ -11,15 +19,29 @@ import (
 type UserHandlerInterface interface {
 	GetProfile(c *gin.Context)
 	UpdateProfile(c *gin.Context)
+	GetSessions(c *gin.Context)
+	RevokeSession(c *gin.Context)
+	RevokeOtherSessions(c *gin.Context)
+	GetUsers(c *gin.Context)
+	GetUser(c *gin.Context)
+	GetUserOrders(c *gin.Context)
+	GetUserSessions(c *gin.Context)
+	DeactivateUser(c *gin.Context)
+	ReactivateUser(c *gin.Context)
+	GetAuditLogs(c *gin.Context)
 }
 
 type userHandler struct {
-	userService userService.UserServiceInterface
+	userService    userService.UserServiceInterface
+	sessionService sessionService.SessionServiceInterface
+	orderService   orderService.OrderServiceInterface
 }
 
-func New(db *gorm.DB) UserHandlerInterface {
+func New(db *gorm.DB, cfg *config.Config, sessions sessionService.SessionServiceInterface, products productService.ProductServiceInterface) UserHandlerInterface {
 	return &userHandler{
-		userService: userService.New(db),
+		userService:    userService.New(db, sessions),
+		sessionService: sessions,
+		orderService:   orderService.New(db, cfg, products),
 	}
 }
 
@@ -71,3 +93,343 @@ func (h *userHandler) UpdateProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, "User profile updated successfully", user)
 }
+
+// @Summary List sessions
+// @Description List the devices signed in to the authenticated user's account
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.SessionResponse} "Sessions fetched successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/me/sessions [get]
+func (h *userHandler) GetSessions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	sessions, err := h.sessionService.GetSessions(userID, c.GetString("session_id"))
+	if err != nil {
+		utils.InternalServerError(c, "failed to fetch sessions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Sessions fetched successfully", sessions)
+}
+
+// @Summary Revoke session
+// @Description Sign one device out. Revoking the current session logs out
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Session ID"
+// @Success 200 {object} utils.Response "Session revoked successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 404 {object} utils.Response "Session not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/me/sessions/{id} [delete]
+func (h *userHandler) RevokeSession(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	if err := h.sessionService.RevokeSession(userID, c.Param("id")); err != nil {
+		handleSessionError(c, "failed to revoke session", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Session revoked successfully", nil)
+}
+
+// @Summary Revoke other sessions
+// @Description Sign every device out but the one making the request
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=dto.RevokeSessionsResponse} "Other sessions revoked successfully"
+// @Failure 401 {object} utils.Response "Unauthorized"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/me/sessions [delete]
+func (h *userHandler) RevokeOtherSessions(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	revoked, err := h.sessionService.RevokeOtherSessions(userID, c.GetString("session_id"))
+	if err != nil {
+		handleSessionError(c, "failed to revoke sessions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Other sessions revoked successfully", dto.RevokeSessionsResponse{Revoked: revoked})
+}
+
+// @Summary List users
+// @Description List user accounts, newest first. The search matches part of the email or name.
+// @Tags Admin Users
+// @Produce json
+// @Security BearerAuth
+// @Param search query string false "Email or name"
+// @Param role query string false "Role"
+// @Param is_active query bool false "Active or deactivated accounts"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} utils.Response{data=[]dto.UserResponse} "Users fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid filter"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users [get]
+func (h *userHandler) GetUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := &dto.UserFilter{
+		Search: c.Query("search"),
+		Role:   c.Query("role"),
+	}
+	if isActive := c.Query("is_active"); isActive != "" {
+		active, err := strconv.ParseBool(isActive)
+		if err != nil {
+			utils.BadRequest(c, "invalid filter", fmt.Errorf("is_active: %w", err))
+			return
+		}
+		filter.IsActive = &active
+	}
+
+	users, meta, err := h.userService.GetUsers(filter, page, limit)
+	if err != nil {
+		utils.InternalServerError(c, "failed to get users", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Users fetched successfully", gin.H{
+		"users": users,
+		"meta":  meta,
+	})
+}
+
+// @Summary Get user
+// @Description Get a user account
+// @Tags Admin Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "User fetched successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id} [get]
+func (h *userHandler) GetUser(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user id", err)
+		return
+	}
+
+	user, err := h.userService.GetProfile(uint(userID))
+	if err != nil {
+		handleUserError(c, "failed to get user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User fetched successfully", user)
+}
+
+// @Summary Get user orders
+// @Description List the orders of a user
+// @Tags Admin Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} utils.Response{data=[]dto.OrderResponse} "Orders fetched successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/orders [get]
+func (h *userHandler) GetUserOrders(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user id", err)
+		return
+	}
+
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	if _, err := h.userService.GetProfile(uint(userID)); err != nil {
+		handleUserError(c, "failed to get orders", err)
+		return
+	}
+
+	orders, meta, err := h.orderService.GetOrders(uint(userID), page, limit)
+	if err != nil {
+		utils.InternalServerError(c, "failed to get orders", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Orders fetched successfully", gin.H{
+		"orders": orders,
+		"meta":   meta,
+	})
+}
+
+// @Summary Get user sessions
+// @Description List the devices signed in to a user's account
+// @Tags Admin Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Success 200 {object} utils.Response{data=[]dto.SessionResponse} "Sessions fetched successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/sessions [get]
+func (h *userHandler) GetUserSessions(c *gin.Context) {
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user id", err)
+		return
+	}
+
+	if _, err := h.userService.GetProfile(uint(userID)); err != nil {
+		handleUserError(c, "failed to fetch sessions", err)
+		return
+	}
+
+	sessions, err := h.sessionService.GetSessions(uint(userID), "")
+	if err != nil {
+		utils.InternalServerError(c, "failed to fetch sessions", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Sessions fetched successfully", sessions)
+}
+
+// @Summary Deactivate user
+// @Description Stop a user from signing in and sign them out of every session. The reason goes to the audit log.
+// @Tags Admin Users
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Param request body dto.DeactivateUserRequest true "Reason"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "User deactivated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data, own account or last active admin"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/deactivate [post]
+func (h *userHandler) DeactivateUser(c *gin.Context) {
+	actorID := c.GetUint("user_id")
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user id", err)
+		return
+	}
+
+	var req dto.DeactivateUserRequest
+	if err = c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	user, err := h.userService.DeactivateUser(actorID, uint(userID), &req)
+	if err != nil {
+		handleUserError(c, "failed to deactivate user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User deactivated successfully", user)
+}
+
+// @Summary Reactivate user
+// @Description Let a deactivated user sign in again
+// @Tags Admin Users
+// @Produce json
+// @Security BearerAuth
+// @Param id path uint true "User ID"
+// @Success 200 {object} utils.Response{data=dto.UserResponse} "User reactivated successfully"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 404 {object} utils.Response "User not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/users/{id}/reactivate [post]
+func (h *userHandler) ReactivateUser(c *gin.Context) {
+	actorID := c.GetUint("user_id")
+
+	userID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid user id", err)
+		return
+	}
+
+	user, err := h.userService.ReactivateUser(actorID, uint(userID))
+	if err != nil {
+		handleUserError(c, "failed to reactivate user", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "User reactivated successfully", user)
+}
+
+// @Summary Get audit log
+// @Description List the actions staff took on user accounts, newest first
+// @Tags Admin Users
+// @Produce json
+// @Security BearerAuth
+// @Param user_id query int false "User the action was taken on"
+// @Param actor_id query int false "Staff member who took the action"
+// @Param action query string false "Action" Enums(user.deactivated, user.reactivated, user.role_changed, user.password_reset_forced, user.unlocked)
+// @Param page query int false "Page"
+// @Param limit query int false "Limit"
+// @Success 200 {object} utils.Response{data=[]dto.AuditLogResponse} "Audit log fetched successfully"
+// @Failure 400 {object} utils.Response "Invalid filter"
+// @Failure 403 {object} utils.Response "Missing permission"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /admin/audit-logs [get]
+func (h *userHandler) GetAuditLogs(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	filter := &dto.AuditLogFilter{Action: c.Query("action")}
+	for param, id := range map[string]*uint{"user_id": &filter.UserID, "actor_id": &filter.ActorID} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.BadRequest(c, "invalid filter", fmt.Errorf("%s: %w", param, err))
+			return
+		}
+		*id = uint(parsed)
+	}
+
+	logs, meta, err := h.userService.GetAuditLogs(filter, page, limit)
+	if err != nil {
+		utils.InternalServerError(c, "failed to get audit log", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Audit log fetched successfully", gin.H{
+		"audit_logs": logs,
+		"meta":       meta,
+	})
+}
+
+func handleUserError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, userService.ErrUserNotFound):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, userService.ErrCannotDeactivateSelf),
+		errors.Is(err, userService.ErrLastAdmin):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
+
+func handleSessionError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, sessionService.ErrSessionNotFound):
+		utils.NotFound(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
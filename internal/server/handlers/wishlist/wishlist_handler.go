@@ -0,0 +1,177 @@
+package wishlistHandler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	wishlistService "github.com/anzhy11/go-e-commerce/internal/services/wishlist"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type WishlistHandlerInterface interface {
+	GetWishlist(c *gin.Context)
+	AddToWishlist(c *gin.Context)
+	UpdateWishlistItem(c *gin.Context)
+	RemoveFromWishlist(c *gin.Context)
+	Unsubscribe(c *gin.Context)
+}
+
+type wishlistHandler struct {
+	wishlistService wishlistService.WishlistServiceInterface
+}
+
+func New(db *gorm.DB, cfg *config.Config) WishlistHandlerInterface {
+	return &wishlistHandler{
+		wishlistService: wishlistService.New(db, cfg),
+	}
+}
+
+// @Summary Get wishlist
+// @Description Get the current user's wishlist
+// @Tags Wishlist
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} utils.Response{data=[]dto.WishlistItemResponse} "Wishlist fetched successfully"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/wishlist [get]
+func (h *wishlistHandler) GetWishlist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	items, err := h.wishlistService.GetWishlist(userID)
+	if err != nil {
+		utils.InternalServerError(c, "failed to get wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist fetched successfully", items)
+}
+
+// @Summary Add to wishlist
+// @Description Add a product to the current user's wishlist. Back-in-stock and price-drop alerts are on unless disabled.
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dto.AddToWishlistRequest true "Wishlist item"
+// @Success 201 {object} utils.Response{data=dto.WishlistItemResponse} "Product added to wishlist"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Product not found"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/wishlist [post]
+func (h *wishlistHandler) AddToWishlist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	var req dto.AddToWishlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	item, err := h.wishlistService.AddToWishlist(userID, &req)
+	if err != nil {
+		handleWishlistError(c, "failed to add product to wishlist", err)
+		return
+	}
+
+	utils.CreatedResponse(c, "Product added to wishlist", item)
+}
+
+// @Summary Update wishlist item
+// @Description Change which alerts are sent for a wishlisted product
+// @Tags Wishlist
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param productId path uint true "Product ID"
+// @Param request body dto.UpdateWishlistItemRequest true "Alert settings"
+// @Success 200 {object} utils.Response{data=dto.WishlistItemResponse} "Wishlist item updated successfully"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Product is not in the wishlist"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/wishlist/{productId} [put]
+func (h *wishlistHandler) UpdateWishlistItem(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	var req dto.UpdateWishlistItemRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.BadRequest(c, "invalid request", err)
+		return
+	}
+
+	item, err := h.wishlistService.UpdateWishlistItem(userID, uint(productID), &req)
+	if err != nil {
+		handleWishlistError(c, "failed to update wishlist item", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Wishlist item updated successfully", item)
+}
+
+// @Summary Remove from wishlist
+// @Description Remove a product from the current user's wishlist
+// @Tags Wishlist
+// @Produce json
+// @Security BearerAuth
+// @Param productId path uint true "Product ID"
+// @Success 200 {object} utils.Response "Product removed from wishlist"
+// @Failure 400 {object} utils.Response "Invalid request data"
+// @Failure 404 {object} utils.Response "Product is not in the wishlist"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/wishlist/{productId} [delete]
+func (h *wishlistHandler) RemoveFromWishlist(c *gin.Context) {
+	userID := c.GetUint("user_id")
+
+	productID, err := strconv.ParseUint(c.Param("productId"), 10, 32)
+	if err != nil {
+		utils.BadRequest(c, "invalid product id", err)
+		return
+	}
+
+	if err := h.wishlistService.RemoveFromWishlist(userID, uint(productID)); err != nil {
+		handleWishlistError(c, "failed to remove product from wishlist", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Product removed from wishlist", nil)
+}
+
+// @Summary Unsubscribe from wishlist alerts
+// @Description Turn off all alerts for a wishlist item using the token from an alert email
+// @Tags Wishlist
+// @Produce json
+// @Param token query string true "Unsubscribe token"
+// @Success 200 {object} utils.Response "Unsubscribed successfully"
+// @Failure 400 {object} utils.Response "Invalid unsubscribe token"
+// @Failure 500 {object} utils.Response "Internal server error"
+// @Router /users/wishlist/unsubscribe [get]
+func (h *wishlistHandler) Unsubscribe(c *gin.Context) {
+	if err := h.wishlistService.Unsubscribe(c.Query("token")); err != nil {
+		handleWishlistError(c, "failed to unsubscribe", err)
+		return
+	}
+
+	utils.SuccessResponse(c, "Unsubscribed successfully", nil)
+}
+
+func handleWishlistError(c *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, wishlistService.ErrWishlistItemNotFound),
+		errors.Is(err, wishlistService.ErrProductNotFound):
+		utils.NotFound(c, message, err)
+	case errors.Is(err, wishlistService.ErrAlreadyInWishlist),
+		errors.Is(err, wishlistService.ErrInvalidUnsubscribe):
+		utils.BadRequest(c, message, err)
+	default:
+		utils.InternalServerError(c, message, err)
+	}
+}
@@ -1,10 +1,11 @@
 package middlewares
 
 import (
+	"errors"
+	"fmt"
 	"slices"
 	"strings"
 
-	"github.com/anzhy11/go-e-commerce/internal/models"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
 )
@@ -26,21 +27,44 @@ func (m *Middlewares) Authorization() gin.HandlerFunc {
 		}
 
 		tokenString := tokenParts[1]
-		payload, err := utils.VerifyToken(tokenString, m.cfg.JWT.Secret)
+		payload, err := m.tokens.VerifyAccessToken(tokenString)
 		if err != nil {
 			utils.Unauthorized(c, "Unauthorized", err)
 			c.Abort()
 			return
 		}
 
+		// Tokens without a session cannot be revoked, so they are refused.
+		if payload.SessionID == "" || payload.ID == "" {
+			utils.Unauthorized(c, "Unauthorized", errors.New("token has no session"))
+			c.Abort()
+			return
+		}
+
+		active, err := m.sessions.IsActive(payload.UserID, payload.SessionID)
+		if err != nil {
+			utils.InternalServerError(c, "failed to check session", err)
+			c.Abort()
+			return
+		}
+		if !active {
+			utils.Unauthorized(c, "Unauthorized", errors.New("session revoked or expired"))
+			c.Abort()
+			return
+		}
+
 		c.Set("user_id", payload.UserID)
 		c.Set("email", payload.Email)
 		c.Set("role", payload.Role)
+		c.Set("session_id", payload.SessionID)
+		c.Set("auth_methods", payload.AuthMethods)
 		c.Next()
 	}
 }
 
-func (m *Middlewares) AdminAuthorization() gin.HandlerFunc {
+// RequirePermission lets the request through when the role of the user has
+// every one of permissions. It runs after Authorization.
+func (m *Middlewares) RequirePermission(permissions ...string) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		role := c.GetString("role")
 		if role == "" {
@@ -49,31 +73,25 @@ func (m *Middlewares) AdminAuthorization() gin.HandlerFunc {
 			return
 		}
 
-		if role != string(models.RoleAdmin) {
-			utils.Forbidden(c, "Forbidden", nil)
+		allowed, err := m.roles.HasPermissions(role, permissions...)
+		if err != nil {
+			utils.InternalServerError(c, "failed to check permissions", err)
 			c.Abort()
 			return
 		}
-
-		c.Next()
-	}
-}
-
-func (m *Middlewares) RoleAuthorization(roles ...string) gin.HandlerFunc {
-	return func(c *gin.Context) {
-		role := c.GetString("role")
-		if role == "" {
-			utils.Forbidden(c, "Forbidden", nil)
+		if !allowed {
+			utils.Forbidden(c, "Forbidden", fmt.Errorf("requires %s", strings.Join(permissions, ", ")))
 			c.Abort()
 			return
 		}
 
-		if slices.Contains(roles, role) {
-			c.Next()
+		// Staff have to sign in again with a code after enrolling.
+		if m.cfg.Auth.RequireMFAForAdmin && !slices.Contains(c.GetStringSlice("auth_methods"), utils.AuthMethodOTP) {
+			utils.Forbidden(c, "two-factor authentication required", nil)
+			c.Abort()
 			return
 		}
 
-		utils.Forbidden(c, "Forbidden", nil)
-		c.Abort()
+		c.Next()
 	}
 }
@@ -4,16 +4,25 @@ import (
 	"net/http"
 
 	"github.com/anzhy11/go-e-commerce/internal/config"
+	roleService "github.com/anzhy11/go-e-commerce/internal/services/roles"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
 )
 
 type Middlewares struct {
-	cfg *config.Config
+	cfg      *config.Config
+	sessions sessionService.SessionServiceInterface
+	roles    roleService.RoleServiceInterface
+	tokens   *utils.TokenManager
 }
 
-func New(cfg *config.Config) *Middlewares {
+func New(cfg *config.Config, tokens *utils.TokenManager, sessions sessionService.SessionServiceInterface, roles roleService.RoleServiceInterface) *Middlewares {
 	return &Middlewares{
-		cfg: cfg,
+		cfg:      cfg,
+		tokens:   tokens,
+		sessions: sessions,
+		roles:    roles,
 	}
 }
 
@@ -2,8 +2,11 @@ package authRoutes
 
 import (
 	"github.com/anzhy11/go-e-commerce/internal/config"
-	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/models"
 	authHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/auth"
+	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/gin-gonic/gin"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
@@ -13,8 +16,8 @@ type authRoutes struct {
 	ah authHandler.AuthHandlerInterface
 }
 
-func Setup(apiGroup *gin.RouterGroup, db *gorm.DB, cfg *config.Config, log *zerolog.Logger, eventPub events.PublisherInterface) {
-	ah := authHandler.New(db, cfg, log, eventPub)
+func Setup(apiGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB, cfg *config.Config, log *zerolog.Logger, tokens *utils.TokenManager, sessions sessionService.SessionServiceInterface) {
+	ah := authHandler.New(db, cfg, log, tokens, sessions)
 
 	ar := &authRoutes{
 		ah: ah,
@@ -25,4 +28,27 @@ func Setup(apiGroup *gin.RouterGroup, db *gorm.DB, cfg *config.Config, log *zero
 	arg.POST("/login", ar.ah.Login)
 	arg.POST("/refresh-token", ar.ah.RefreshToken)
 	arg.POST("/logout", ar.ah.Logout)
+	arg.GET("/verify-email", ar.ah.VerifyEmailLink)
+	arg.POST("/verify-email", ar.ah.VerifyEmail)
+	arg.POST("/verify-email/resend", mdw.Authorization(), ar.ah.ResendVerificationEmail)
+	arg.POST("/forgot-password", ar.ah.ForgotPassword)
+	arg.POST("/reset-password", ar.ah.ResetPassword)
+	arg.POST("/change-password", mdw.Authorization(), ar.ah.ChangePassword)
+
+	arg.POST("/mfa/verify", ar.ah.VerifyMFA)
+	arg.GET("/mfa", mdw.Authorization(), ar.ah.GetMFAStatus)
+	arg.POST("/mfa/enroll", mdw.Authorization(), ar.ah.EnrollMFA)
+	arg.POST("/mfa/confirm", mdw.Authorization(), ar.ah.ConfirmMFA)
+	arg.POST("/mfa/disable", mdw.Authorization(), ar.ah.DisableMFA)
+	arg.POST("/mfa/recovery-codes", mdw.Authorization(), ar.ah.RegenerateRecoveryCodes)
+
+	arg.GET("/oidc/providers", ar.ah.ListOIDCProviders)
+	arg.GET("/oidc/:provider/login", ar.ah.StartOIDCLogin)
+	arg.GET("/oidc/:provider/callback", ar.ah.OIDCCallback)
+
+	adg := apiGroup.Group("/admin/users")
+	adg.Use(mdw.Authorization())
+	adg.Use(mdw.RequirePermission(models.PermissionUsersWrite))
+	adg.POST("/:id/unlock", ar.ah.UnlockAccount)
+	adg.POST("/:id/password-reset", ar.ah.ForcePasswordReset)
 }
@@ -1,8 +1,11 @@
 package orderRoutes
 
 import (
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/models"
 	orderHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/orders"
 	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
@@ -13,11 +16,11 @@ type orderRoutes struct {
 	orderHandler orderHandler.OrderHandlerInterface
 }
 
-func New(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB) *orderRoutes {
+func New(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB, cfg *config.Config, products productService.ProductServiceInterface) *orderRoutes {
 	return &orderRoutes{
 		routeGroup:   routeGroup,
 		mdw:          mdw,
-		orderHandler: orderHandler.New(db),
+		orderHandler: orderHandler.New(db, cfg, products),
 	}
 }
 
@@ -27,4 +30,6 @@ func (o *orderRoutes) SetupRoutes() {
 	orderGroup.POST("/", o.orderHandler.CreateOrder)
 	orderGroup.GET("/", o.orderHandler.GetOrders)
 	orderGroup.GET("/:id", o.orderHandler.GetOrder)
+	orderGroup.POST("/:id/cancel", o.orderHandler.CancelOrder)
+	orderGroup.PUT("/:id/status", o.mdw.RequirePermission(models.PermissionOrdersWrite), o.orderHandler.UpdateOrderStatus)
 }
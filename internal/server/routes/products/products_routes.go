@@ -2,20 +2,24 @@ package productRoutes
 
 import (
 	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 
 	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	"github.com/anzhy11/go-e-commerce/internal/models"
 	productHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/products"
 	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
+	importService "github.com/anzhy11/go-e-commerce/internal/services/imports"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
 )
 
 type productRoutes struct {
 	pd productHandler.ProductHandlerInterface
 }
 
-func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB, cfg *config.Config, up interfaces.Upload) {
-	pd := productHandler.New(db, cfg, up)
+func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB, cfg *config.Config, log *zerolog.Logger, up interfaces.Upload, imports *importService.Worker, products productService.ProductServiceInterface) {
+	pd := productHandler.New(db, cfg, log, up, imports, products)
 
 	pr := &productRoutes{
 		pd: pd,
@@ -26,18 +30,34 @@ func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.D
 	// Public routes
 	prg.GET("/", pr.pd.GetProducts)
 	prg.GET("/categories", pr.pd.GetCategories)
+	prg.GET("/categories/:id/attributes", pr.pd.GetCategoryAttributes)
 	prg.GET("/:id", pr.pd.GetProductById)
+	prg.GET("/:id/images", pr.pd.GetProductImages)
 
 	// Protected routes
 	prg.Use(mdw.Authorization())
-	prg.Use(mdw.AdminAuthorization())
+	prg.Use(mdw.RequirePermission(models.PermissionCatalogWrite))
 
 	prg.POST("/", pr.pd.CreateProduct)
 	prg.PUT("/:id", pr.pd.UpdateProduct)
 	prg.DELETE("/:id", pr.pd.DeleteProduct)
 	prg.POST("/:id/upload", pr.pd.UploadProductImage)
+	prg.POST("/:id/images/upload-url", pr.pd.RequestProductImageUpload)
+	prg.POST("/:id/images/confirm", pr.pd.ConfirmProductImageUpload)
+	prg.PUT("/:id/images/order", pr.pd.ReorderProductImages)
+	prg.PUT("/:id/images/:imageId", pr.pd.UpdateProductImage)
+	prg.PUT("/:id/images/:imageId/primary", pr.pd.SetPrimaryProductImage)
+	prg.DELETE("/:id/images/:imageId", pr.pd.DeleteProductImage)
+
+	prg.POST("/import", pr.pd.ImportProducts)
+	prg.GET("/import/:jobId", pr.pd.GetImportJob)
+	prg.GET("/export", pr.pd.ExportProducts)
 
 	prg.POST("/categories", pr.pd.CreateCategory)
 	prg.PUT("/categories/:id", pr.pd.UpdateCategory)
 	prg.DELETE("/categories/:id", pr.pd.DeleteCategory)
+
+	prg.POST("/categories/:id/attributes", pr.pd.CreateCategoryAttribute)
+	prg.PUT("/categories/:id/attributes/:attributeId", pr.pd.UpdateCategoryAttribute)
+	prg.DELETE("/categories/:id/attributes/:attributeId", pr.pd.DeleteCategoryAttribute)
 }
@@ -0,0 +1,38 @@
+package reviewRoutes
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	reviewHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/reviews"
+	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type reviewRoutes struct {
+	reviewHandler reviewHandler.ReviewHandlerInterface
+}
+
+func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB) {
+	rr := &reviewRoutes{
+		reviewHandler: reviewHandler.New(db),
+	}
+
+	rrg := routeGroup.Group("/products/:id/reviews")
+
+	// Public routes
+	rrg.GET("/", rr.reviewHandler.GetProductReviews)
+
+	// Protected routes
+	rrg.Use(mdw.Authorization())
+	rrg.GET("/me", rr.reviewHandler.GetMyReview)
+	rrg.POST("/", rr.reviewHandler.CreateReview)
+	rrg.PUT("/:reviewId", rr.reviewHandler.UpdateReview)
+	rrg.DELETE("/:reviewId", rr.reviewHandler.DeleteReview)
+
+	// Moderation
+	arg := routeGroup.Group("/admin/reviews")
+	arg.Use(mdw.Authorization())
+	arg.Use(mdw.RequirePermission(models.PermissionReviewsModerate))
+	arg.GET("/", rr.reviewHandler.GetModerationQueue)
+	arg.PUT("/:id/moderate", rr.reviewHandler.ModerateReview)
+}
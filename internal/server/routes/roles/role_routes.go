@@ -0,0 +1,33 @@
+package roleRoutes
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	roleHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/roles"
+	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
+	roleService "github.com/anzhy11/go-e-commerce/internal/services/roles"
+	"github.com/gin-gonic/gin"
+)
+
+type roleRoutes struct {
+	roleHandler roleHandler.RoleHandlerInterface
+}
+
+func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, roles roleService.RoleServiceInterface) {
+	rr := &roleRoutes{
+		roleHandler: roleHandler.New(roles),
+	}
+
+	arg := routeGroup.Group("/admin")
+	arg.Use(mdw.Authorization())
+	arg.Use(mdw.RequirePermission(models.PermissionRolesManage))
+
+	arg.GET("/permissions", rr.roleHandler.GetPermissions)
+
+	arg.GET("/roles", rr.roleHandler.GetRoles)
+	arg.POST("/roles", rr.roleHandler.CreateRole)
+	arg.GET("/roles/:name", rr.roleHandler.GetRole)
+	arg.PUT("/roles/:name", rr.roleHandler.UpdateRole)
+	arg.DELETE("/roles/:name", rr.roleHandler.DeleteRole)
+
+	arg.PUT("/users/:id/role", rr.roleHandler.AssignUserRole)
+}
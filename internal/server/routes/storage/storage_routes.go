@@ -0,0 +1,30 @@
+package storageRoutes
+
+import (
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	"github.com/anzhy11/go-e-commerce/internal/providers"
+	storageHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/storage"
+	"github.com/gin-gonic/gin"
+)
+
+type storageRoutes struct {
+	storageHandler storageHandler.StorageHandlerInterface
+}
+
+// Setup registers the presigned URL endpoints. They only exist for the local
+// provider; with S3 the client talks to the bucket directly.
+func Setup(routeGroup *gin.RouterGroup, cfg *config.Config, up interfaces.Upload) {
+	local, ok := up.(*providers.LocalUploadProvider)
+	if !ok {
+		return
+	}
+
+	sr := &storageRoutes{
+		storageHandler: storageHandler.New(cfg, local),
+	}
+
+	srg := routeGroup.Group("/storage")
+	srg.PUT("/*path", sr.storageHandler.UploadObject)
+	srg.GET("/*path", sr.storageHandler.DownloadObject)
+}
@@ -1,23 +1,51 @@
 package userRoutes
 
 import (
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/models"
 	userHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/users"
+	wishlistHandler "github.com/anzhy11/go-e-commerce/internal/server/handlers/wishlist"
 	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
 	"github.com/gin-gonic/gin"
 	"gorm.io/gorm"
 )
 
 type userRoutes struct {
-	userHandler userHandler.UserHandlerInterface
+	userHandler     userHandler.UserHandlerInterface
+	wishlistHandler wishlistHandler.WishlistHandlerInterface
 }
 
-func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB) {
+func Setup(routeGroup *gin.RouterGroup, mdw *middlewares.Middlewares, db *gorm.DB, cfg *config.Config, sessions sessionService.SessionServiceInterface, products productService.ProductServiceInterface) {
 	ur := &userRoutes{
-		userHandler: userHandler.New(db),
+		userHandler:     userHandler.New(db, cfg, sessions, products),
+		wishlistHandler: wishlistHandler.New(db, cfg),
 	}
 
 	urg := routeGroup.Group("/users")
+	urg.GET("/wishlist/unsubscribe", ur.wishlistHandler.Unsubscribe)
+
 	urg.Use(mdw.Authorization())
 	urg.GET("/profile", ur.userHandler.GetProfile)
 	urg.PUT("/profile", ur.userHandler.UpdateProfile)
+
+	urg.GET("/me/sessions", ur.userHandler.GetSessions)
+	urg.DELETE("/me/sessions", ur.userHandler.RevokeOtherSessions)
+	urg.DELETE("/me/sessions/:id", ur.userHandler.RevokeSession)
+
+	urg.GET("/wishlist", ur.wishlistHandler.GetWishlist)
+	urg.POST("/wishlist", ur.wishlistHandler.AddToWishlist)
+	urg.PUT("/wishlist/:productId", ur.wishlistHandler.UpdateWishlistItem)
+	urg.DELETE("/wishlist/:productId", ur.wishlistHandler.RemoveFromWishlist)
+
+	aug := routeGroup.Group("/admin")
+	aug.Use(mdw.Authorization())
+	aug.GET("/users", mdw.RequirePermission(models.PermissionUsersRead), ur.userHandler.GetUsers)
+	aug.GET("/users/:id", mdw.RequirePermission(models.PermissionUsersRead), ur.userHandler.GetUser)
+	aug.GET("/users/:id/orders", mdw.RequirePermission(models.PermissionUsersRead), ur.userHandler.GetUserOrders)
+	aug.GET("/users/:id/sessions", mdw.RequirePermission(models.PermissionUsersRead), ur.userHandler.GetUserSessions)
+	aug.GET("/audit-logs", mdw.RequirePermission(models.PermissionUsersRead), ur.userHandler.GetAuditLogs)
+	aug.POST("/users/:id/deactivate", mdw.RequirePermission(models.PermissionUsersWrite), ur.userHandler.DeactivateUser)
+	aug.POST("/users/:id/reactivate", mdw.RequirePermission(models.PermissionUsersWrite), ur.userHandler.ReactivateUser)
 }
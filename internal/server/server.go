@@ -1,10 +1,10 @@
 package server
 
 import (
+	"fmt"
 	"net/http"
 
 	"github.com/anzhy11/go-e-commerce/internal/config"
-	"github.com/anzhy11/go-e-commerce/internal/events"
 	"github.com/anzhy11/go-e-commerce/internal/interfaces"
 	"github.com/anzhy11/go-e-commerce/internal/server/middlewares"
 	authRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/auth"
@@ -12,7 +12,15 @@ import (
 
 	orderRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/orders"
 	productRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/products"
+	reviewRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/reviews"
+	roleRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/roles"
+	storageRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/storage"
 	userRoutes "github.com/anzhy11/go-e-commerce/internal/server/routes/users"
+	importService "github.com/anzhy11/go-e-commerce/internal/services/imports"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
+	roleService "github.com/anzhy11/go-e-commerce/internal/services/roles"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
 
 	_ "github.com/anzhy11/go-e-commerce/docs"
 	"github.com/gin-gonic/gin"
@@ -27,50 +35,82 @@ type Server struct {
 	db       *gorm.DB
 	log      *zerolog.Logger
 	mdw      *middlewares.Middlewares
-	eventPub events.PublisherInterface
 	up       interfaces.Upload
+	tokens   *utils.TokenManager
+	imports  *importService.Worker
+	sessions sessionService.SessionServiceInterface
+	roles    roleService.RoleServiceInterface
+	products productService.ProductServiceInterface
 }
 
-func New(cfg *config.Config, db *gorm.DB, log *zerolog.Logger, eventPub events.PublisherInterface, up interfaces.Upload) *Server {
+func New(cfg *config.Config, db *gorm.DB, log *zerolog.Logger, up interfaces.Upload, tokens *utils.TokenManager, imports *importService.Worker) *Server {
+	// One session service and one role service serve the middleware and the
+	// handlers, so a revocation or a role change clears the cache the
+	// middleware answers from.
+	sessions := sessionService.New(db, cfg)
+	roles := roleService.New(db, cfg, sessions)
+
 	return &Server{
 		cfg:      cfg,
 		db:       db,
 		log:      log,
-		mdw:      middlewares.New(cfg),
-		eventPub: eventPub,
+		mdw:      middlewares.New(cfg, tokens, sessions, roles),
 		up:       up,
+		tokens:   tokens,
+		imports:  imports,
+		sessions: sessions,
+		roles:    roles,
+		products: productService.New(db, cfg, log),
 	}
 }
 
-func (s *Server) SetupRoutes() *gin.Engine {
+func (s *Server) SetupRoutes() (*gin.Engine, error) {
 	router := gin.New()
 
+	// Client IPs feed login throttling and the session list, so
+	// X-Forwarded-For is only believed from the configured proxies.
+	if err := router.SetTrustedProxies(s.cfg.Server.TrustedProxies); err != nil {
+		return nil, fmt.Errorf("invalid TRUSTED_PROXIES: %w", err)
+	}
+
 	router.Use(gin.Logger())
 	router.Use(gin.Recovery())
 	router.Use(s.mdw.CorsMiddleware())
 
 	router.GET("/health", healthCheckHandler)
+	router.GET("/.well-known/jwks.json", s.jwksHandler)
 
 	// Swagger documentation
 	router.GET("/docs/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 	router.StaticFile("/api-docs", "./docs/rapidoc.html")
 
 	// Uploads
-	router.Static("/uploads", "./uploads")
+	router.Static("/uploads", s.cfg.Upload.Path)
 
 	apiGroup := router.Group("/api/v1")
 
-	authRoutes.Setup(apiGroup, s.db, s.cfg, s.log, s.eventPub)
-	userRoutes.Setup(apiGroup, s.mdw, s.db)
-	productRoutes.Setup(apiGroup, s.mdw, s.db, s.cfg, s.up)
+	authRoutes.Setup(apiGroup, s.mdw, s.db, s.cfg, s.log, s.tokens, s.sessions)
+	userRoutes.Setup(apiGroup, s.mdw, s.db, s.cfg, s.sessions, s.products)
+	productRoutes.Setup(apiGroup, s.mdw, s.db, s.cfg, s.log, s.up, s.imports, s.products)
+	reviewRoutes.Setup(apiGroup, s.mdw, s.db)
+	roleRoutes.Setup(apiGroup, s.mdw, s.roles)
 	cartRoutes.Setup(apiGroup, s.mdw, s.db)
+	storageRoutes.Setup(apiGroup, s.cfg, s.up)
 
-	orderService := orderRoutes.New(apiGroup, s.mdw, s.db)
+	orderService := orderRoutes.New(apiGroup, s.mdw, s.db, s.cfg, s.products)
 	orderService.SetupRoutes()
 
-	return router
+	return router, nil
 }
 
 func healthCheckHandler(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+// jwksHandler publishes the public keys access tokens can be verified with.
+// Verifiers may cache it briefly; a new key is published before it signs
+// anything, so a stale copy only misses keys that are not in use yet.
+func (s *Server) jwksHandler(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=300")
+	c.JSON(http.StatusOK, s.tokens.JWKS())
+}
@@ -1,53 +1,126 @@
 package authService
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 
 	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/dto"
 	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/identity"
 	"github.com/anzhy11/go-e-commerce/internal/models"
-	"github.com/anzhy11/go-e-commerce/internal/notifications"
 	"github.com/anzhy11/go-e-commerce/internal/repository"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"github.com/anzhy11/go-e-commerce/pkg/encryption"
 	"github.com/rs/zerolog"
 )
 
 type AuthServiceInterface interface {
-	Register(data *dto.RegisterRequest) (*dto.AuthResponse, error)
-	Login(data *dto.LoginRequest) (*dto.AuthResponse, error)
-	RefreshToken(data *dto.RefreshTokenRequest) (*dto.AuthResponse, error)
+	Register(data *dto.RegisterRequest, client *dto.ClientInfo) (*dto.AuthResponse, error)
+	Login(data *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error)
+	RefreshToken(data *dto.RefreshTokenRequest, client *dto.ClientInfo) (*dto.AuthResponse, error)
 	Logout(rt string) error
+	VerifyEmail(token string) (*dto.UserResponse, error)
+	ResendVerificationEmail(userID uint) error
+	ForgotPassword(data *dto.ForgotPasswordRequest) error
+	ResetPassword(data *dto.ResetPasswordRequest) error
+	ChangePassword(userID uint, data *dto.ChangePasswordRequest) error
+	GetMFAStatus(userID uint) (*dto.MFAStatusResponse, error)
+	EnrollMFA(userID uint) (*dto.MFAEnrollmentResponse, error)
+	ConfirmMFA(userID uint, code string) (*dto.MFARecoveryCodesResponse, error)
+	DisableMFA(userID uint, data *dto.DisableMFARequest) error
+	RegenerateRecoveryCodes(userID uint, code string) (*dto.MFARecoveryCodesResponse, error)
+	VerifyMFA(data *dto.MFAVerifyRequest, client *dto.ClientInfo) (*dto.AuthResponse, error)
+	ListOIDCProviders() *dto.OIDCProvidersResponse
+	StartOIDCLogin(ctx context.Context, providerName string) (*dto.OIDCLogin, error)
+	CompleteOIDCLogin(ctx context.Context, providerName string, data *dto.OIDCCallbackRequest, client *dto.ClientInfo) (*dto.LoginResponse, error)
+	UnlockAccount(actorID, userID uint) error
+	ForcePasswordReset(actorID, userID uint) error
+	PurgeExpiredTokens(before time.Time) (*PurgeResult, error)
 }
 
+// PurgeResult counts the rows PurgeExpiredTokens deleted.
+type PurgeResult struct {
+	Sessions        int64
+	RefreshTokens   int64
+	UserTokens      int64
+	LoginThrottles  int64
+	OIDCLoginStates int64
+}
+
+var (
+	ErrUserNotFound            = errors.New("user not found")
+	ErrInvalidToken            = errors.New("invalid or expired token")
+	ErrEmailAlreadyVerified    = errors.New("email already verified")
+	ErrInvalidPassword         = errors.New("invalid password")
+	ErrInvalidCredentials      = errors.New("invalid email or password")
+	ErrAccountDeactivated      = errors.New("account is deactivated")
+	ErrTooManyLoginAttempts    = errors.New("too many failed login attempts")
+	ErrMFAAlreadyEnabled       = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled           = errors.New("two-factor authentication is not enabled")
+	ErrMFANotEnrolled          = errors.New("no authenticator enrolled")
+	ErrMFARequired             = errors.New("two-factor authentication is required for this account")
+	ErrInvalidMFACode          = errors.New("invalid authentication code")
+	ErrMFAUnavailable          = errors.New("two-factor authentication is not configured")
+	ErrInvalidRefreshToken     = errors.New("invalid refresh token")
+	ErrRefreshTokenReused      = errors.New("refresh token reuse detected, session revoked")
+	ErrInvalidOIDCState        = errors.New("invalid or expired login state")
+	ErrOIDCLoginDenied         = errors.New("sign-in refused by the identity provider")
+	ErrIdentityEmailUnverified = errors.New("the identity provider has not verified the email")
+	ErrAccountEmailUnverified  = errors.New("an account with this email exists but its email is not verified, sign in with the password and verify it first")
+)
+
 type authService struct {
-	db       *gorm.DB
-	log      *zerolog.Logger
-	cfg      *config.Config
-	userRepo repository.UserRepositoryInterface
-	cartRepo repository.CartRepositoryInterface
-	authRepo repository.AuthRepositoryInterface
-	eventPub events.PublisherInterface
+	db                *gorm.DB
+	log               *zerolog.Logger
+	cfg               *config.Config
+	userRepo          repository.UserRepositoryInterface
+	cartRepo          repository.CartRepositoryInterface
+	authRepo          repository.AuthRepositoryInterface
+	sessionRepo       repository.SessionRepositoryInterface
+	userTokenRepo     repository.UserTokenRepositoryInterface
+	outboxRepo        repository.OutboxRepositoryInterface
+	loginThrottleRepo repository.LoginThrottleRepositoryInterface
+	mfaRepo           repository.MFARepositoryInterface
+	roleRepo          repository.RoleRepositoryInterface
+	identityRepo      repository.IdentityRepositoryInterface
+	auditRepo         repository.AuditRepositoryInterface
+	identityProviders *identity.Registry
+	tokens            *utils.TokenManager
+	sessions          sessionService.SessionServiceInterface
 }
 
-func New(db *gorm.DB, cfg *config.Config, log *zerolog.Logger, eventPub events.PublisherInterface) AuthServiceInterface {
+func New(db *gorm.DB, cfg *config.Config, log *zerolog.Logger, tokens *utils.TokenManager, sessions sessionService.SessionServiceInterface) AuthServiceInterface {
 	return &authService{
-		db:       db,
-		cfg:      cfg,
-		log:      log,
-		eventPub: eventPub,
-		userRepo: repository.NewUserRepo(db),
-		cartRepo: repository.NewCartRepo(db),
-		authRepo: repository.NewAuthRepo(db),
+		db:                db,
+		cfg:               cfg,
+		log:               log,
+		tokens:            tokens,
+		sessions:          sessions,
+		userRepo:          repository.NewUserRepo(db),
+		cartRepo:          repository.NewCartRepo(db),
+		authRepo:          repository.NewAuthRepo(db),
+		sessionRepo:       repository.NewSessionRepo(db),
+		userTokenRepo:     repository.NewUserTokenRepo(db),
+		outboxRepo:        repository.NewOutboxRepo(db),
+		loginThrottleRepo: repository.NewLoginThrottleRepo(db),
+		mfaRepo:           repository.NewMFARepo(db),
+		roleRepo:          repository.NewRoleRepo(db),
+		identityRepo:      repository.NewIdentityRepo(db),
+		auditRepo:         repository.NewAuditRepo(db),
+		identityProviders: newIdentityRegistry(&cfg.OIDC),
 	}
 }
 
-func (s *authService) Register(data *dto.RegisterRequest) (*dto.AuthResponse, error) {
+func (s *authService) Register(data *dto.RegisterRequest, client *dto.ClientInfo) (*dto.AuthResponse, error) {
 	existingUser, err := s.userRepo.GetUserByEmail(data.Email)
 	if err != nil {
 		return nil, err
@@ -69,57 +142,117 @@ func (s *authService) Register(data *dto.RegisterRequest) (*dto.AuthResponse, er
 		Password:  hashedPassword,
 		Phone:     data.Phone,
 		Role:      string(models.RoleCustomer),
+		Locale:    data.Locale,
+	}
+	if user.Locale == "" {
+		user.Locale = models.DefaultLocale
 	}
 
-	if err := s.userRepo.CreateUser(&user); err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.CreateUserTx(&user, tx); err != nil {
+			return err
+		}
+
+		if err := s.cartRepo.CreateCartTx(&models.Cart{UserID: user.ID}, tx); err != nil {
+			return err
+		}
+
+		token, expiresAt, err := s.createUserTokenTx(user.ID, models.UserTokenEmailVerification, s.cfg.Auth.EmailVerificationTokenTTL, tx)
+		if err != nil {
+			return err
+		}
+
+		registered, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.UserRegistered{
+			UserID:                user.ID,
+			Email:                 user.Email,
+			FirstName:             user.FirstName,
+			LastName:              user.LastName,
+			Locale:                user.Locale,
+			VerificationURL:       s.verificationURL(token),
+			VerificationExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*registered}, tx)
+	})
+	if err != nil {
 		return nil, err
 	}
 
-	cart := models.Cart{
-		UserID: user.ID,
-	}
+	return s.generateAuthResponse(&user, client, []string{utils.AuthMethodPassword})
+}
 
-	if err := s.cartRepo.CreateCart(&cart); err != nil {
-		s.log.Error().Err(err).Msg("Failed to create cart")
+// Login fails with ErrInvalidCredentials whether the email or the password
+// is wrong, and with a *LoginThrottledError once the account or the client
+// failed too often. Deactivated accounts fail with ErrAccountDeactivated,
+// only once the password is right. Accounts with two-factor authentication
+// get an MFA challenge instead of tokens.
+func (s *authService) Login(data *dto.LoginRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	now := time.Now()
+	if err := s.checkLoginThrottles(data.Email, client, now); err != nil {
+		return nil, err
 	}
 
-	return s.generateAuthResponse(&user)
-}
-
-func (s *authService) Login(data *dto.LoginRequest) (*dto.AuthResponse, error) {
 	user, err := s.userRepo.GetUserByEmail(data.Email)
 	if err != nil {
 		return nil, err
 	}
 
+	passwordHash := user.Password
 	if user.ID == 0 {
-		return nil, errors.New("user not found")
+		passwordHash = dummyPasswordHash()
+	}
+
+	if !encryption.CheckPassword(data.Password, passwordHash) || user.ID == 0 {
+		if err := s.recordFailedLogin(data.Email, user, client, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
 	}
 
-	if !encryption.CheckPassword(data.Password, user.Password) {
-		return nil, errors.New("invalid password")
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
 	}
 
-	return s.generateAuthResponse(user)
+	// The failures are kept until the code is checked too, or alternating
+	// right passwords and wrong codes would never be throttled.
+	if user.MFAEnabledAt != nil {
+		return s.mfaChallenge(user, utils.AuthMethodPassword)
+	}
+
+	s.clearFailedLogins(data.Email)
+	resp, err := s.generateAuthResponse(user, client, []string{utils.AuthMethodPassword})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{AuthResponse: resp}, nil
 }
 
-func (s *authService) RefreshToken(data *dto.RefreshTokenRequest) (*dto.AuthResponse, error) {
-	payload, err := utils.VerifyToken(data.RefreshToken, s.cfg.JWT.Secret)
+// RefreshToken exchanges a refresh token for a new pair. The old token stays
+// on record as rotated: presenting it again means it was copied, so the
+// whole session is revoked and the user warned.
+func (s *authService) RefreshToken(data *dto.RefreshTokenRequest, client *dto.ClientInfo) (*dto.AuthResponse, error) {
+	payload, err := s.tokens.VerifyRefreshToken(data.RefreshToken)
 	if err != nil {
-		return nil, errors.New("invalid refresh token")
+		return nil, ErrInvalidRefreshToken
 	}
 
-	refreshToken, err := s.authRepo.GetRefreshToken(data.RefreshToken)
+	refreshToken, err := s.authRepo.GetRefreshTokenByHash(encryption.HashToken(data.RefreshToken))
 	if err != nil {
 		return nil, err
 	}
 
-	if refreshToken.UserID != payload.UserID {
-		return nil, errors.New("invalid refresh token")
+	if refreshToken.ID == 0 || refreshToken.UserID != payload.UserID {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if refreshToken.RotatedAt != nil {
+		return nil, s.revokeReusedSession(refreshToken)
 	}
 
 	if refreshToken.ExpiresAt.Before(time.Now()) {
-		return nil, errors.New("refresh token expired")
+		return nil, ErrInvalidRefreshToken
 	}
 
 	user, err := s.userRepo.GetUserById(payload.UserID)
@@ -128,50 +261,552 @@ func (s *authService) RefreshToken(data *dto.RefreshTokenRequest) (*dto.AuthResp
 	}
 
 	if user.ID == 0 {
-		return nil, errors.New("user not found")
+		return nil, ErrUserNotFound
 	}
 
-	s.authRepo.DeleteRefreshToken(refreshToken)
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	var resp *dto.AuthResponse
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		rotated, err := s.authRepo.RotateRefreshTokenTx(refreshToken, tx)
+		if err != nil {
+			return err
+		}
+		if !rotated {
+			return ErrRefreshTokenReused
+		}
+
+		session := newSession(refreshToken.FamilyID, user.ID, client, s.cfg.JWT.RefreshTokenExpiresIn)
+		if err := s.sessionRepo.RefreshSessionTx(session, tx); err != nil {
+			return err
+		}
+
+		resp, err = s.issueTokensTx(user, session.ID, payload.AuthMethods, tx)
+		return err
+	})
+	if errors.Is(err, ErrRefreshTokenReused) {
+		return nil, s.revokeReusedSession(refreshToken)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	return s.generateAuthResponse(user)
+	return resp, nil
 }
 
+// Logout ends the session the refresh token belongs to.
 func (s *authService) Logout(rt string) error {
-	return s.db.Where("token = ?", rt).Delete(&models.RefreshToken{}).Error
+	refreshToken, err := s.authRepo.GetRefreshTokenByHash(encryption.HashToken(rt))
+	if err != nil {
+		return err
+	}
+
+	if refreshToken.ID == 0 {
+		return nil
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.sessionRepo.RevokeSessionTx(refreshToken.FamilyID, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sessions.Forget(refreshToken.FamilyID)
+	return nil
+}
+
+// PurgeExpiredTokens permanently deletes sessions, refresh tokens,
+// single-use tokens and OIDC login states that expired before the given
+// time, and login throttles with nothing left to enforce by then.
+func (s *authService) PurgeExpiredTokens(before time.Time) (*PurgeResult, error) {
+	result := &PurgeResult{}
+
+	sessions, err := s.sessionRepo.DeleteExpiredSessions(before)
+	if err != nil {
+		return result, err
+	}
+	result.Sessions = sessions
+
+	refreshTokens, err := s.authRepo.DeleteExpiredRefreshTokens(before)
+	if err != nil {
+		return result, err
+	}
+	result.RefreshTokens = refreshTokens
+
+	userTokens, err := s.userTokenRepo.DeleteExpiredUserTokens(before)
+	if err != nil {
+		return result, err
+	}
+	result.UserTokens = userTokens
+
+	loginThrottles, err := s.loginThrottleRepo.DeleteStaleLoginThrottles(before)
+	if err != nil {
+		return result, err
+	}
+	result.LoginThrottles = loginThrottles
+
+	oidcLoginStates, err := s.identityRepo.DeleteExpiredOIDCLoginStates(before)
+	if err != nil {
+		return result, err
+	}
+	result.OIDCLoginStates = oidcLoginStates
+
+	return result, nil
+}
+
+// VerifyEmail redeems an email verification token.
+func (s *authService) VerifyEmail(token string) (*dto.UserResponse, error) {
+	userToken, err := s.findUserToken(models.UserTokenEmailVerification, token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userRepo.GetUserById(userToken.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == 0 {
+		return nil, ErrInvalidToken
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		used, err := s.userTokenRepo.UseUserTokenTx(userToken, tx)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidToken
+		}
+		return s.userRepo.MarkEmailVerifiedTx(user, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp := s.userResponse(user)
+	return &resp, nil
+}
+
+// ResendVerificationEmail sends a new verification link. Links sent before
+// stop working.
+func (s *authService) ResendVerificationEmail(userID uint) error {
+	user, err := s.userRepo.GetUserById(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == 0 {
+		return ErrUserNotFound
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return ErrEmailAlreadyVerified
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userTokenRepo.RevokeUserTokensTx(user.ID, models.UserTokenEmailVerification, tx); err != nil {
+			return err
+		}
+
+		token, expiresAt, err := s.createUserTokenTx(user.ID, models.UserTokenEmailVerification, s.cfg.Auth.EmailVerificationTokenTTL, tx)
+		if err != nil {
+			return err
+		}
+
+		requested, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.EmailVerificationRequested{
+			UserID:                user.ID,
+			Email:                 user.Email,
+			FirstName:             user.FirstName,
+			LastName:              user.LastName,
+			Locale:                user.Locale,
+			VerificationURL:       s.verificationURL(token),
+			VerificationExpiresAt: expiresAt,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*requested}, tx)
+	})
+}
+
+// ForgotPassword emails a password reset link. It succeeds whether or not
+// the email belongs to an active account, so it cannot be used to find
+// accounts.
+func (s *authService) ForgotPassword(data *dto.ForgotPasswordRequest) error {
+	user, err := s.userRepo.GetUserByEmail(data.Email)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == 0 || !user.IsActive {
+		return nil
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.sendPasswordResetTx(user, tx)
+	})
+}
+
+// ForcePasswordReset makes a user choose a new password: the current one
+// stops working, every session is signed out and a reset link is emailed.
+func (s *authService) ForcePasswordReset(actorID, userID uint) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		user.Password = ""
+		if err := s.userRepo.UpdatePasswordTx(user, tx); err != nil {
+			return err
+		}
+
+		revoked, err := s.sessionRepo.RevokeUserSessionsTx(user.ID, "", tx)
+		if err != nil {
+			return err
+		}
+
+		if err := s.sendPasswordResetTx(user, tx); err != nil {
+			return err
+		}
+
+		return s.auditRepo.CreateAuditLogTx(&models.AuditLog{
+			ActorID: actorID,
+			UserID:  user.ID,
+			Action:  models.AuditUserPasswordResetForced,
+			Details: models.JSONMap{"sessions_revoked": revoked},
+		}, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sessions.ForgetUser(user.ID)
+	return nil
+}
+
+// ResetPassword sets a new password with a token from a reset email.
+func (s *authService) ResetPassword(data *dto.ResetPasswordRequest) error {
+	userToken, err := s.findUserToken(models.UserTokenPasswordReset, data.Token)
+	if err != nil {
+		return err
+	}
+
+	user, err := s.userRepo.GetUserById(userToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == 0 {
+		return ErrInvalidToken
+	}
+
+	hashedPassword, err := encryption.HashPassword(data.Password)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		used, err := s.userTokenRepo.UseUserTokenTx(userToken, tx)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidToken
+		}
+
+		if err := s.userTokenRepo.RevokeUserTokensTx(user.ID, models.UserTokenPasswordReset, tx); err != nil {
+			return err
+		}
+
+		return s.setPasswordTx(user, hashedPassword, events.PasswordChangeMethodReset, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sessions.ForgetUser(user.ID)
+	return nil
 }
 
-func (s *authService) generateAuthResponse(user *models.User) (*dto.AuthResponse, error) {
-	accessToken, refreshToken, err := utils.GenerateTokenPair(s.cfg, user.ID, user.Email, user.Role)
+// ChangePassword changes the password of a signed-in user who knows the
+// current one.
+func (s *authService) ChangePassword(userID uint, data *dto.ChangePasswordRequest) error {
+	user, err := s.userRepo.GetUserById(userID)
+	if err != nil {
+		return err
+	}
+
+	if user.ID == 0 {
+		return ErrUserNotFound
+	}
+
+	if !encryption.CheckPassword(data.CurrentPassword, user.Password) {
+		return ErrInvalidPassword
+	}
+
+	hashedPassword, err := encryption.HashPassword(data.NewPassword)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.setPasswordTx(user, hashedPassword, events.PasswordChangeMethodChange, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sessions.ForgetUser(user.ID)
+	return nil
+}
+
+// generateAuthResponse signs the user in on a new session. The session ID
+// is also the family of its refresh tokens. authMethods lists the factors
+// the user proved.
+func (s *authService) generateAuthResponse(user *models.User, client *dto.ClientInfo, authMethods []string) (*dto.AuthResponse, error) {
+	var resp *dto.AuthResponse
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		session := newSession(uuid.NewString(), user.ID, client, s.cfg.JWT.RefreshTokenExpiresIn)
+		if err := s.sessionRepo.CreateSessionTx(session, tx); err != nil {
+			return err
+		}
+
+		var err error
+		resp, err = s.issueTokensTx(user, session.ID, authMethods, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+func (s *authService) issueTokensTx(user *models.User, sessionID string, authMethods []string, tx *gorm.DB) (*dto.AuthResponse, error) {
+	accessToken, refreshToken, err := s.tokens.GenerateTokenPair(user.ID, user.Email, user.Role, sessionID, authMethods)
 	if err != nil {
 		return nil, err
 	}
 
 	refreshTokenModel := models.RefreshToken{
-		Token:     refreshToken,
+		TokenHash: encryption.HashToken(refreshToken),
+		FamilyID:  sessionID,
 		UserID:    user.ID,
 		ExpiresAt: time.Now().Add(s.cfg.JWT.RefreshTokenExpiresIn),
 	}
 
-	if rtErr := s.authRepo.CreateRefreshToken(&refreshTokenModel); rtErr != nil {
-		return nil, rtErr
+	loggedIn, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.UserLoggedIn{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Locale:    user.Locale,
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	err = s.eventPub.Publish(notifications.UserLoggedInEventType, user, map[string]string{})
-	if err != nil {
-		return nil, fmt.Errorf("failed to publish user logged in event: %w", err)
+	if err := s.authRepo.CreateRefreshTokenTx(&refreshTokenModel, tx); err != nil {
+		return nil, err
+	}
+	if err := s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*loggedIn}, tx); err != nil {
+		return nil, err
 	}
 
 	return &dto.AuthResponse{
-		User: dto.UserResponse{
-			ID:        user.ID,
-			FirstName: user.FirstName,
-			LastName:  user.LastName,
-			Phone:     user.Phone,
-			Email:     user.Email,
-			Role:      user.Role,
-			IsActive:  user.IsActive,
-		},
+		User:         s.userResponse(user),
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 	}, nil
 }
+
+// revokeReusedSession revokes the session of a reused refresh token and warns
+// the user. It returns ErrRefreshTokenReused unless revoking fails.
+func (s *authService) revokeReusedSession(refreshToken *models.RefreshToken) error {
+	s.log.Warn().
+		Uint("user_id", refreshToken.UserID).
+		Str("family_id", refreshToken.FamilyID).
+		Msg("Refresh token reused, revoking session")
+
+	user, err := s.userRepo.GetUserById(refreshToken.UserID)
+	if err != nil {
+		return err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.sessionRepo.RevokeSessionTx(refreshToken.FamilyID, tx); err != nil {
+			return err
+		}
+
+		if user.ID == 0 {
+			return nil
+		}
+
+		reused, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.RefreshTokenReused{
+			UserID:     user.ID,
+			Email:      user.Email,
+			FirstName:  user.FirstName,
+			LastName:   user.LastName,
+			Locale:     user.Locale,
+			FamilyID:   refreshToken.FamilyID,
+			DetectedAt: time.Now(),
+		})
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*reused}, tx)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.sessions.Forget(refreshToken.FamilyID)
+	return ErrRefreshTokenReused
+}
+
+func newSession(id string, userID uint, client *dto.ClientInfo, ttl time.Duration) *models.Session {
+	now := time.Now()
+	session := &models.Session{
+		ID:         id,
+		UserID:     userID,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(ttl),
+	}
+	if client != nil {
+		session.IPAddress = client.IPAddress
+		session.UserAgent = client.UserAgent
+	}
+	return session
+}
+
+func (s *authService) userResponse(user *models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:            user.ID,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Phone:         user.Phone,
+		Email:         user.Email,
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		Locale:        user.Locale,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    user.MFAEnabledAt != nil,
+	}
+}
+
+// sendPasswordResetTx replaces the user's password reset links with a new
+// one and emails it.
+func (s *authService) sendPasswordResetTx(user *models.User, tx *gorm.DB) error {
+	if err := s.userTokenRepo.RevokeUserTokensTx(user.ID, models.UserTokenPasswordReset, tx); err != nil {
+		return err
+	}
+
+	token, expiresAt, err := s.createUserTokenTx(user.ID, models.UserTokenPasswordReset, s.cfg.Auth.PasswordResetTokenTTL, tx)
+	if err != nil {
+		return err
+	}
+
+	resetURL, err := s.passwordResetURL(token)
+	if err != nil {
+		return err
+	}
+
+	requested, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.PasswordResetRequested{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Locale:    user.Locale,
+		ResetURL:  resetURL,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*requested}, tx)
+}
+
+// createUserTokenTx saves a new single-use token and returns it in clear
+// text for the email, along with its expiry.
+func (s *authService) createUserTokenTx(userID uint, purpose models.UserTokenPurpose, ttl time.Duration, tx *gorm.DB) (string, time.Time, error) {
+	return s.saveUserTokenTx(&models.UserToken{UserID: userID, Purpose: purpose}, ttl, tx)
+}
+
+// saveUserTokenTx generates the token of userToken and saves it.
+func (s *authService) saveUserTokenTx(userToken *models.UserToken, ttl time.Duration, tx *gorm.DB) (string, time.Time, error) {
+	token, err := encryption.GenerateRandomString(32)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	userToken.TokenHash = encryption.HashToken(token)
+	userToken.ExpiresAt = time.Now().Add(ttl)
+	if err := s.userTokenRepo.CreateUserTokenTx(userToken, tx); err != nil {
+		return "", time.Time{}, err
+	}
+
+	return token, userToken.ExpiresAt, nil
+}
+
+// findUserToken looks up a token that can still be redeemed.
+func (s *authService) findUserToken(purpose models.UserTokenPurpose, token string) (*models.UserToken, error) {
+	userToken, err := s.userTokenRepo.GetUserToken(purpose, encryption.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+
+	if userToken.ID == 0 || userToken.UsedAt != nil || userToken.ExpiresAt.Before(time.Now()) {
+		return nil, ErrInvalidToken
+	}
+
+	return userToken, nil
+}
+
+// setPasswordTx saves a new password hash, signs the user out of every
+// session and notifies them of the change. Callers forget the user's
+// sessions once the transaction commits.
+func (s *authService) setPasswordTx(user *models.User, hashedPassword, method string, tx *gorm.DB) error {
+	user.Password = hashedPassword
+	if err := s.userRepo.UpdatePasswordTx(user, tx); err != nil {
+		return err
+	}
+
+	if _, err := s.sessionRepo.RevokeUserSessionsTx(user.ID, "", tx); err != nil {
+		return err
+	}
+
+	changed, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.PasswordChanged{
+		UserID:    user.ID,
+		Email:     user.Email,
+		FirstName: user.FirstName,
+		LastName:  user.LastName,
+		Locale:    user.Locale,
+		Method:    method,
+		ChangedAt: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*changed}, tx)
+}
+
+func (s *authService) passwordResetURL(token string) (string, error) {
+	resetURL, err := url.Parse(s.cfg.Auth.PasswordResetURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid password reset url: %w", err)
+	}
+
+	query := resetURL.Query()
+	query.Set("token", token)
+	resetURL.RawQuery = query.Encode()
+	return resetURL.String(), nil
+}
+
+func (s *authService) verificationURL(token string) string {
+	return fmt.Sprintf("%s/api/v1/auth/verify-email?token=%s", strings.TrimRight(s.cfg.Server.AppURL, "/"), url.QueryEscape(token))
+}
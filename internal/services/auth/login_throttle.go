@@ -0,0 +1,190 @@
+package authService
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+)
+
+// LoginThrottledError is returned while logins are refused for the account
+// or the client IP. It reads the same either way and whether or not the
+// account exists.
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("too many failed login attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+func (e *LoginThrottledError) Unwrap() error {
+	return ErrTooManyLoginAttempts
+}
+
+// dummyPasswordHash is compared against when the account does not exist, so a
+// failed login takes as long either way.
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, _ := encryption.HashPassword("not a real password")
+	return hash
+})
+
+// loginLimit is the throttling policy of one kind of key.
+type loginLimit struct {
+	freeFailures int
+	maxFailures  int
+}
+
+func accountThrottleKey(email string) string {
+	return "account:" + strings.ToLower(strings.TrimSpace(email))
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+func (s *authService) accountLimit() loginLimit {
+	return loginLimit{freeFailures: s.cfg.Auth.LoginFreeFailures, maxFailures: s.cfg.Auth.LoginMaxFailures}
+}
+
+// An IP gets no delays before its block: many users can sit behind one
+// address, and delays would slow all of them down.
+func (s *authService) ipLimit() loginLimit {
+	return loginLimit{freeFailures: s.cfg.Auth.LoginIPMaxFailures, maxFailures: s.cfg.Auth.LoginIPMaxFailures}
+}
+
+// checkLoginThrottles refuses the login when the account or the IP is
+// blocked.
+func (s *authService) checkLoginThrottles(email string, client *dto.ClientInfo, now time.Time) error {
+	keys := []string{accountThrottleKey(email)}
+	if client != nil && client.IPAddress != "" {
+		keys = append(keys, ipThrottleKey(client.IPAddress))
+	}
+
+	throttles, err := s.loginThrottleRepo.GetBlockedLoginThrottles(keys, now)
+	if err != nil {
+		return err
+	}
+
+	var retryAfter time.Duration
+	for _, throttle := range throttles {
+		retryAfter = max(retryAfter, throttle.BlockedUntil.Sub(now))
+	}
+	if retryAfter > 0 {
+		return &LoginThrottledError{RetryAfter: retryAfter}
+	}
+	return nil
+}
+
+// recordFailedLogin counts a failed login against the account and the IP.
+// user is empty when no account has that email; the attempt still counts so
+// unknown addresses are throttled like real ones.
+func (s *authService) recordFailedLogin(email string, user *models.User, client *dto.ClientInfo, now time.Time) error {
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		lockedUntil, err := s.recordFailureTx(accountThrottleKey(email), s.accountLimit(), now, tx)
+		if err != nil {
+			return err
+		}
+
+		if client != nil && client.IPAddress != "" {
+			if _, err := s.recordFailureTx(ipThrottleKey(client.IPAddress), s.ipLimit(), now, tx); err != nil {
+				return err
+			}
+		}
+
+		if lockedUntil.IsZero() || user.ID == 0 {
+			return nil
+		}
+
+		locked := &events.AccountLocked{
+			UserID:      user.ID,
+			Email:       user.Email,
+			FirstName:   user.FirstName,
+			LastName:    user.LastName,
+			Locale:      user.Locale,
+			LockedAt:    now,
+			LockedUntil: lockedUntil,
+		}
+		if client != nil {
+			locked.IPAddress = client.IPAddress
+		}
+
+		message, err := events.NewOutboxMessage(s.cfg.Events.Producer, locked)
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*message}, tx)
+	})
+}
+
+// recordFailureTx adds a failure to the throttle of key and blocks it as
+// limit says. It returns the end of the lockout when this failure locked
+// the key, and the zero time otherwise.
+func (s *authService) recordFailureTx(key string, limit loginLimit, now time.Time, tx *gorm.DB) (time.Time, error) {
+	throttle, err := s.loginThrottleRepo.LockLoginThrottleTx(key, tx)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if throttle.LastFailedAt.Before(now.Add(-s.cfg.Auth.LoginFailureWindow)) {
+		throttle.Failures = 0
+	}
+	throttle.Failures++
+	throttle.LastFailedAt = now
+
+	var lockedUntil time.Time
+	switch {
+	case throttle.Failures >= limit.maxFailures:
+		// The count restarts after a lockout, delays included.
+		lockedUntil = now.Add(s.cfg.Auth.LoginLockoutDuration)
+		throttle.Failures = 0
+		throttle.BlockedUntil = &lockedUntil
+	case throttle.Failures > limit.freeFailures:
+		delay := s.cfg.Auth.LoginBaseDelay << min(throttle.Failures-limit.freeFailures-1, 20)
+		blockedUntil := now.Add(min(delay, s.cfg.Auth.LoginLockoutDuration))
+		throttle.BlockedUntil = &blockedUntil
+	default:
+		throttle.BlockedUntil = nil
+	}
+
+	if err := s.loginThrottleRepo.SaveLoginThrottleTx(throttle, tx); err != nil {
+		return time.Time{}, err
+	}
+	return lockedUntil, nil
+}
+
+// UnlockAccount lifts the lockout and clears the failed logins of a user.
+// Blocks on the IPs the failures came from stay.
+func (s *authService) UnlockAccount(actorID, userID uint) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.loginThrottleRepo.DeleteLoginThrottle(accountThrottleKey(user.Email)); err != nil {
+		return err
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		return s.auditRepo.CreateAuditLogTx(&models.AuditLog{
+			ActorID: actorID,
+			UserID:  user.ID,
+			Action:  models.AuditUserUnlocked,
+		}, tx)
+	})
+}
+
+// clearFailedLogins forgets the failures of an account after a successful
+// login. It only logs errors: the user already proved their password.
+func (s *authService) clearFailedLogins(email string) {
+	if err := s.loginThrottleRepo.DeleteLoginThrottle(accountThrottleKey(email)); err != nil {
+		s.log.Error().Err(err).Msg("Failed to clear failed logins")
+	}
+}
@@ -0,0 +1,148 @@
+package authService
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+)
+
+func newThrottleTestService(t *testing.T) *testService {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.Auth.LoginFailureWindow = 15 * time.Minute
+	cfg.Auth.LoginFreeFailures = 3
+	cfg.Auth.LoginBaseDelay = time.Minute
+	cfg.Auth.LoginMaxFailures = 10
+	cfg.Auth.LoginLockoutDuration = 15 * time.Minute
+	cfg.Auth.LoginIPMaxFailures = 20
+	return newTestService(t, cfg)
+}
+
+func TestRecordFailure(t *testing.T) {
+	now := time.Now()
+	earlier := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+
+	tests := []struct {
+		name string
+		ip   bool
+		// The throttle before the failure; nil when there is none.
+		before *models.LoginThrottle
+		// The failures counted and how long logins are then refused for.
+		wantFailures int
+		wantBlock    time.Duration
+		wantLocked   bool
+	}{
+		{name: "first failure is free", wantFailures: 1},
+		{name: "last free failure", before: &models.LoginThrottle{Failures: 2, LastFailedAt: now}, wantFailures: 3},
+		{name: "first delay", before: &models.LoginThrottle{Failures: 3, LastFailedAt: now}, wantFailures: 4, wantBlock: time.Minute},
+		{name: "delay doubles", before: &models.LoginThrottle{Failures: 4, LastFailedAt: now}, wantFailures: 5, wantBlock: 2 * time.Minute},
+		{name: "delay keeps doubling", before: &models.LoginThrottle{Failures: 6, LastFailedAt: now}, wantFailures: 7, wantBlock: 8 * time.Minute},
+		{name: "delay is capped at the lockout", before: &models.LoginThrottle{Failures: 7, LastFailedAt: now}, wantFailures: 8, wantBlock: 15 * time.Minute},
+		{name: "lockout restarts the count", before: &models.LoginThrottle{Failures: 9, LastFailedAt: now}, wantFailures: 0, wantBlock: 15 * time.Minute, wantLocked: true},
+		{name: "failure inside the window counts", before: &models.LoginThrottle{Failures: 3, LastFailedAt: now.Add(-14 * time.Minute)}, wantFailures: 4, wantBlock: time.Minute},
+		{name: "failures expire after the window", before: &models.LoginThrottle{Failures: 9, LastFailedAt: now.Add(-16 * time.Minute)}, wantFailures: 1},
+		{name: "expired failures drop their delay", before: &models.LoginThrottle{Failures: 5, LastFailedAt: now.Add(-20 * time.Minute), BlockedUntil: earlier(18 * time.Minute)}, wantFailures: 1},
+		{name: "ip has no delays", ip: true, before: &models.LoginThrottle{Failures: 18, LastFailedAt: now}, wantFailures: 19},
+		{name: "ip is blocked at its limit", ip: true, before: &models.LoginThrottle{Failures: 19, LastFailedAt: now}, wantFailures: 0, wantBlock: 15 * time.Minute, wantLocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := newThrottleTestService(t)
+
+			key, limit := accountThrottleKey("jane@example.com"), ts.accountLimit()
+			if tt.ip {
+				key, limit = ipThrottleKey("192.0.2.1"), ts.ipLimit()
+			}
+			if tt.before != nil {
+				tt.before.Key = key
+				ts.throttles.throttles[key] = tt.before
+			}
+
+			lockedUntil, err := ts.recordFailureTx(key, limit, now, ts.db)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			throttle := ts.throttles.throttles[key]
+			if throttle.Failures != tt.wantFailures {
+				t.Errorf("failures = %d, want %d", throttle.Failures, tt.wantFailures)
+			}
+			if !throttle.LastFailedAt.Equal(now) {
+				t.Errorf("last failure at %s, want %s", throttle.LastFailedAt, now)
+			}
+
+			switch {
+			case tt.wantBlock == 0 && throttle.BlockedUntil != nil:
+				t.Errorf("blocked until %s, want no block", throttle.BlockedUntil)
+			case tt.wantBlock != 0 && (throttle.BlockedUntil == nil || !throttle.BlockedUntil.Equal(now.Add(tt.wantBlock))):
+				t.Errorf("blocked until %v, want %s later", throttle.BlockedUntil, tt.wantBlock)
+			}
+
+			if tt.wantLocked != !lockedUntil.IsZero() {
+				t.Errorf("locked until %v, want locked %v", lockedUntil, tt.wantLocked)
+			}
+			if tt.wantLocked && !lockedUntil.Equal(now.Add(tt.wantBlock)) {
+				t.Errorf("locked until %s, want %s later", lockedUntil, tt.wantBlock)
+			}
+		})
+	}
+}
+
+func TestLockoutRefusesLoginsAndNotifiesTheUser(t *testing.T) {
+	ts := newThrottleTestService(t)
+	user := ts.users.add(&models.User{Email: "jane@example.com", IsActive: true})
+	now := time.Now()
+
+	for i := range ts.cfg.Auth.LoginMaxFailures {
+		if err := ts.recordFailedLogin(user.Email, user, testClient, now); err != nil {
+			t.Fatal(err)
+		}
+		if locked := len(ts.outbox.messages) > 0; locked != (i == ts.cfg.Auth.LoginMaxFailures-1) {
+			t.Fatalf("failure %d: account locked %v", i+1, locked)
+		}
+	}
+
+	if len(ts.outbox.messages) != 1 || ts.outbox.messages[0].EventType != events.AccountLockedEventType {
+		t.Fatalf("outbox = %+v, want one %s", ts.outbox.messages, events.AccountLockedEventType)
+	}
+
+	err := ts.checkLoginThrottles("Jane@Example.com ", nil, now.Add(time.Minute))
+	var throttled *LoginThrottledError
+	if !errors.As(err, &throttled) || !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Fatalf("got %v, want a LoginThrottledError", err)
+	}
+	if throttled.RetryAfter != 14*time.Minute {
+		t.Errorf("retry after %s, want 14m", throttled.RetryAfter)
+	}
+
+	if err := ts.checkLoginThrottles(user.Email, nil, now.Add(ts.cfg.Auth.LoginLockoutDuration)); err != nil {
+		t.Errorf("after the lockout: %v", err)
+	}
+}
+
+func TestUnknownEmailIsThrottledWithoutNotification(t *testing.T) {
+	ts := newThrottleTestService(t)
+	now := time.Now()
+
+	for range ts.cfg.Auth.LoginMaxFailures {
+		if err := ts.recordFailedLogin("nobody@example.com", &models.User{}, testClient, now); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(ts.outbox.messages) != 0 {
+		t.Errorf("outbox = %+v, want no messages", ts.outbox.messages)
+	}
+	if err := ts.checkLoginThrottles("nobody@example.com", nil, now); !errors.Is(err, ErrTooManyLoginAttempts) {
+		t.Fatalf("got %v, want ErrTooManyLoginAttempts", err)
+	}
+}
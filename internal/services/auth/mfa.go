@@ -0,0 +1,373 @@
+package authService
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+	"github.com/anzhy11/go-e-commerce/pkg/totp"
+)
+
+const (
+	dateFormat = "2006-01-02 15:04:05"
+
+	recoveryCodeCount = 10
+	// totpSkew accepts the codes of the previous and next period, for clocks
+	// that drifted.
+	totpSkew = 1
+)
+
+// GetMFAStatus tells whether the user has two-factor authentication.
+func (s *authService) GetMFAStatus(userID uint) (*dto.MFAStatusResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := s.mfaRequired(user)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dto.MFAStatusResponse{
+		Enabled:  user.MFAEnabledAt != nil,
+		Required: required,
+	}
+	if user.MFAEnabledAt == nil {
+		return status, nil
+	}
+
+	status.EnabledAt = user.MFAEnabledAt.Format(dateFormat)
+	status.RecoveryCodesLeft, err = s.mfaRepo.CountRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// EnrollMFA creates the secret of a new authenticator. Nothing changes for
+// the user until ConfirmMFA; enrolling again replaces a pending secret.
+func (s *authService) EnrollMFA(userID uint) (*dto.MFAEnrollmentResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	key, err := s.mfaKey()
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := encryption.Encrypt(key, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.mfaRepo.SaveUserTOTP(&models.UserTOTP{UserID: user.ID, Secret: encrypted}); err != nil {
+		return nil, err
+	}
+
+	return &dto.MFAEnrollmentResponse{
+		Secret:     secret,
+		OTPAuthURI: totp.URI(s.cfg.Auth.MFAIssuer, user.Email, secret),
+	}, nil
+}
+
+// ConfirmMFA turns two-factor authentication on once the user proves the
+// authenticator works, and returns the first recovery codes.
+func (s *authService) ConfirmMFA(userID uint, code string) (*dto.MFARecoveryCodesResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabledAt != nil {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkMFACodeTx(user, code, false, tx); err != nil {
+			return err
+		}
+
+		now := time.Now()
+		user.MFAEnabledAt = &now
+		if err := s.userRepo.SetMFAEnabledTx(user, tx); err != nil {
+			return err
+		}
+		return s.mfaRepo.ReplaceRecoveryCodesTx(user.ID, hashes, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// DisableMFA turns two-factor authentication off. It takes the password and
+// a code, so a stolen session alone cannot do it.
+func (s *authService) DisableMFA(userID uint, data *dto.DisableMFARequest) error {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	if user.MFAEnabledAt == nil {
+		return ErrMFANotEnabled
+	}
+	required, err := s.mfaRequired(user)
+	if err != nil {
+		return err
+	}
+	if required {
+		return ErrMFARequired
+	}
+	if !encryption.CheckPassword(data.Password, user.Password) {
+		return ErrInvalidPassword
+	}
+
+	return s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkMFACodeTx(user, data.Code, true, tx); err != nil {
+			return err
+		}
+
+		if err := s.mfaRepo.DeleteUserTOTPTx(user.ID, tx); err != nil {
+			return err
+		}
+
+		user.MFAEnabledAt = nil
+		return s.userRepo.SetMFAEnabledTx(user, tx)
+	})
+}
+
+// RegenerateRecoveryCodes replaces every recovery code of the user. It takes
+// an authenticator code; a recovery code would not do, since it is about to
+// be replaced.
+func (s *authService) RegenerateRecoveryCodes(userID uint, code string) (*dto.MFARecoveryCodesResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabledAt == nil {
+		return nil, ErrMFANotEnabled
+	}
+
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.checkMFACodeTx(user, code, false, tx); err != nil {
+			return err
+		}
+		return s.mfaRepo.ReplaceRecoveryCodesTx(user.ID, hashes, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.MFARecoveryCodesResponse{RecoveryCodes: codes}, nil
+}
+
+// VerifyMFA completes a login started with the password or an identity
+// provider. Wrong codes count as failed logins, so they are throttled like
+// passwords.
+func (s *authService) VerifyMFA(data *dto.MFAVerifyRequest, client *dto.ClientInfo) (*dto.AuthResponse, error) {
+	challenge, err := s.findUserToken(models.UserTokenMFAChallenge, data.MFAToken)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.getUser(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabledAt == nil {
+		return nil, ErrInvalidToken
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	now := time.Now()
+	if err := s.checkLoginThrottles(user.Email, client, now); err != nil {
+		return nil, err
+	}
+
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		used, err := s.userTokenRepo.UseUserTokenTx(challenge, tx)
+		if err != nil {
+			return err
+		}
+		if !used {
+			return ErrInvalidToken
+		}
+		return s.checkMFACodeTx(user, data.Code, true, tx)
+	})
+	if errors.Is(err, ErrInvalidMFACode) {
+		if err := s.recordFailedLogin(user.Email, user, client, now); err != nil {
+			return nil, err
+		}
+		return nil, ErrInvalidMFACode
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s.clearFailedLogins(user.Email)
+	// Challenges issued before the first factor was recorded all followed a
+	// password.
+	firstFactor := challenge.AuthMethod
+	if firstFactor == "" {
+		firstFactor = utils.AuthMethodPassword
+	}
+	return s.generateAuthResponse(user, client, []string{firstFactor, utils.AuthMethodOTP})
+}
+
+// mfaChallenge starts the second step of a login whose first factor was
+// authMethod.
+func (s *authService) mfaChallenge(user *models.User, authMethod string) (*dto.LoginResponse, error) {
+	var token string
+	var expiresAt time.Time
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		token, expiresAt, err = s.saveUserTokenTx(&models.UserToken{
+			UserID:     user.ID,
+			Purpose:    models.UserTokenMFAChallenge,
+			AuthMethod: authMethod,
+		}, s.cfg.Auth.MFAChallengeTTL, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.LoginResponse{
+		MFA: &dto.MFAChallengeResponse{
+			MFAToken:  token,
+			ExpiresAt: expiresAt.Format(dateFormat),
+		},
+	}, nil
+}
+
+// checkMFACodeTx accepts an authenticator code, or a recovery code when
+// allowRecovery is set, and fails with ErrInvalidMFACode otherwise. Either
+// kind of code is used up.
+func (s *authService) checkMFACodeTx(user *models.User, code string, allowRecovery bool, tx *gorm.DB) error {
+	authenticator, err := s.mfaRepo.GetUserTOTP(user.ID)
+	if err != nil {
+		return err
+	}
+	if authenticator.UserID == 0 {
+		return ErrMFANotEnrolled
+	}
+
+	key, err := s.mfaKey()
+	if err != nil {
+		return err
+	}
+
+	secret, err := encryption.Decrypt(key, authenticator.Secret)
+	if err != nil {
+		return err
+	}
+
+	used := false
+	if step, ok := totp.Validate(secret, code, time.Now(), totpSkew); ok {
+		used, err = s.mfaRepo.UseTOTPStepTx(user.ID, step, tx)
+	} else if allowRecovery {
+		used, err = s.mfaRepo.UseRecoveryCodeTx(user.ID, encryption.HashToken(normalizeRecoveryCode(code)), tx)
+	}
+	if err != nil {
+		return err
+	}
+	if !used {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// mfaRequired tells whether the user is staff, with a role that has a
+// permission, while staff are required to use two-factor authentication.
+func (s *authService) mfaRequired(user *models.User) (bool, error) {
+	if !s.cfg.Auth.RequireMFAForAdmin {
+		return false, nil
+	}
+
+	permissions, err := s.roleRepo.GetRolePermissions(user.Role)
+	if err != nil {
+		return false, err
+	}
+	return len(permissions) > 0, nil
+}
+
+// mfaKey returns the key authenticator secrets are encrypted with.
+func (s *authService) mfaKey() ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s.cfg.Auth.MFASecretKey)
+	if err != nil || len(key) != 32 {
+		return nil, ErrMFAUnavailable
+	}
+	return key, nil
+}
+
+func (s *authService) getUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// generateRecoveryCodes returns recovery codes formatted for the user and
+// the hashes to store.
+func generateRecoveryCodes() (codes, hashes []string, err error) {
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+
+	for range recoveryCodeCount {
+		// 10 random bytes are 16 base32 characters, shown as 4 groups.
+		code := make([]byte, 10)
+		if _, err := rand.Read(code); err != nil {
+			return nil, nil, err
+		}
+		raw := strings.ToLower(encoding.EncodeToString(code))
+		formatted := raw[0:4] + "-" + raw[4:8] + "-" + raw[8:12] + "-" + raw[12:16]
+
+		codes = append(codes, formatted)
+		hashes = append(hashes, encryption.HashToken(raw))
+	}
+	return codes, hashes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, code)
+}
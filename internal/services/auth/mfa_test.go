@@ -0,0 +1,263 @@
+package authService
+
+import (
+	"encoding/base64"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/identity/oidctest"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+	"github.com/anzhy11/go-e-commerce/pkg/totp"
+)
+
+const testPassword = "correct horse battery staple"
+
+func newMFATestService(t *testing.T) *testService {
+	t.Helper()
+
+	cfg := &config.Config{}
+	cfg.JWT.Issuer = "https://shop.example.com"
+	cfg.JWT.Audience = "shop-api"
+	cfg.JWT.ExpiresIn = time.Minute
+	cfg.JWT.RefreshTokenExpiresIn = time.Hour
+	cfg.Auth.MFASecretKey = testMFASecretKey
+	cfg.Auth.MFAChallengeTTL = 5 * time.Minute
+	return newTestService(t, cfg)
+}
+
+var testMFASecretKey = base64.StdEncoding.EncodeToString(make([]byte, 32))
+
+// enableMFA gives user an authenticator with two-factor authentication on
+// and returns its secret.
+func enableMFA(t *testing.T, ts *testService) (*models.User, string) {
+	t.Helper()
+
+	hashed, err := encryption.HashPassword(testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	user := ts.users.add(&models.User{Email: "jane@example.com", Password: hashed, IsActive: true, MFAEnabledAt: &now})
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := ts.mfaKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	encrypted, err := encryption.Encrypt(key, secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts.mfa.totps[user.ID] = &models.UserTOTP{UserID: user.ID, Secret: encrypted}
+
+	return user, secret
+}
+
+func codeAt(t *testing.T, secret string, step int64) string {
+	t.Helper()
+
+	code, err := totp.Code(secret, step)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// completeMFA answers the challenge of login with a current code and checks
+// the amr claim of the access token issued.
+func completeMFA(t *testing.T, ts *testService, login *dto.LoginResponse, secret string, wantAMR []string) {
+	t.Helper()
+
+	if login.MFA == nil || login.AuthResponse != nil {
+		t.Fatalf("got %+v, want an MFA challenge", login)
+	}
+
+	resp, err := ts.VerifyMFA(&dto.MFAVerifyRequest{
+		MFAToken: login.MFA.MFAToken,
+		Code:     codeAt(t, secret, totp.Step(time.Now())),
+	}, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload, err := ts.tokens.VerifyAccessToken(resp.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(payload.AuthMethods, wantAMR) {
+		t.Errorf("amr = %v, want %v", payload.AuthMethods, wantAMR)
+	}
+}
+
+func TestPasswordLoginWithMFA(t *testing.T) {
+	ts := newMFATestService(t)
+	user, secret := enableMFA(t, ts)
+
+	login, err := ts.Login(&dto.LoginRequest{Email: user.Email, Password: testPassword}, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completeMFA(t, ts, login, secret, []string{utils.AuthMethodPassword, utils.AuthMethodOTP})
+}
+
+func TestOIDCLoginWithMFAKeepsTheFederatedFactor(t *testing.T) {
+	ts, issuer := newOIDCTestService(t)
+	ts.cfg.Auth.MFASecretKey = testMFASecretKey
+	ts.cfg.Auth.MFAChallengeTTL = 5 * time.Minute
+
+	user, secret := enableMFA(t, ts)
+	verifiedAt := time.Now()
+	ts.users.users[user.ID].EmailVerifiedAt = &verifiedAt
+	issuer.SetUser(oidctest.User{Subject: "subject-1", Email: user.Email, EmailVerified: true})
+
+	login, err := completeLogin(t, ts, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	completeMFA(t, ts, login, secret, []string{utils.AuthMethodFederated, utils.AuthMethodOTP})
+}
+
+func TestMFAChallengeWorksOnce(t *testing.T) {
+	ts := newMFATestService(t)
+	user, secret := enableMFA(t, ts)
+
+	login, err := ts.Login(&dto.LoginRequest{Email: user.Email, Password: testPassword}, testClient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	completeMFA(t, ts, login, secret, []string{utils.AuthMethodPassword, utils.AuthMethodOTP})
+
+	_, err = ts.VerifyMFA(&dto.MFAVerifyRequest{
+		MFAToken: login.MFA.MFAToken,
+		Code:     codeAt(t, secret, totp.Step(time.Now())+1),
+	}, testClient)
+	if !errors.Is(err, ErrInvalidToken) {
+		t.Fatalf("reused challenge: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestAuthenticatorCodeWorksOnce(t *testing.T) {
+	ts := newMFATestService(t)
+	user, secret := enableMFA(t, ts)
+
+	step := totp.Step(time.Now())
+	code := codeAt(t, secret, step)
+
+	if _, err := ts.RegenerateRecoveryCodes(user.ID, code); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if _, err := ts.RegenerateRecoveryCodes(user.ID, code); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("second use: got %v, want ErrInvalidMFACode", err)
+	}
+
+	// The previous period is within the skew, but a later step was used.
+	previous := codeAt(t, secret, step-1)
+	if _, err := ts.RegenerateRecoveryCodes(user.ID, previous); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("earlier step: got %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestRecoveryCodeWorksOnce(t *testing.T) {
+	ts := newMFATestService(t)
+	user, secret := enableMFA(t, ts)
+
+	codes, err := ts.RegenerateRecoveryCodes(user.ID, codeAt(t, secret, totp.Step(time.Now())))
+	if err != nil {
+		t.Fatal(err)
+	}
+	code := codes.RecoveryCodes[0]
+
+	if err := ts.checkMFACodeTx(user, code, true, ts.db); err != nil {
+		t.Fatalf("first use: %v", err)
+	}
+	if err := ts.checkMFACodeTx(user, code, true, ts.db); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("second use: got %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestRecoveryCodeRefusedWhereNotAllowed(t *testing.T) {
+	ts := newMFATestService(t)
+	user, secret := enableMFA(t, ts)
+
+	codes, err := ts.RegenerateRecoveryCodes(user.ID, codeAt(t, secret, totp.Step(time.Now())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := ts.checkMFACodeTx(user, codes.RecoveryCodes[0], false, ts.db); !errors.Is(err, ErrInvalidMFACode) {
+		t.Fatalf("got %v, want ErrInvalidMFACode", err)
+	}
+}
+
+func TestDisableMFAWithReformattedRecoveryCode(t *testing.T) {
+	ts := newMFATestService(t)
+	user, secret := enableMFA(t, ts)
+
+	codes, err := ts.RegenerateRecoveryCodes(user.ID, codeAt(t, secret, totp.Step(time.Now())))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Users retype codes in capitals, with spaces for the dashes.
+	typed := strings.ToUpper(strings.ReplaceAll(codes.RecoveryCodes[0], "-", " "))
+	if err := ts.DisableMFA(user.ID, &dto.DisableMFARequest{Password: testPassword, Code: typed}); err != nil {
+		t.Fatal(err)
+	}
+
+	if ts.users.users[user.ID].MFAEnabledAt != nil {
+		t.Error("two-factor authentication is still enabled")
+	}
+	if _, ok := ts.mfa.totps[user.ID]; ok {
+		t.Error("authenticator was not deleted")
+	}
+}
+
+func TestNormalizeRecoveryCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want string
+	}{
+		{"abcd-efgh-ijkl-mnop", "abcdefghijklmnop"},
+		{"ABCD-EFGH-IJKL-MNOP", "abcdefghijklmnop"},
+		{"abcd efgh ijkl mnop", "abcdefghijklmnop"},
+		{"abcdefghijklmnop", "abcdefghijklmnop"},
+		{" Abcd-Efgh Ijkl-Mnop ", "abcdefghijklmnop"},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeRecoveryCode(tt.code); got != tt.want {
+			t.Errorf("normalizeRecoveryCode(%q) = %q, want %q", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedRecoveryCodesMatchTheirHashes(t *testing.T) {
+	codes, hashes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(codes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+		t.Fatalf("got %d codes and %d hashes, want %d", len(codes), len(hashes), recoveryCodeCount)
+	}
+
+	for i, code := range codes {
+		if len(code) != 19 || strings.Count(code, "-") != 3 {
+			t.Errorf("code %q is not formatted as 4 groups of 4", code)
+		}
+		if encryption.HashToken(normalizeRecoveryCode(code)) != hashes[i] {
+			t.Errorf("code %q does not match its hash", code)
+		}
+	}
+}
@@ -0,0 +1,253 @@
+package authService
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/events"
+	"github.com/anzhy11/go-e-commerce/internal/identity"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+)
+
+// newIdentityRegistry builds the OpenID Connect providers of the config.
+// Nothing is fetched from them until a user signs in.
+func newIdentityRegistry(cfg *config.OIDCConfig) *identity.Registry {
+	providers := make([]identity.Provider, 0, len(cfg.Providers))
+	for _, provider := range cfg.Providers {
+		providers = append(providers, identity.NewOIDCProvider(identity.OIDCConfig{
+			Name:         provider.Name,
+			IssuerURL:    provider.IssuerURL,
+			ClientID:     provider.ClientID,
+			ClientSecret: provider.ClientSecret,
+			RedirectURL:  provider.RedirectURL,
+			Scopes:       provider.Scopes,
+		}))
+	}
+	return identity.NewRegistry(providers...)
+}
+
+// ListOIDCProviders returns the identity providers users can sign in with.
+func (s *authService) ListOIDCProviders() *dto.OIDCProvidersResponse {
+	return &dto.OIDCProvidersResponse{Providers: s.identityProviders.Names()}
+}
+
+// StartOIDCLogin starts a login with an identity provider. The state, nonce
+// and PKCE code verifier are kept until the user comes back.
+func (s *authService) StartOIDCLogin(ctx context.Context, providerName string) (*dto.OIDCLogin, error) {
+	provider, err := s.identityProviders.Provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	var state, nonce, codeVerifier string
+	for _, token := range []*string{&state, &nonce, &codeVerifier} {
+		if *token, err = identity.RandomToken(); err != nil {
+			return nil, err
+		}
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, &identity.AuthRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: identity.CodeChallenge(codeVerifier),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	loginState := &models.OIDCLoginState{
+		StateHash:    encryption.HashToken(state),
+		Provider:     provider.Name(),
+		Nonce:        nonce,
+		CodeVerifier: codeVerifier,
+		ExpiresAt:    time.Now().Add(s.cfg.OIDC.StateTTL),
+	}
+	if err := s.identityRepo.CreateOIDCLoginState(loginState); err != nil {
+		return nil, err
+	}
+
+	return &dto.OIDCLogin{
+		AuthorizationURL: authURL,
+		State:            state,
+		ExpiresAt:        loginState.ExpiresAt,
+	}, nil
+}
+
+// CompleteOIDCLogin signs in the user an identity provider sent back. A
+// known identity signs in its user. Otherwise the identity is linked to the
+// account with the same email, or a new account is created, and both need
+// the email verified on either side. Accounts with two-factor
+// authentication get an MFA challenge, as with a password.
+func (s *authService) CompleteOIDCLogin(ctx context.Context, providerName string, data *dto.OIDCCallbackRequest, client *dto.ClientInfo) (*dto.LoginResponse, error) {
+	provider, err := s.identityProviders.Provider(providerName)
+	if err != nil {
+		return nil, err
+	}
+
+	loginState, err := s.identityRepo.TakeOIDCLoginState(encryption.HashToken(data.State))
+	if err != nil {
+		return nil, err
+	}
+	if loginState.StateHash == "" || loginState.Provider != provider.Name() || loginState.ExpiresAt.Before(time.Now()) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	if data.Error != "" {
+		return nil, fmt.Errorf("%w: %s %s", ErrOIDCLoginDenied, data.Error, data.ErrorDescription)
+	}
+	if data.Code == "" {
+		return nil, ErrInvalidOIDCState
+	}
+
+	external, err := provider.Exchange(ctx, data.Code, loginState.CodeVerifier, loginState.Nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.identityUser(external)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, ErrAccountDeactivated
+	}
+
+	if user.MFAEnabledAt != nil {
+		return s.mfaChallenge(user, utils.AuthMethodFederated)
+	}
+
+	resp, err := s.generateAuthResponse(user, client, []string{utils.AuthMethodFederated})
+	if err != nil {
+		return nil, err
+	}
+	return &dto.LoginResponse{AuthResponse: resp}, nil
+}
+
+// identityUser returns the user an external identity signs in, linking or
+// creating the account on first sign-in.
+func (s *authService) identityUser(external *identity.Identity) (*models.User, error) {
+	now := time.Now()
+
+	linked, err := s.identityRepo.GetUserIdentity(external.Provider, external.Subject)
+	if err != nil {
+		return nil, err
+	}
+	if linked.ID != 0 {
+		user, err := s.getUser(linked.UserID)
+		if err != nil {
+			return nil, err
+		}
+
+		linked.Email = external.Email
+		linked.LastLoginAt = now
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			return s.identityRepo.UpdateUserIdentityLoginTx(linked, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	if external.Email == "" || !external.EmailVerified {
+		return nil, ErrIdentityEmailUnverified
+	}
+
+	user, err := s.userRepo.GetUserByEmail(external.Email)
+	if err != nil {
+		return nil, err
+	}
+	// Whoever registered an unverified account may not own the email, and
+	// linking would let them in as the provider's user.
+	if user.ID != 0 && user.EmailVerifiedAt == nil {
+		return nil, ErrAccountEmailUnverified
+	}
+
+	link := &models.UserIdentity{
+		Provider:    external.Provider,
+		Subject:     external.Subject,
+		Email:       external.Email,
+		LastLoginAt: now,
+	}
+
+	if user.ID != 0 {
+		link.UserID = user.ID
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			return s.identityRepo.CreateUserIdentityTx(link, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return user, nil
+	}
+
+	return s.registerIdentity(external, link, now)
+}
+
+// registerIdentity creates the account of a new user from their external
+// identity, like Register does. The account has no password; one can be
+// set with a password reset.
+func (s *authService) registerIdentity(external *identity.Identity, link *models.UserIdentity, now time.Time) (*models.User, error) {
+	user := &models.User{
+		FirstName:       external.GivenName,
+		LastName:        external.FamilyName,
+		Email:           external.Email,
+		Role:            string(models.RoleCustomer),
+		IsActive:        true,
+		Locale:          identityLocale(external.Locale),
+		EmailVerifiedAt: &now,
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.userRepo.CreateUserTx(user, tx); err != nil {
+			return err
+		}
+
+		if err := s.cartRepo.CreateCartTx(&models.Cart{UserID: user.ID}, tx); err != nil {
+			return err
+		}
+
+		link.UserID = user.ID
+		if err := s.identityRepo.CreateUserIdentityTx(link, tx); err != nil {
+			return err
+		}
+
+		// The provider verified the email, so there is no verification link.
+		registered, err := events.NewOutboxMessage(s.cfg.Events.Producer, &events.UserRegistered{
+			UserID:    user.ID,
+			Email:     user.Email,
+			FirstName: user.FirstName,
+			LastName:  user.LastName,
+			Locale:    user.Locale,
+		})
+		if err != nil {
+			return err
+		}
+		return s.outboxRepo.CreateOutboxMessagesTx([]models.OutboxMessage{*registered}, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return user, nil
+}
+
+// identityLocale maps the locale of a provider, such as "fr-CA", to one the
+// shop supports.
+func identityLocale(locale string) string {
+	language, _, _ := strings.Cut(strings.ReplaceAll(strings.ToLower(locale), "_", "-"), "-")
+	switch language {
+	case "en", "fr":
+		return language
+	default:
+		return models.DefaultLocale
+	}
+}
@@ -0,0 +1,303 @@
+package authService
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/identity"
+	"github.com/anzhy11/go-e-commerce/internal/identity/oidctest"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+)
+
+const testClientID = "ecommerce"
+
+var testClient = &dto.ClientInfo{IPAddress: "192.0.2.1", UserAgent: "test"}
+
+// newOIDCTestService returns a service signing in with two providers, "dev"
+// and "other", both served by the returned issuer.
+func newOIDCTestService(t *testing.T) (*testService, *oidctest.Issuer) {
+	t.Helper()
+
+	var issuer *oidctest.Issuer
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		issuer.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	issuer, err := oidctest.New(server.URL, testClientID, "secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := &config.Config{}
+	cfg.JWT.Issuer = "https://shop.example.com"
+	cfg.JWT.Audience = "shop-api"
+	cfg.JWT.ExpiresIn = time.Minute
+	cfg.JWT.RefreshTokenExpiresIn = time.Hour
+	cfg.OIDC.StateTTL = 10 * time.Minute
+	for _, name := range []string{"dev", "other"} {
+		cfg.OIDC.Providers = append(cfg.OIDC.Providers, config.OIDCProviderConfig{
+			Name:         name,
+			IssuerURL:    server.URL,
+			ClientID:     testClientID,
+			ClientSecret: "secret",
+			RedirectURL:  "http://localhost:8080/api/v1/auth/oidc/" + name + "/callback",
+		})
+	}
+
+	return newTestService(t, cfg), issuer
+}
+
+// startLogin starts a login with provider and follows the authorization URL
+// the way a browser would. It returns the callback the issuer sent the user
+// back with.
+func startLogin(t *testing.T, ts *testService, provider string) *dto.OIDCCallbackRequest {
+	t.Helper()
+
+	login, err := ts.StartOIDCLogin(context.Background(), provider)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authURL, err := url.Parse(login.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authURL.Query().Get("code_challenge_method") != "S256" || authURL.Query().Get("code_challenge") == "" {
+		t.Fatalf("login does not use PKCE: %s", login.AuthorizationURL)
+	}
+
+	client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	}}
+	resp, err := client.Get(login.AuthorizationURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	location, err := resp.Location()
+	if err != nil {
+		t.Fatalf("no redirect back (status %d): %v", resp.StatusCode, err)
+	}
+	query := location.Query()
+	if query.Get("state") != login.State {
+		t.Fatalf("state %q came back, want %q", query.Get("state"), login.State)
+	}
+
+	return &dto.OIDCCallbackRequest{
+		State:            query.Get("state"),
+		Code:             query.Get("code"),
+		Error:            query.Get("error"),
+		ErrorDescription: query.Get("error_description"),
+	}
+}
+
+func completeLogin(t *testing.T, ts *testService, provider string) (*dto.LoginResponse, error) {
+	t.Helper()
+
+	callback := startLogin(t, ts, provider)
+	return ts.CompleteOIDCLogin(context.Background(), provider, callback, testClient)
+}
+
+func TestOIDCLoginCreatesUser(t *testing.T) {
+	ts, issuer := newOIDCTestService(t)
+	issuer.SetUser(oidctest.User{
+		Subject:       "subject-1",
+		Email:         "jane@example.com",
+		EmailVerified: true,
+		GivenName:     "Jane",
+		FamilyName:    "Doe",
+		Locale:        "fr-CA",
+	})
+
+	resp, err := completeLogin(t, ts, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.AuthResponse == nil {
+		t.Fatalf("no tokens issued: %+v", resp)
+	}
+
+	user := ts.users.users[resp.User.ID]
+	if user == nil {
+		t.Fatal("user was not created")
+	}
+	if user.Email != "jane@example.com" || user.FirstName != "Jane" || user.LastName != "Doe" {
+		t.Errorf("unexpected user %+v", user)
+	}
+	if user.EmailVerifiedAt == nil {
+		t.Error("email is not verified")
+	}
+	if user.Role != string(models.RoleCustomer) || user.Locale != "fr" || !user.IsActive {
+		t.Errorf("role %q, locale %q, active %v", user.Role, user.Locale, user.IsActive)
+	}
+
+	if len(ts.carts.carts) != 1 || ts.carts.carts[0].UserID != user.ID {
+		t.Errorf("carts = %+v, want one for user %d", ts.carts.carts, user.ID)
+	}
+
+	linked, _ := ts.identities.GetUserIdentity("dev", "subject-1")
+	if linked.UserID != user.ID {
+		t.Errorf("identity linked to user %d, want %d", linked.UserID, user.ID)
+	}
+
+	payload, err := ts.tokens.VerifyAccessToken(resp.AccessToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(payload.AuthMethods, []string{utils.AuthMethodFederated}) {
+		t.Errorf("amr = %v, want [%s]", payload.AuthMethods, utils.AuthMethodFederated)
+	}
+}
+
+func TestOIDCLoginSignsInKnownIdentity(t *testing.T) {
+	ts, _ := newOIDCTestService(t)
+
+	first, err := completeLogin(t, ts, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := completeLogin(t, ts, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if second.User.ID != first.User.ID {
+		t.Errorf("second login signed in user %d, want %d", second.User.ID, first.User.ID)
+	}
+	if len(ts.users.users) != 1 || len(ts.carts.carts) != 1 || len(ts.identities.identities) != 1 {
+		t.Errorf("got %d users, %d carts and %d identities, want one each", len(ts.users.users), len(ts.carts.carts), len(ts.identities.identities))
+	}
+}
+
+func TestOIDCLoginLinksVerifiedAccount(t *testing.T) {
+	ts, issuer := newOIDCTestService(t)
+	verifiedAt := time.Now().Add(-time.Hour)
+	account := ts.users.add(&models.User{Email: "jane@example.com", IsActive: true, EmailVerifiedAt: &verifiedAt})
+	issuer.SetUser(oidctest.User{Subject: "subject-1", Email: "jane@example.com", EmailVerified: true})
+
+	resp, err := completeLogin(t, ts, "dev")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.User.ID != account.ID {
+		t.Errorf("signed in user %d, want %d", resp.User.ID, account.ID)
+	}
+	if len(ts.users.users) != 1 || len(ts.carts.carts) != 0 {
+		t.Errorf("got %d users and %d carts, want the existing account only", len(ts.users.users), len(ts.carts.carts))
+	}
+
+	linked, _ := ts.identities.GetUserIdentity("dev", "subject-1")
+	if linked.UserID != account.ID {
+		t.Errorf("identity linked to user %d, want %d", linked.UserID, account.ID)
+	}
+}
+
+func TestOIDCLoginRefusesUnverifiedAccount(t *testing.T) {
+	ts, issuer := newOIDCTestService(t)
+	ts.users.add(&models.User{Email: "jane@example.com", IsActive: true})
+	issuer.SetUser(oidctest.User{Subject: "subject-1", Email: "jane@example.com", EmailVerified: true})
+
+	if _, err := completeLogin(t, ts, "dev"); !errors.Is(err, ErrAccountEmailUnverified) {
+		t.Fatalf("got %v, want ErrAccountEmailUnverified", err)
+	}
+	if len(ts.identities.identities) != 0 {
+		t.Error("identity was linked")
+	}
+}
+
+func TestOIDCLoginRefusesUnverifiedIdentityEmail(t *testing.T) {
+	ts, issuer := newOIDCTestService(t)
+	issuer.SetUser(oidctest.User{Subject: "subject-1", Email: "jane@example.com"})
+
+	if _, err := completeLogin(t, ts, "dev"); !errors.Is(err, ErrIdentityEmailUnverified) {
+		t.Fatalf("got %v, want ErrIdentityEmailUnverified", err)
+	}
+	if len(ts.users.users) != 0 {
+		t.Error("user was created")
+	}
+}
+
+func TestOIDCLoginRefusesDeactivatedAccount(t *testing.T) {
+	ts, issuer := newOIDCTestService(t)
+	verifiedAt := time.Now()
+	ts.users.add(&models.User{Email: "jane@example.com", EmailVerifiedAt: &verifiedAt})
+	issuer.SetUser(oidctest.User{Subject: "subject-1", Email: "jane@example.com", EmailVerified: true})
+
+	if _, err := completeLogin(t, ts, "dev"); !errors.Is(err, ErrAccountDeactivated) {
+		t.Fatalf("got %v, want ErrAccountDeactivated", err)
+	}
+}
+
+func TestOIDCStateWorksOnce(t *testing.T) {
+	ts, _ := newOIDCTestService(t)
+
+	callback := startLogin(t, ts, "dev")
+	if _, err := ts.CompleteOIDCLogin(context.Background(), "dev", callback, testClient); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ts.CompleteOIDCLogin(context.Background(), "dev", callback, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("replayed state: got %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestOIDCStateExpires(t *testing.T) {
+	ts, _ := newOIDCTestService(t)
+
+	callback := startLogin(t, ts, "dev")
+	ts.identities.states[encryption.HashToken(callback.State)].ExpiresAt = time.Now().Add(-time.Second)
+
+	if _, err := ts.CompleteOIDCLogin(context.Background(), "dev", callback, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("got %v, want ErrInvalidOIDCState", err)
+	}
+	if len(ts.identities.states) != 0 {
+		t.Error("expired state was kept")
+	}
+}
+
+func TestOIDCStateBelongsToItsProvider(t *testing.T) {
+	ts, _ := newOIDCTestService(t)
+
+	callback := startLogin(t, ts, "dev")
+	if _, err := ts.CompleteOIDCLogin(context.Background(), "other", callback, testClient); !errors.Is(err, ErrInvalidOIDCState) {
+		t.Fatalf("got %v, want ErrInvalidOIDCState", err)
+	}
+}
+
+func TestOIDCLoginRefusesNonceMismatch(t *testing.T) {
+	ts, _ := newOIDCTestService(t)
+
+	callback := startLogin(t, ts, "dev")
+	ts.identities.states[encryption.HashToken(callback.State)].Nonce = "another-login"
+
+	if _, err := ts.CompleteOIDCLogin(context.Background(), "dev", callback, testClient); !errors.Is(err, identity.ErrInvalidIDToken) {
+		t.Fatalf("got %v, want identity.ErrInvalidIDToken", err)
+	}
+	if len(ts.users.users) != 0 {
+		t.Error("user was created")
+	}
+}
+
+func TestOIDCLoginDeniedByProvider(t *testing.T) {
+	ts, _ := newOIDCTestService(t)
+
+	callback := startLogin(t, ts, "dev")
+	callback.Code = ""
+	callback.Error = "access_denied"
+
+	if _, err := ts.CompleteOIDCLogin(context.Background(), "dev", callback, testClient); !errors.Is(err, ErrOIDCLoginDenied) {
+		t.Fatalf("got %v, want ErrOIDCLoginDenied", err)
+	}
+}
@@ -0,0 +1,362 @@
+package authService
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/utils/tests"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+)
+
+// The service runs against in-memory repositories. Each embeds its
+// interface, so calling a method a test did not expect panics.
+
+var errNoDatabase = errors.New("tests have no database")
+
+// fakeConnPool lets gorm open and commit transactions without a database;
+// running SQL fails.
+type fakeConnPool struct{}
+
+func (fakeConnPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errNoDatabase
+}
+
+func (fakeConnPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errNoDatabase
+}
+
+func (fakeConnPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errNoDatabase
+}
+
+func (fakeConnPool) QueryRowContext(context.Context, string, ...any) *sql.Row {
+	return nil
+}
+
+func (fakeConnPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return &fakeTx{}, nil
+}
+
+type fakeTx struct{ fakeConnPool }
+
+func (*fakeTx) Commit() error   { return nil }
+func (*fakeTx) Rollback() error { return nil }
+
+func newFakeDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(tests.DummyDialector{}, &gorm.Config{
+		ConnPool:             fakeConnPool{},
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+type fakeUserRepo struct {
+	repository.UserRepositoryInterface
+	users map[uint]*models.User
+}
+
+func (r *fakeUserRepo) add(user *models.User) *models.User {
+	user.ID = uint(len(r.users) + 1)
+	r.users[user.ID] = user
+	return user
+}
+
+func (r *fakeUserRepo) GetUserById(userID uint) (*models.User, error) {
+	if user, ok := r.users[userID]; ok {
+		copied := *user
+		return &copied, nil
+	}
+	return &models.User{}, nil
+}
+
+func (r *fakeUserRepo) GetUserByEmail(email string) (*models.User, error) {
+	for _, user := range r.users {
+		if user.Email == email {
+			copied := *user
+			return &copied, nil
+		}
+	}
+	return &models.User{}, nil
+}
+
+func (r *fakeUserRepo) CreateUserTx(data *models.User, _ *gorm.DB) error {
+	copied := *data
+	data.ID = r.add(&copied).ID
+	return nil
+}
+
+func (r *fakeUserRepo) SetMFAEnabledTx(data *models.User, _ *gorm.DB) error {
+	r.users[data.ID].MFAEnabledAt = data.MFAEnabledAt
+	return nil
+}
+
+// fakeMFARepo keeps the rules of the real queries: a step is used once and
+// never before a later one, and a recovery code is used once.
+type fakeMFARepo struct {
+	repository.MFARepositoryInterface
+	totps         map[uint]*models.UserTOTP
+	recoveryCodes map[uint]map[string]bool
+}
+
+func (r *fakeMFARepo) GetUserTOTP(userID uint) (*models.UserTOTP, error) {
+	if authenticator, ok := r.totps[userID]; ok {
+		copied := *authenticator
+		return &copied, nil
+	}
+	return &models.UserTOTP{}, nil
+}
+
+func (r *fakeMFARepo) UseTOTPStepTx(userID uint, step int64, _ *gorm.DB) (bool, error) {
+	authenticator, ok := r.totps[userID]
+	if !ok || authenticator.LastUsedStep >= step {
+		return false, nil
+	}
+	authenticator.LastUsedStep = step
+	return true, nil
+}
+
+func (r *fakeMFARepo) DeleteUserTOTPTx(userID uint, _ *gorm.DB) error {
+	delete(r.totps, userID)
+	delete(r.recoveryCodes, userID)
+	return nil
+}
+
+func (r *fakeMFARepo) ReplaceRecoveryCodesTx(userID uint, codeHashes []string, _ *gorm.DB) error {
+	r.recoveryCodes[userID] = map[string]bool{}
+	for _, hash := range codeHashes {
+		r.recoveryCodes[userID][hash] = false
+	}
+	return nil
+}
+
+func (r *fakeMFARepo) UseRecoveryCodeTx(userID uint, codeHash string, _ *gorm.DB) (bool, error) {
+	used, ok := r.recoveryCodes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	r.recoveryCodes[userID][codeHash] = true
+	return true, nil
+}
+
+// fakeIdentityRepo takes a login state once, as the real DELETE ...
+// RETURNING does.
+type fakeIdentityRepo struct {
+	repository.IdentityRepositoryInterface
+	identities []*models.UserIdentity
+	states     map[string]*models.OIDCLoginState
+}
+
+func (r *fakeIdentityRepo) GetUserIdentity(provider, subject string) (*models.UserIdentity, error) {
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			copied := *identity
+			return &copied, nil
+		}
+	}
+	return &models.UserIdentity{}, nil
+}
+
+func (r *fakeIdentityRepo) CreateUserIdentityTx(data *models.UserIdentity, _ *gorm.DB) error {
+	data.ID = uint(len(r.identities) + 1)
+	copied := *data
+	r.identities = append(r.identities, &copied)
+	return nil
+}
+
+func (r *fakeIdentityRepo) UpdateUserIdentityLoginTx(data *models.UserIdentity, _ *gorm.DB) error {
+	for _, identity := range r.identities {
+		if identity.ID == data.ID {
+			identity.Email = data.Email
+			identity.LastLoginAt = data.LastLoginAt
+		}
+	}
+	return nil
+}
+
+func (r *fakeIdentityRepo) CreateOIDCLoginState(data *models.OIDCLoginState) error {
+	copied := *data
+	r.states[data.StateHash] = &copied
+	return nil
+}
+
+func (r *fakeIdentityRepo) TakeOIDCLoginState(stateHash string) (*models.OIDCLoginState, error) {
+	state, ok := r.states[stateHash]
+	if !ok {
+		return &models.OIDCLoginState{}, nil
+	}
+	delete(r.states, stateHash)
+	return state, nil
+}
+
+type fakeCartRepo struct {
+	repository.CartRepositoryInterface
+	carts []models.Cart
+}
+
+func (r *fakeCartRepo) CreateCartTx(cart *models.Cart, _ *gorm.DB) error {
+	r.carts = append(r.carts, *cart)
+	return nil
+}
+
+type fakeSessionRepo struct {
+	repository.SessionRepositoryInterface
+	sessions []models.Session
+}
+
+func (r *fakeSessionRepo) CreateSessionTx(data *models.Session, _ *gorm.DB) error {
+	r.sessions = append(r.sessions, *data)
+	return nil
+}
+
+type fakeAuthRepo struct {
+	repository.AuthRepositoryInterface
+}
+
+func (r *fakeAuthRepo) CreateRefreshTokenTx(*models.RefreshToken, *gorm.DB) error {
+	return nil
+}
+
+type fakeOutboxRepo struct {
+	repository.OutboxRepositoryInterface
+	messages []models.OutboxMessage
+}
+
+func (r *fakeOutboxRepo) CreateOutboxMessagesTx(messages []models.OutboxMessage, _ *gorm.DB) error {
+	r.messages = append(r.messages, messages...)
+	return nil
+}
+
+type fakeLoginThrottleRepo struct {
+	repository.LoginThrottleRepositoryInterface
+	throttles map[string]*models.LoginThrottle
+}
+
+func (r *fakeLoginThrottleRepo) GetBlockedLoginThrottles(keys []string, now time.Time) ([]models.LoginThrottle, error) {
+	var blocked []models.LoginThrottle
+	for _, key := range keys {
+		if throttle, ok := r.throttles[key]; ok && throttle.BlockedUntil != nil && throttle.BlockedUntil.After(now) {
+			blocked = append(blocked, *throttle)
+		}
+	}
+	return blocked, nil
+}
+
+func (r *fakeLoginThrottleRepo) LockLoginThrottleTx(key string, _ *gorm.DB) (*models.LoginThrottle, error) {
+	throttle, ok := r.throttles[key]
+	if !ok {
+		throttle = &models.LoginThrottle{Key: key, LastFailedAt: time.Now()}
+		r.throttles[key] = throttle
+	}
+	copied := *throttle
+	return &copied, nil
+}
+
+func (r *fakeLoginThrottleRepo) SaveLoginThrottleTx(throttle *models.LoginThrottle, _ *gorm.DB) error {
+	copied := *throttle
+	r.throttles[throttle.Key] = &copied
+	return nil
+}
+
+func (r *fakeLoginThrottleRepo) DeleteLoginThrottle(key string) error {
+	delete(r.throttles, key)
+	return nil
+}
+
+type fakeUserTokenRepo struct {
+	repository.UserTokenRepositoryInterface
+	tokens map[string]*models.UserToken
+}
+
+func (r *fakeUserTokenRepo) GetUserToken(purpose models.UserTokenPurpose, tokenHash string) (*models.UserToken, error) {
+	if token, ok := r.tokens[tokenHash]; ok && token.Purpose == purpose {
+		copied := *token
+		return &copied, nil
+	}
+	return &models.UserToken{}, nil
+}
+
+func (r *fakeUserTokenRepo) CreateUserTokenTx(data *models.UserToken, _ *gorm.DB) error {
+	data.ID = uint(len(r.tokens) + 1)
+	copied := *data
+	r.tokens[data.TokenHash] = &copied
+	return nil
+}
+
+func (r *fakeUserTokenRepo) UseUserTokenTx(data *models.UserToken, _ *gorm.DB) (bool, error) {
+	token, ok := r.tokens[data.TokenHash]
+	if !ok || token.UsedAt != nil {
+		return false, nil
+	}
+	now := time.Now()
+	token.UsedAt = &now
+	data.UsedAt = &now
+	return true, nil
+}
+
+type testService struct {
+	*authService
+	users      *fakeUserRepo
+	mfa        *fakeMFARepo
+	identities *fakeIdentityRepo
+	carts      *fakeCartRepo
+	sessions   *fakeSessionRepo
+	outbox     *fakeOutboxRepo
+	throttles  *fakeLoginThrottleRepo
+	userTokens *fakeUserTokenRepo
+}
+
+func newTestService(t *testing.T, cfg *config.Config) *testService {
+	t.Helper()
+
+	cfg.JWT.KeysDir = t.TempDir()
+	log := zerolog.Nop()
+	tokens, err := utils.NewTokenManager(&cfg.JWT, gin.TestMode, &log)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ts := &testService{
+		users:      &fakeUserRepo{users: map[uint]*models.User{}},
+		mfa:        &fakeMFARepo{totps: map[uint]*models.UserTOTP{}, recoveryCodes: map[uint]map[string]bool{}},
+		identities: &fakeIdentityRepo{states: map[string]*models.OIDCLoginState{}},
+		carts:      &fakeCartRepo{},
+		sessions:   &fakeSessionRepo{},
+		outbox:     &fakeOutboxRepo{},
+		throttles:  &fakeLoginThrottleRepo{throttles: map[string]*models.LoginThrottle{}},
+		userTokens: &fakeUserTokenRepo{tokens: map[string]*models.UserToken{}},
+	}
+	ts.authService = &authService{
+		db:                newFakeDB(t),
+		cfg:               cfg,
+		log:               &log,
+		tokens:            tokens,
+		userRepo:          ts.users,
+		mfaRepo:           ts.mfa,
+		identityRepo:      ts.identities,
+		cartRepo:          ts.carts,
+		sessionRepo:       ts.sessions,
+		authRepo:          &fakeAuthRepo{},
+		outboxRepo:        ts.outbox,
+		loginThrottleRepo: ts.throttles,
+		userTokenRepo:     ts.userTokens,
+		identityProviders: newIdentityRegistry(&cfg.OIDC),
+	}
+	return ts
+}
@@ -0,0 +1,514 @@
+package importService
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
+)
+
+// ErrInvalidImportFile is returned when an uploaded file cannot be imported at all,
+// as opposed to individual rows failing validation.
+var ErrInvalidImportFile = errors.New("invalid import file")
+
+const (
+	maxImportRows         = 50000
+	maxReportedErrors     = 1000
+	progressInterval      = 50
+	progressSaveInterval  = 30 * time.Second
+	exportBatchSize       = 500
+	attributeColumnPrefix = "attr:"
+	dateFormat            = "2006-01-02 15:04:05"
+	formulaPrefixes       = "=+-@"
+)
+
+// Columns in export order. Attribute columns follow as attr:<key>.
+var productColumns = []string{"sku", "name", "description", "category", "price", "stock", "is_active"}
+
+var requiredColumns = []string{"sku", "name", "category", "price", "stock"}
+
+type ImportServiceInterface interface {
+	StartProductImport(userID uint, fileName string, file io.Reader) (*dto.ImportJobResponse, error)
+	GetImportJob(jobID uint) (*dto.ImportJobResponse, error)
+	ExportProducts(w io.Writer) error
+}
+
+type importService struct {
+	db          *gorm.DB
+	log         *zerolog.Logger
+	worker      *Worker
+	products    productService.ProductServiceInterface
+	importRepo  repository.ImportRepositoryInterface
+	productRepo repository.ProductRepositoryInterface
+}
+
+func New(db *gorm.DB, log *zerolog.Logger, worker *Worker, products productService.ProductServiceInterface) ImportServiceInterface {
+	return &importService{
+		db:          db,
+		log:         log,
+		worker:      worker,
+		products:    products,
+		importRepo:  repository.NewImportRepo(db),
+		productRepo: repository.NewProductRepo(db),
+	}
+}
+
+// StartProductImport validates the file layout, records an import job and
+// processes the rows in the background on the worker. Progress is read with
+// GetImportJob.
+func (s *importService) StartProductImport(userID uint, fileName string, file io.Reader) (*dto.ImportJobResponse, error) {
+	columns, rows, err := readImportFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImportFile, err)
+	}
+
+	job := models.ImportJob{
+		UserID:    userID,
+		FileName:  fileName,
+		Status:    models.ImportJobStatusPending,
+		TotalRows: len(rows),
+	}
+
+	if err := s.importRepo.CreateImportJob(&job); err != nil {
+		return nil, err
+	}
+
+	response := generateImportJobResponse(&job)
+
+	s.worker.Go(func(ctx context.Context) {
+		s.runProductImport(ctx, &job, columns, rows)
+	})
+
+	return response, nil
+}
+
+func (s *importService) GetImportJob(jobID uint) (*dto.ImportJobResponse, error) {
+	job, err := s.importRepo.GetImportJobById(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	return generateImportJobResponse(job), nil
+}
+
+func (s *importService) ExportProducts(w io.Writer) error {
+	attributes, err := s.productRepo.GetAllCategoryAttributes()
+	if err != nil {
+		return err
+	}
+
+	var attributeKeys []string
+	for i := range attributes {
+		if !slices.Contains(attributeKeys, attributes[i].Key) {
+			attributeKeys = append(attributeKeys, attributes[i].Key)
+		}
+	}
+	slices.Sort(attributeKeys)
+
+	header := slices.Clone(productColumns)
+	for _, key := range attributeKeys {
+		header = append(header, attributeColumnPrefix+key)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	return s.productRepo.GetProductsInBatches(exportBatchSize, func(products []models.Product) error {
+		for i := range products {
+			product := &products[i]
+			record := []string{
+				escapeCell(product.SKU),
+				escapeCell(product.Name),
+				escapeCell(product.Description),
+				escapeCell(product.Category.Name),
+				strconv.FormatFloat(product.Price, 'f', -1, 64),
+				strconv.Itoa(product.Stock),
+				strconv.FormatBool(product.IsActive),
+			}
+			for _, key := range attributeKeys {
+				record = append(record, formatAttributeValue(product.Attributes[key]))
+			}
+
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+
+		writer.Flush()
+		return writer.Error()
+	})
+}
+
+// importRun caches lookups shared by every row of one import.
+type importRun struct {
+	categories map[string]*models.Category
+	schemas    map[uint][]models.CategoryAttribute
+}
+
+// runProductImport imports the rows until done or ctx is cancelled. Rows
+// imported before a cancellation stay; uploading the file again updates them.
+func (s *importService) runProductImport(ctx context.Context, job *models.ImportJob, columns map[string]int, rows [][]string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.finishImportJob(job, models.ImportJobStatusFailed, fmt.Sprintf("import aborted: %v", r))
+		}
+	}()
+
+	startedAt := time.Now()
+	job.Status = models.ImportJobStatusProcessing
+	job.StartedAt = &startedAt
+	s.saveImportJob(job)
+	savedAt := startedAt
+
+	categories, err := s.productRepo.GetAllCategories()
+	if err != nil {
+		s.finishImportJob(job, models.ImportJobStatusFailed, err.Error())
+		return
+	}
+
+	run := &importRun{
+		categories: make(map[string]*models.Category, len(categories)),
+		schemas:    map[uint][]models.CategoryAttribute{},
+	}
+	for i := range categories {
+		run.categories[strings.ToLower(categories[i].Name)] = &categories[i]
+	}
+
+	for i, row := range rows {
+		if ctx.Err() != nil {
+			s.finishImportJob(job, models.ImportJobStatusFailed, interruptedMessage)
+			return
+		}
+
+		record := make(map[string]string, len(columns))
+		for column, index := range columns {
+			record[column] = unescapeCell(strings.TrimSpace(row[index]))
+		}
+
+		// Line numbers are 1-based and the header occupies line 1.
+		created, rowErrors := s.importProductRow(run, i+2, record)
+
+		job.ProcessedRows++
+		switch {
+		case len(rowErrors) > 0:
+			job.FailedCount++
+			for _, rowError := range rowErrors {
+				if len(job.Errors) < maxReportedErrors {
+					job.Errors = append(job.Errors, rowError)
+				}
+			}
+		case created:
+			job.CreatedCount++
+		default:
+			job.UpdatedCount++
+		}
+
+		// Saving often enough also tells the worker the import is alive.
+		if job.ProcessedRows%progressInterval == 0 || time.Since(savedAt) >= progressSaveInterval {
+			s.saveImportJob(job)
+			savedAt = time.Now()
+		}
+	}
+
+	s.finishImportJob(job, models.ImportJobStatusCompleted, "")
+}
+
+func (s *importService) importProductRow(run *importRun, line int, record map[string]string) (created bool, rowErrors []models.ImportRowError) {
+	sku := record["sku"]
+	fail := func(field, message string) {
+		rowErrors = append(rowErrors, models.ImportRowError{Row: line, SKU: sku, Field: field, Message: message})
+	}
+
+	if sku == "" {
+		fail("sku", "sku is required")
+		return false, rowErrors
+	}
+
+	product, err := s.productRepo.GetProductBySKU(sku)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		fail("sku", err.Error())
+		return false, rowErrors
+	}
+
+	created = errors.Is(err, gorm.ErrRecordNotFound)
+	if created {
+		product = &models.Product{SKU: sku, IsActive: true}
+	}
+
+	// Empty cells keep the current value of an existing product.
+	if name := record["name"]; name != "" {
+		product.Name = name
+	} else if created {
+		fail("name", "name is required")
+	}
+
+	if description := record["description"]; description != "" {
+		product.Description = description
+	}
+
+	if categoryName := record["category"]; categoryName != "" {
+		if category, ok := run.categories[strings.ToLower(categoryName)]; ok {
+			product.CategoryID = category.ID
+		} else {
+			fail("category", fmt.Sprintf("category %q does not exist", categoryName))
+		}
+	} else if created {
+		fail("category", "category is required")
+	}
+
+	if raw := record["price"]; raw != "" {
+		price, parseErr := strconv.ParseFloat(raw, 64)
+		if parseErr != nil || price < 0 {
+			fail("price", "price must be a non-negative number")
+		} else {
+			product.Price = price
+		}
+	} else if created {
+		fail("price", "price is required")
+	}
+
+	if raw := record["stock"]; raw != "" {
+		stock, parseErr := strconv.Atoi(raw)
+		if parseErr != nil || stock < 0 {
+			fail("stock", "stock must be a non-negative integer")
+		} else {
+			product.Stock = stock
+		}
+	} else if created {
+		fail("stock", "stock is required")
+	}
+
+	if raw := record["is_active"]; raw != "" {
+		isActive, parseErr := strconv.ParseBool(raw)
+		if parseErr != nil {
+			fail("is_active", "is_active must be true or false")
+		} else {
+			product.IsActive = isActive
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return created, rowErrors
+	}
+
+	schema, err := s.categorySchema(run, product.CategoryID)
+	if err != nil {
+		fail("category", err.Error())
+		return created, rowErrors
+	}
+
+	values := map[string]any{}
+	for i := range schema {
+		if value, ok := product.Attributes[schema[i].Key]; ok {
+			values[schema[i].Key] = value
+		}
+	}
+
+	for column, raw := range record {
+		key, isAttribute := strings.CutPrefix(column, attributeColumnPrefix)
+		if !isAttribute || raw == "" {
+			continue
+		}
+
+		index := slices.IndexFunc(schema, func(attribute models.CategoryAttribute) bool {
+			return attribute.Key == key
+		})
+		if index < 0 {
+			fail(column, fmt.Sprintf("attribute %q is not defined for this category", key))
+			continue
+		}
+
+		value, parseErr := productService.ParseAttributeValue(&schema[index], raw)
+		if parseErr != nil {
+			fail(column, parseErr.Error())
+			continue
+		}
+		values[key] = value
+	}
+
+	if len(rowErrors) > 0 {
+		return created, rowErrors
+	}
+
+	attributes, err := productService.ValidateAttributes(schema, values)
+	if err != nil {
+		fail("attributes", err.Error())
+		return created, rowErrors
+	}
+	product.Attributes = attributes
+
+	// Saved through the product service, so price and stock changes publish
+	// the same events and wishlist alerts as an edit in the admin API.
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.products.SaveProductTx(product, tx)
+	})
+	if err != nil {
+		fail("", err.Error())
+	}
+
+	return created, rowErrors
+}
+
+// Helper
+func (s *importService) categorySchema(run *importRun, categoryID uint) ([]models.CategoryAttribute, error) {
+	if schema, ok := run.schemas[categoryID]; ok {
+		return schema, nil
+	}
+
+	schema, err := s.productRepo.GetCategoryAttributes(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	run.schemas[categoryID] = schema
+	return schema, nil
+}
+
+func (s *importService) finishImportJob(job *models.ImportJob, status models.ImportJobStatus, message string) {
+	finishedAt := time.Now()
+	job.Status = status
+	job.Message = message
+	job.FinishedAt = &finishedAt
+	s.saveImportJob(job)
+
+	s.log.Info().
+		Uint("job_id", job.ID).
+		Str("status", string(status)).
+		Int("created", job.CreatedCount).
+		Int("updated", job.UpdatedCount).
+		Int("failed", job.FailedCount).
+		Msg("Product import finished")
+}
+
+func (s *importService) saveImportJob(job *models.ImportJob) {
+	if err := s.importRepo.UpdateImportJob(job); err != nil {
+		s.log.Error().Err(err).Uint("job_id", job.ID).Msg("Failed to save import job")
+	}
+}
+
+func readImportFile(file io.Reader) (columns map[string]int, rows [][]string, err error) {
+	reader := csv.NewReader(file)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(records) < 2 {
+		return nil, nil, errors.New("file must contain a header and at least one row")
+	}
+
+	if len(records)-1 > maxImportRows {
+		return nil, nil, fmt.Errorf("file exceeds the limit of %d rows", maxImportRows)
+	}
+
+	columns = make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		// Spreadsheet exports often prefix the file with a byte order mark.
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		if !slices.Contains(productColumns, name) && !strings.HasPrefix(name, attributeColumnPrefix) {
+			return nil, nil, fmt.Errorf("unknown column %q", name)
+		}
+		if _, ok := columns[name]; ok {
+			return nil, nil, fmt.Errorf("duplicate column %q", name)
+		}
+		columns[name] = i
+	}
+
+	for _, name := range requiredColumns {
+		if _, ok := columns[name]; !ok {
+			return nil, nil, fmt.Errorf("missing column %q", name)
+		}
+	}
+
+	return columns, records[1:], nil
+}
+
+func formatAttributeValue(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeCell(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// escapeCell stops spreadsheets from running exported text as a formula by
+// prefixing cells that start like one with a quote. unescapeCell undoes it,
+// so exported files import back unchanged.
+func escapeCell(value string) string {
+	if value != "" && strings.ContainsRune(formulaPrefixes, rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func unescapeCell(value string) string {
+	if len(value) > 1 && value[0] == '\'' && strings.ContainsRune(formulaPrefixes, rune(value[1])) {
+		return value[1:]
+	}
+	return value
+}
+
+func generateImportJobResponse(job *models.ImportJob) *dto.ImportJobResponse {
+	rowErrors := make([]dto.ImportRowErrorResponse, len(job.Errors))
+	for i := range job.Errors {
+		rowErrors[i] = dto.ImportRowErrorResponse{
+			Row:     job.Errors[i].Row,
+			SKU:     job.Errors[i].SKU,
+			Field:   job.Errors[i].Field,
+			Message: job.Errors[i].Message,
+		}
+	}
+
+	var progress float64
+	if job.TotalRows > 0 {
+		progress = float64(job.ProcessedRows) / float64(job.TotalRows) * 100
+	}
+
+	response := &dto.ImportJobResponse{
+		ID:            job.ID,
+		Status:        string(job.Status),
+		FileName:      job.FileName,
+		TotalRows:     job.TotalRows,
+		ProcessedRows: job.ProcessedRows,
+		Progress:      progress,
+		CreatedCount:  job.CreatedCount,
+		UpdatedCount:  job.UpdatedCount,
+		FailedCount:   job.FailedCount,
+		Errors:        rowErrors,
+		Message:       job.Message,
+		CreatedAt:     job.CreatedAt.Format(dateFormat),
+	}
+
+	if job.StartedAt != nil {
+		response.StartedAt = job.StartedAt.Format(dateFormat)
+	}
+	if job.FinishedAt != nil {
+		response.FinishedAt = job.FinishedAt.Format(dateFormat)
+	}
+
+	return response
+}
@@ -0,0 +1,108 @@
+package importService
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
+
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+)
+
+const (
+	// staleImportAfter is how long an unfinished import may go without saving
+	// progress before it is considered abandoned by a stopped server. Running
+	// imports save at least every progressSaveInterval.
+	staleImportAfter    = 5 * time.Minute
+	staleImportInterval = time.Minute
+	interruptedMessage  = "import interrupted by a server restart, upload the file again"
+)
+
+// Worker runs product imports in the background of the API process. On
+// shutdown the running imports stop between rows and are marked failed;
+// imports left unfinished by a server that crashed are failed by Run.
+type Worker struct {
+	importRepo repository.ImportRepositoryInterface
+	log        *zerolog.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu      sync.Mutex
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+func NewWorker(db *gorm.DB, log *zerolog.Logger) *Worker {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Worker{
+		importRepo: repository.NewImportRepo(db),
+		log:        log,
+		ctx:        ctx,
+		cancel:     cancel,
+	}
+}
+
+// Run fails abandoned imports at start and then every staleImportInterval,
+// until Shutdown.
+func (w *Worker) Run() {
+	ticker := time.NewTicker(staleImportInterval)
+	defer ticker.Stop()
+
+	for {
+		failed, err := w.importRepo.FailStaleImportJobs(time.Now().Add(-staleImportAfter), interruptedMessage)
+		if err != nil {
+			w.log.Error().Err(err).Msg("Failed to fail abandoned import jobs")
+		} else if failed > 0 {
+			w.log.Warn().Int64("failed", failed).Msg("Failed abandoned import jobs")
+		}
+
+		select {
+		case <-w.ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Go runs fn in the background with a context cancelled on Shutdown. Once
+// the worker is stopped, fn runs right away with the cancelled context.
+func (w *Worker) Go(fn func(ctx context.Context)) {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		fn(w.ctx)
+		return
+	}
+	w.wg.Add(1)
+	w.mu.Unlock()
+
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+	}()
+}
+
+// Shutdown stops the running imports and waits until they have recorded it,
+// or until ctx ends.
+func (w *Worker) Shutdown(ctx context.Context) error {
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
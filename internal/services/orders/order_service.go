@@ -1,12 +1,17 @@
 package orderService
 
 import (
+	"cmp"
 	"errors"
 	"fmt"
+	"slices"
 
+	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/events"
 	"github.com/anzhy11/go-e-commerce/internal/models"
 	"github.com/anzhy11/go-e-commerce/internal/repository"
+	productService "github.com/anzhy11/go-e-commerce/internal/services/products"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"gorm.io/gorm"
 )
@@ -15,17 +20,44 @@ type OrderServiceInterface interface {
 	CreateOrder(userId uint) (*dto.OrderResponse, error)
 	GetOrders(userId uint, page, limit int) ([]dto.OrderResponse, *utils.PaginatedMeta, error)
 	GetOrder(userId, orderId uint) (*dto.OrderResponse, error)
+	UpdateOrderStatus(actorID, orderID uint, data *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error)
+	CancelOrder(userId, orderId uint) (*dto.OrderResponse, error)
 }
 
 type orderService struct {
-	orderRepo repository.OrderRepositoryInterface
+	db         *gorm.DB
+	cfg        *config.Config
+	products   productService.ProductServiceInterface
+	orderRepo  repository.OrderRepositoryInterface
+	userRepo   repository.UserRepositoryInterface
+	outboxRepo repository.OutboxRepositoryInterface
+}
+
+var (
+	ErrOrderNotFound           = errors.New("order not found")
+	ErrCartEmpty               = errors.New("cart is empty")
+	ErrInsufficientStock       = errors.New("insufficient stock")
+	ErrInvalidStatusTransition = errors.New("invalid order status transition")
+	ErrEmailNotVerified        = errors.New("email must be verified before checkout")
+)
+
+// orderTransitions lists the statuses an order can move to from each status.
+var orderTransitions = map[models.OrderStatus][]models.OrderStatus{
+	models.OrderStatusPending:   {models.OrderStatusConfirmed, models.OrderStatusCancelled},
+	models.OrderStatusConfirmed: {models.OrderStatusShipped, models.OrderStatusCancelled},
+	models.OrderStatusShipped:   {models.OrderStatusDelivered},
 }
 
 const dateFormat = "2006-01-02 15:04:05"
 
-func New(db *gorm.DB) OrderServiceInterface {
+func New(db *gorm.DB, cfg *config.Config, products productService.ProductServiceInterface) OrderServiceInterface {
 	return &orderService{
-		orderRepo: repository.NewOrderRepo(db),
+		db:         db,
+		cfg:        cfg,
+		products:   products,
+		orderRepo:  repository.NewOrderRepo(db),
+		userRepo:   repository.NewUserRepo(db),
+		outboxRepo: repository.NewOutboxRepo(db),
 	}
 }
 
@@ -75,7 +107,15 @@ func (s *orderService) GetOrder(userId, orderId uint) (*dto.OrderResponse, error
 }
 
 func (s *orderService) CreateOrder(userId uint) (*dto.OrderResponse, error) {
-	var orderResponse *dto.OrderResponse
+	if s.cfg.Auth.RequireVerifiedEmailForCheckout {
+		user, err := s.userRepo.GetUserById(userId)
+		if err != nil {
+			return nil, err
+		}
+		if user.EmailVerifiedAt == nil {
+			return nil, ErrEmailNotVerified
+		}
+	}
 
 	tx := s.orderRepo.BeginTx()
 	defer func() {
@@ -85,63 +125,34 @@ func (s *orderService) CreateOrder(userId uint) (*dto.OrderResponse, error) {
 		}
 	}()
 
-	cartTx, createErr := s.orderRepo.GetCartByUserIDTx(userId, tx)
-	if createErr != nil {
-		return nil, createErr
-	}
-
-	if len(cartTx.CartItems) == 0 {
-		return nil, errors.New("cart is empty")
-	}
-
-	var totalAmount float64
-	var orderItems []models.OrderItem
-
-	for i := range cartTx.CartItems {
-		cartItem := &cartTx.CartItems[i]
-
-		if cartItem.Product.Stock < cartItem.Quantity {
-			return nil, fmt.Errorf("insufficient stock for product %d", cartItem.Product.ID)
-		}
-
-		itemTotal := cartItem.Product.Price * float64(cartItem.Quantity)
-		totalAmount += itemTotal
-
-		orderItems = append(orderItems, models.OrderItem{
-			ProductID: cartItem.ProductID,
-			Quantity:  cartItem.Quantity,
-			Price:     cartItem.Product.Price,
-		})
-
-		cartItem.Product.Stock -= cartItem.Quantity
-		if updateErr := s.orderRepo.UpdateProductStockTx(&cartItem.Product, tx); updateErr != nil {
-			return nil, updateErr
-		}
-	}
-
-	order := models.Order{
-		UserID:      userId,
-		Status:      models.OrderStatusPending,
-		TotalAmount: totalAmount,
-		OrderItems:  orderItems,
-	}
-
-	if err := s.orderRepo.CreateOrderTX(&order, tx); err != nil {
+	orderResponse, err := s.createOrderTx(userId, tx)
+	if err != nil {
+		s.orderRepo.RollbackTx(tx)
 		return nil, err
 	}
 
-	if err := s.orderRepo.ClearCartTx(cartTx.ID, tx); err != nil {
+	if err := s.orderRepo.CommitTx(tx); err != nil {
 		return nil, err
 	}
 
-	orderResponse, err := s.GetOrderByIdTx(order.ID, tx)
-	if err != nil {
-		return nil, err
-	}
+	return orderResponse, nil
+}
 
-	s.orderRepo.CommitTx(tx)
+// UpdateOrderStatus moves an order to a new status on behalf of an admin.
+func (s *orderService) UpdateOrderStatus(actorID, orderID uint, data *dto.UpdateOrderStatusRequest) (*dto.OrderResponse, error) {
+	return s.changeOrderStatus(orderID, func(order *models.Order, tx *gorm.DB) error {
+		return s.transitionTx(order, models.OrderStatus(data.Status), actorID, tx)
+	})
+}
 
-	return orderResponse, nil
+// CancelOrder cancels one of the customer's own orders before it ships.
+func (s *orderService) CancelOrder(userId, orderId uint) (*dto.OrderResponse, error) {
+	return s.changeOrderStatus(orderId, func(order *models.Order, tx *gorm.DB) error {
+		if order.UserID != userId {
+			return ErrOrderNotFound
+		}
+		return s.transitionTx(order, models.OrderStatusCancelled, userId, tx)
+	})
 }
 
 func (s *orderService) GetOrderByIdTx(orderID uint, tx *gorm.DB) (*dto.OrderResponse, error) {
@@ -186,3 +197,170 @@ func (s *orderService) generateOrderResponse(order *models.Order) *dto.OrderResp
 		CreatedAt:   order.CreatedAt.Format(dateFormat),
 	}
 }
+
+// Helper
+func (s *orderService) createOrderTx(userId uint, tx *gorm.DB) (*dto.OrderResponse, error) {
+	cartTx, err := s.orderRepo.GetCartByUserIDTx(userId, tx)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(cartTx.CartItems) == 0 {
+		return nil, ErrCartEmpty
+	}
+
+	var totalAmount float64
+	var orderItems []models.OrderItem
+
+	// Each product is locked before its stock is checked, so concurrent
+	// checkouts cannot both take the last items, and prices are read under
+	// the same lock. Locking in product ID order avoids deadlocks.
+	cartItems := slices.Clone(cartTx.CartItems)
+	slices.SortFunc(cartItems, func(a, b models.CartItem) int {
+		return cmp.Compare(a.ProductID, b.ProductID)
+	})
+
+	for _, cartItem := range cartItems {
+		product, err := s.products.AdjustStockTx(cartItem.ProductID, -cartItem.Quantity, tx)
+		if err != nil {
+			// A product deleted since it was added to the cart has no stock.
+			if errors.Is(err, productService.ErrInsufficientStock) || errors.Is(err, productService.ErrProductNotFound) {
+				return nil, fmt.Errorf("%w: product %d", ErrInsufficientStock, cartItem.ProductID)
+			}
+			return nil, err
+		}
+
+		totalAmount += product.Price * float64(cartItem.Quantity)
+
+		orderItems = append(orderItems, models.OrderItem{
+			ProductID: cartItem.ProductID,
+			Quantity:  cartItem.Quantity,
+			Price:     product.Price,
+		})
+	}
+
+	order := models.Order{
+		UserID:      userId,
+		Status:      models.OrderStatusPending,
+		TotalAmount: totalAmount,
+		OrderItems:  orderItems,
+	}
+
+	if err := s.orderRepo.CreateOrderTX(&order, tx); err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.ClearCartTx(cartTx.ID, tx); err != nil {
+		return nil, err
+	}
+
+	created := &events.OrderCreated{
+		OrderID:     order.ID,
+		UserID:      order.UserID,
+		Status:      string(order.Status),
+		TotalAmount: order.TotalAmount,
+		Items:       eventOrderItems(order.OrderItems),
+	}
+
+	if err := s.publishTx([]events.Event{created}, tx); err != nil {
+		return nil, err
+	}
+
+	return s.GetOrderByIdTx(order.ID, tx)
+}
+
+// changeOrderStatus locks the order and applies change in one transaction.
+func (s *orderService) changeOrderStatus(orderID uint, change func(order *models.Order, tx *gorm.DB) error) (*dto.OrderResponse, error) {
+	var order *models.Order
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		order, err = s.orderRepo.GetOrderForUpdateTx(orderID, tx)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrOrderNotFound
+			}
+			return err
+		}
+
+		return change(order, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.generateOrderResponse(order), nil
+}
+
+// transitionTx moves order to status if the transition is allowed. Cancelled
+// orders return their items to stock.
+func (s *orderService) transitionTx(order *models.Order, status models.OrderStatus, actorID uint, tx *gorm.DB) error {
+	if !slices.Contains(orderTransitions[order.Status], status) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidStatusTransition, order.Status, status)
+	}
+
+	previousStatus := order.Status
+	order.Status = status
+
+	if err := s.orderRepo.UpdateOrderStatusTx(order, tx); err != nil {
+		return err
+	}
+
+	changes := []events.Event{&events.OrderStatusChanged{
+		OrderID:        order.ID,
+		UserID:         order.UserID,
+		PreviousStatus: string(previousStatus),
+		Status:         string(status),
+	}}
+
+	if status == models.OrderStatusCancelled {
+		// Restocking goes through the product service, so it publishes the
+		// stock events and back-in-stock alerts an admin restock would.
+		orderItems := slices.Clone(order.OrderItems)
+		slices.SortFunc(orderItems, func(a, b models.OrderItem) int {
+			return cmp.Compare(a.ProductID, b.ProductID)
+		})
+
+		for _, item := range orderItems {
+			_, err := s.products.AdjustStockTx(item.ProductID, item.Quantity, tx)
+			// Items of a deleted product have nowhere to go back to.
+			if err != nil && !errors.Is(err, productService.ErrProductNotFound) {
+				return err
+			}
+		}
+
+		changes = append(changes, &events.OrderCancelled{
+			OrderID:     order.ID,
+			UserID:      order.UserID,
+			CancelledBy: actorID,
+			TotalAmount: order.TotalAmount,
+			Items:       eventOrderItems(order.OrderItems),
+		})
+	}
+
+	return s.publishTx(changes, tx)
+}
+
+func (s *orderService) publishTx(changes []events.Event, tx *gorm.DB) error {
+	messages := make([]models.OutboxMessage, 0, len(changes))
+	for _, change := range changes {
+		message, err := events.NewOutboxMessage(s.cfg.Events.Producer, change)
+		if err != nil {
+			return err
+		}
+		messages = append(messages, *message)
+	}
+	return s.outboxRepo.CreateOutboxMessagesTx(messages, tx)
+}
+
+func eventOrderItems(items []models.OrderItem) []events.OrderItem {
+	eventItems := make([]events.OrderItem, len(items))
+	for i := range items {
+		eventItems[i] = events.OrderItem{
+			ProductID: items[i].ProductID,
+			Quantity:  items[i].Quantity,
+			Price:     items[i].Price,
+		}
+	}
+	return eventItems
+}
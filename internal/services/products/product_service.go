@@ -2,11 +2,21 @@ package productService
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
 
+	"github.com/anzhy11/go-e-commerce/internal/config"
 	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/events"
 	"github.com/anzhy11/go-e-commerce/internal/models"
 	"github.com/anzhy11/go-e-commerce/internal/repository"
+	uploadService "github.com/anzhy11/go-e-commerce/internal/services/upload"
+	"github.com/anzhy11/go-e-commerce/internal/storage"
 	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
 
@@ -15,23 +25,55 @@ type ProductServiceInterface interface {
 	GetCategories() ([]dto.CategoryResponse, error)
 	UpdateCategory(categoryID uint, data *dto.UpdateCategoryRequest) (*dto.CategoryResponse, error)
 	DeleteCategory(categoryID uint) error
+	CreateCategoryAttribute(categoryID uint, data *dto.CreateCategoryAttributeRequest) (*dto.CategoryAttributeResponse, error)
+	GetCategoryAttributes(categoryID uint) ([]dto.CategoryAttributeResponse, error)
+	UpdateCategoryAttribute(categoryID, attributeID uint, data *dto.UpdateCategoryAttributeRequest) (*dto.CategoryAttributeResponse, error)
+	DeleteCategoryAttribute(categoryID, attributeID uint) error
 	CreateProduct(data *dto.CreateProductRequest) (*dto.ProductResponse, error)
-	GetProducts(page, limit int) ([]dto.ProductResponse, *utils.PaginatedMeta, error)
+	GetProducts(filter *dto.ProductFilter, page, limit int) ([]dto.ProductResponse, *utils.PaginatedMeta, error)
 	GetProductById(productID uint) (*dto.ProductResponse, error)
 	UpdateProduct(productID uint, data *dto.UpdateProductRequest) (*dto.ProductResponse, error)
 	DeleteProduct(productID uint) error
-	AddProductImage(productID uint, url, altText string) error
+	AddProductImage(productID uint, upload *uploadService.UploadedImage, altText string) (*dto.ProductImageResponse, error)
+	GetProductImages(productID uint) ([]dto.ProductImageResponse, error)
+	UpdateProductImage(productID, imageID uint, data *dto.UpdateProductImageRequest) (*dto.ProductImageResponse, error)
+	SetPrimaryProductImage(productID, imageID uint) ([]dto.ProductImageResponse, error)
+	ReorderProductImages(productID uint, data *dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error)
+	DeleteProductImage(productID, imageID uint) error
+	SaveProductTx(product *models.Product, tx *gorm.DB) error
+	AdjustStockTx(productID uint, delta int, tx *gorm.DB) (*models.Product, error)
 }
 
 type productService struct {
-	db          *gorm.DB
-	productRepo repository.ProductRepositoryInterface
+	db           *gorm.DB
+	cfg          *config.Config
+	log          *zerolog.Logger
+	urls         *storage.URLResolver
+	productRepo  repository.ProductRepositoryInterface
+	wishlistRepo repository.WishlistRepositoryInterface
+	outboxRepo   repository.OutboxRepositoryInterface
 }
 
-func New(db *gorm.DB) ProductServiceInterface {
+var (
+	ErrProductNotFound      = errors.New("product not found")
+	ErrProductImageNotFound = errors.New("product image not found")
+	ErrImageLimitReached    = errors.New("maximum number of images reached")
+	ErrInvalidImageOrder    = errors.New("image order must list every image of the product exactly once")
+	ErrInvalidAttribute     = errors.New("invalid attribute")
+	ErrInsufficientStock    = errors.New("insufficient stock")
+)
+
+var attributeKeyPattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+func New(db *gorm.DB, cfg *config.Config, log *zerolog.Logger) ProductServiceInterface {
 	return &productService{
-		db:          db,
-		productRepo: repository.NewProductRepo(db),
+		db:           db,
+		cfg:          cfg,
+		log:          log,
+		urls:         storage.NewURLResolver(cfg),
+		productRepo:  repository.NewProductRepo(db),
+		wishlistRepo: repository.NewWishlistRepo(db),
+		outboxRepo:   repository.NewOutboxRepo(db),
 	}
 }
 
@@ -100,8 +142,91 @@ func (s *productService) DeleteCategory(categoryID uint) error {
 	return s.productRepo.DeleteCategory(categoryID)
 }
 
+// Category attributes
+func (s *productService) CreateCategoryAttribute(categoryID uint, data *dto.CreateCategoryAttributeRequest) (*dto.CategoryAttributeResponse, error) {
+	if _, err := s.productRepo.GetCategoryById(categoryID); err != nil {
+		return nil, err
+	}
+
+	if !attributeKeyPattern.MatchString(data.Key) {
+		return nil, fmt.Errorf("%w: keys must be lowercase letters, digits and underscores", ErrInvalidAttribute)
+	}
+
+	attribute := models.CategoryAttribute{
+		CategoryID:   categoryID,
+		Key:          data.Key,
+		Label:        data.Label,
+		Type:         models.AttributeType(data.Type),
+		Unit:         data.Unit,
+		Options:      data.Options,
+		IsRequired:   data.IsRequired,
+		IsFilterable: data.IsFilterable,
+		Position:     data.Position,
+	}
+
+	if err := validateAttributeOptions(&attribute); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.CreateCategoryAttribute(&attribute); err != nil {
+		return nil, err
+	}
+
+	return generateCategoryAttributeResponse(&attribute), nil
+}
+
+func (s *productService) GetCategoryAttributes(categoryID uint) ([]dto.CategoryAttributeResponse, error) {
+	attributes, err := s.productRepo.GetCategoryAttributes(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	attributeResponses := make([]dto.CategoryAttributeResponse, len(attributes))
+	for i := range attributes {
+		attributeResponses[i] = *generateCategoryAttributeResponse(&attributes[i])
+	}
+	return attributeResponses, nil
+}
+
+func (s *productService) UpdateCategoryAttribute(categoryID, attributeID uint, data *dto.UpdateCategoryAttributeRequest) (*dto.CategoryAttributeResponse, error) {
+	attribute, err := s.productRepo.GetCategoryAttributeById(categoryID, attributeID)
+	if err != nil {
+		return nil, err
+	}
+
+	if data.Label != "" {
+		attribute.Label = data.Label
+	}
+	attribute.Unit = data.Unit
+	attribute.IsRequired = data.IsRequired
+	attribute.IsFilterable = data.IsFilterable
+	attribute.Position = data.Position
+	if data.Options != nil {
+		attribute.Options = data.Options
+	}
+
+	if err := validateAttributeOptions(attribute); err != nil {
+		return nil, err
+	}
+
+	if err := s.productRepo.UpdateCategoryAttribute(attribute); err != nil {
+		return nil, err
+	}
+
+	return generateCategoryAttributeResponse(attribute), nil
+}
+
+func (s *productService) DeleteCategoryAttribute(categoryID, attributeID uint) error {
+	return s.productRepo.DeleteCategoryAttribute(categoryID, attributeID)
+}
+
 // Product
 func (s *productService) CreateProduct(data *dto.CreateProductRequest) (*dto.ProductResponse, error) {
+	attributes, err := s.validateProductAttributes(data.CategoryID, data.Attributes)
+	if err != nil {
+		return nil, err
+	}
+
 	product := models.Product{
 		Name:        data.Name,
 		Description: data.Description,
@@ -109,6 +234,7 @@ func (s *productService) CreateProduct(data *dto.CreateProductRequest) (*dto.Pro
 		Stock:       data.Stock,
 		SKU:         data.SKU,
 		CategoryID:  data.CategoryID,
+		Attributes:  attributes,
 	}
 
 	if err := s.productRepo.CreateProduct(&product); err != nil {
@@ -122,10 +248,11 @@ func (s *productService) CreateProduct(data *dto.CreateProductRequest) (*dto.Pro
 		Price:       product.Price,
 		Stock:       product.Stock,
 		CategoryID:  product.CategoryID,
+		Attributes:  product.Attributes,
 	}, nil
 }
 
-func (s *productService) GetProducts(page, limit int) ([]dto.ProductResponse, *utils.PaginatedMeta, error) {
+func (s *productService) GetProducts(filter *dto.ProductFilter, page, limit int) ([]dto.ProductResponse, *utils.PaginatedMeta, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -135,7 +262,11 @@ func (s *productService) GetProducts(page, limit int) ([]dto.ProductResponse, *u
 
 	offset := (page - 1) * limit
 
-	products, total, err := s.productRepo.GetProducts(offset, limit)
+	if err := s.resolveProductFilter(filter); err != nil {
+		return nil, nil, err
+	}
+
+	products, total, err := s.productRepo.GetProducts(filter, offset, limit)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -171,6 +302,19 @@ func (s *productService) UpdateProduct(productID uint, data *dto.UpdateProductRe
 		return nil, err
 	}
 
+	if data.Attributes != nil || data.CategoryID != product.CategoryID {
+		values := data.Attributes
+		if values == nil {
+			values = product.Attributes
+		}
+
+		attributes, attrErr := s.validateProductAttributes(data.CategoryID, values)
+		if attrErr != nil {
+			return nil, attrErr
+		}
+		product.Attributes = attributes
+	}
+
 	product.Name = data.Name
 	product.Description = data.Description
 	product.Price = data.Price
@@ -181,61 +325,608 @@ func (s *productService) UpdateProduct(productID uint, data *dto.UpdateProductRe
 		product.IsActive = data.IsActive
 	}
 
-	if err := s.productRepo.UpdateProduct(product); err != nil {
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		return s.SaveProductTx(product, tx)
+	})
+	if err != nil {
 		return nil, err
 	}
 
 	return s.generateProductResponse(product), nil
 }
 
+// SaveProductTx creates product, or updates it with the row locked and saves
+// the price and stock events and wishlist alerts of the update in tx.
+func (s *productService) SaveProductTx(product *models.Product, tx *gorm.DB) error {
+	if product.ID == 0 {
+		return s.productRepo.CreateProductTx(product, tx)
+	}
+
+	current, err := s.productRepo.GetProductForUpdateTx(product.ID, tx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+
+	if err := s.productRepo.UpdateProductTx(product, tx); err != nil {
+		return err
+	}
+	return s.saveProductChangesTx(product, current.Stock, current.Price, tx)
+}
+
+// AdjustStockTx adds delta to a product's stock with the row locked, and saves
+// the stock events and back-in-stock alerts of the change in tx. Callers
+// adjusting several products should do so in product ID order, so concurrent
+// transactions lock rows in the same order.
+func (s *productService) AdjustStockTx(productID uint, delta int, tx *gorm.DB) (*models.Product, error) {
+	product, err := s.productRepo.GetProductForUpdateTx(productID, tx)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	if product.Stock+delta < 0 {
+		return nil, fmt.Errorf("%w: product %d", ErrInsufficientStock, productID)
+	}
+
+	previousStock := product.Stock
+	product.Stock += delta
+	if err := s.productRepo.UpdateProductStockTx(product, tx); err != nil {
+		return nil, err
+	}
+
+	if err := s.saveProductChangesTx(product, previousStock, product.Price, tx); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
 func (s *productService) DeleteProduct(productID uint) error {
 	return s.productRepo.DeleteProduct(productID)
 }
 
-func (s *productService) AddProductImage(productID uint, url, altText string) error {
-	count := s.productRepo.CountProductImage(productID)
-	if count >= 10 {
-		return errors.New("maximum number of images reached")
+// AddProductImage adds an uploaded image after the others. The first image of
+// a product becomes its primary image.
+func (s *productService) AddProductImage(productID uint, upload *uploadService.UploadedImage, altText string) (*dto.ProductImageResponse, error) {
+	image := models.ProductImage{
+		ProductID:       productID,
+		StorageProvider: upload.Provider,
+		Key:             upload.Key,
+		ThumbnailKey:    upload.ThumbnailKey,
+		MediumKey:       upload.MediumKey,
+		LargeKey:        upload.LargeKey,
+		Width:           upload.Width,
+		Height:          upload.Height,
+		AltText:         altText,
 	}
 
-	image := models.ProductImage{
-		ProductID: productID,
-		URL:       url,
-		AltText:   altText,
+	// The product stays locked until the image is saved, so concurrent
+	// uploads cannot both pass the limit or both become primary.
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.productRepo.LockProductTx(productID, tx); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrProductNotFound
+			}
+			return err
+		}
+
+		count, err := s.productRepo.CountProductImagesTx(productID, tx)
+		if err != nil {
+			return err
+		}
+		if count >= int64(s.cfg.Upload.MaxImagesPerProduct) {
+			return ErrImageLimitReached
+		}
+
+		if image.Position, err = s.productRepo.NextProductImagePositionTx(productID, tx); err != nil {
+			return err
+		}
+		image.IsPrimary = count == 0
+
+		return s.productRepo.CreateProductImageTx(&image, tx)
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	if count == 0 {
-		image.IsPrimary = true
+	return s.generateProductImageResponse(&image), nil
+}
+
+func (s *productService) GetProductImages(productID uint) ([]dto.ProductImageResponse, error) {
+	if _, err := s.productRepo.GetProductById(productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	return s.getProductImages(productID)
+}
+
+func (s *productService) UpdateProductImage(productID, imageID uint, data *dto.UpdateProductImageRequest) (*dto.ProductImageResponse, error) {
+	image, err := s.getProductImage(productID, imageID)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := s.productRepo.UploadProductImage(&image); err != nil {
+	image.AltText = strings.TrimSpace(data.AltText)
+
+	if err := s.productRepo.UpdateProductImage(image); err != nil {
+		return nil, err
+	}
+
+	return s.generateProductImageResponse(image), nil
+}
+
+func (s *productService) SetPrimaryProductImage(productID, imageID uint) ([]dto.ProductImageResponse, error) {
+	if _, err := s.getProductImage(productID, imageID); err != nil {
+		return nil, err
+	}
+
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.productRepo.LockProductTx(productID, tx); err != nil {
+			return err
+		}
+		return s.productRepo.SetPrimaryProductImageTx(productID, imageID, tx)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrProductImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return s.getProductImages(productID)
+}
+
+func (s *productService) ReorderProductImages(productID uint, data *dto.ReorderProductImagesRequest) ([]dto.ProductImageResponse, error) {
+	images, err := s.productRepo.GetProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	// A partial order would leave positions ambiguous, so require all images.
+	if len(data.ImageIDs) != len(images) {
+		return nil, ErrInvalidImageOrder
+	}
+
+	remaining := make(map[uint]bool, len(images))
+	for i := range images {
+		remaining[images[i].ID] = true
+	}
+	for _, imageID := range data.ImageIDs {
+		if !remaining[imageID] {
+			return nil, ErrInvalidImageOrder
+		}
+		delete(remaining, imageID)
+	}
+
+	if err := s.productRepo.ReorderProductImages(productID, data.ImageIDs); err != nil {
+		return nil, err
+	}
+
+	return s.getProductImages(productID)
+}
+
+// DeleteProductImage removes the image. When the primary image goes, the
+// next one in order takes its place in the same transaction, so a product
+// with images always has a primary one.
+func (s *productService) DeleteProductImage(productID, imageID uint) error {
+	image, err := s.getProductImage(productID, imageID)
+	if err != nil {
 		return err
 	}
 
-	return nil
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.productRepo.LockProductTx(productID, tx); err != nil {
+			return err
+		}
+
+		if err := s.productRepo.DeleteProductImageTx(image, tx); err != nil {
+			return err
+		}
+
+		if !image.IsPrimary {
+			return nil
+		}
+
+		next, err := s.productRepo.FirstProductImageTx(productID, tx)
+		if err != nil {
+			return err
+		}
+		if next.ID == 0 {
+			return nil
+		}
+		return s.productRepo.SetPrimaryProductImageTx(productID, next.ID, tx)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrProductImageNotFound
+	}
+	return err
+}
+
+// ValidateAttributes checks attribute values against a category schema and
+// returns them normalised for storage.
+func ValidateAttributes(schema []models.CategoryAttribute, values map[string]any) (models.JSONMap, error) {
+	definitions := make(map[string]*models.CategoryAttribute, len(schema))
+	for i := range schema {
+		definitions[schema[i].Key] = &schema[i]
+	}
+
+	result := models.JSONMap{}
+	for key, value := range values {
+		attribute, ok := definitions[key]
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is unknown", ErrInvalidAttribute, key)
+		}
+
+		if value == nil {
+			continue
+		}
+
+		normalised, err := normaliseAttributeValue(attribute, value)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = normalised
+	}
+
+	for i := range schema {
+		if _, ok := result[schema[i].Key]; schema[i].IsRequired && !ok {
+			return nil, fmt.Errorf("%w: %q is required", ErrInvalidAttribute, schema[i].Key)
+		}
+	}
+
+	return result, nil
+}
+
+// ParseAttributeValue converts the text form of an attribute value, as found in
+// CSV files, into the type declared by its schema.
+func ParseAttributeValue(attribute *models.CategoryAttribute, raw string) (any, error) {
+	switch attribute.Type {
+	case models.AttributeTypeNumber:
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q must be a number", ErrInvalidAttribute, attribute.Key)
+		}
+		return number, nil
+	case models.AttributeTypeBoolean:
+		flag, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %q must be a boolean", ErrInvalidAttribute, attribute.Key)
+		}
+		return flag, nil
+	default:
+		return raw, nil
+	}
 }
 
 // Helper
+func (s *productService) getProductImage(productID, imageID uint) (*models.ProductImage, error) {
+	image, err := s.productRepo.GetProductImage(productID, imageID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductImageNotFound
+		}
+		return nil, err
+	}
+	return image, nil
+}
+
+func (s *productService) getProductImages(productID uint) ([]dto.ProductImageResponse, error) {
+	images, err := s.productRepo.GetProductImages(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.ProductImageResponse, len(images))
+	for i := range images {
+		response[i] = *s.generateProductImageResponse(&images[i])
+	}
+	return response, nil
+}
+
+// productChangeMessages builds the price and stock events for a product
+// update.
+func (s *productService) productChangeMessages(product *models.Product, previousStock int, previousPrice float64) ([]models.OutboxMessage, error) {
+	var changes []events.Event
+
+	if product.Price != previousPrice {
+		changes = append(changes, &events.ProductPriceChanged{
+			ProductID:     product.ID,
+			SKU:           product.SKU,
+			Name:          product.Name,
+			PreviousPrice: previousPrice,
+			Price:         product.Price,
+		})
+	}
+
+	changes = append(changes, events.StockEvents(events.ProductStock{
+		ProductID:     product.ID,
+		SKU:           product.SKU,
+		Name:          product.Name,
+		PreviousStock: previousStock,
+		Stock:         product.Stock,
+		Threshold:     s.cfg.Inventory.LowStockThreshold,
+	})...)
+
+	messages := make([]models.OutboxMessage, 0, len(changes))
+	for _, change := range changes {
+		message, err := events.NewOutboxMessage(s.cfg.Events.Producer, change)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *message)
+	}
+	return messages, nil
+}
+
+// saveProductChangesTx saves the events and wishlist alerts of a product
+// change in tx, so they are only sent if the change commits.
+func (s *productService) saveProductChangesTx(product *models.Product, previousStock int, previousPrice float64, tx *gorm.DB) error {
+	messages, err := s.productChangeMessages(product, previousStock, previousPrice)
+	if err != nil {
+		return err
+	}
+
+	alerts, err := s.wishlistAlerts(product, previousStock, previousPrice)
+	if err != nil {
+		return err
+	}
+
+	return s.outboxRepo.CreateOutboxMessagesTx(append(messages, alerts...), tx)
+}
+
+// wishlistAlerts builds the messages telling wishlist subscribers that a
+// product came back in stock or got cheaper. They are saved with the product
+// update, so an alert is only sent for a change that was committed.
+func (s *productService) wishlistAlerts(product *models.Product, previousStock int, previousPrice float64) ([]models.OutboxMessage, error) {
+	if !product.IsActive {
+		return nil, nil
+	}
+
+	var messages []models.OutboxMessage
+
+	if previousStock <= 0 && product.Stock > 0 {
+		subscribers, err := s.wishlistRepo.GetBackInStockSubscribers(product.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		alerts, err := s.wishlistAlertMessages(product, previousPrice, subscribers, func(alert events.WishlistAlert) events.Event {
+			return &events.WishlistBackInStock{WishlistAlert: alert}
+		})
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, alerts...)
+	}
+
+	if product.Price < previousPrice {
+		subscribers, err := s.wishlistRepo.GetPriceDropSubscribers(product.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		alerts, err := s.wishlistAlertMessages(product, previousPrice, subscribers, func(alert events.WishlistAlert) events.Event {
+			return &events.WishlistPriceDrop{WishlistAlert: alert}
+		})
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, alerts...)
+	}
+
+	return messages, nil
+}
+
+func (s *productService) wishlistAlertMessages(product *models.Product, previousPrice float64, items []models.WishlistItem, newEvent func(alert events.WishlistAlert) events.Event) ([]models.OutboxMessage, error) {
+	messages := make([]models.OutboxMessage, 0, len(items))
+	for i := range items {
+		alert := events.WishlistAlert{
+			Email:          items[i].User.Email,
+			Name:           strings.TrimSpace(items[i].User.FirstName + " " + items[i].User.LastName),
+			ProductID:      product.ID,
+			ProductName:    product.Name,
+			OldPrice:       previousPrice,
+			NewPrice:       product.Price,
+			Stock:          product.Stock,
+			UnsubscribeURL: fmt.Sprintf("%s/api/v1/users/wishlist/unsubscribe?token=%s", strings.TrimRight(s.cfg.Server.AppURL, "/"), items[i].UnsubscribeToken),
+			Locale:         items[i].User.Locale,
+		}
+
+		message, err := events.NewOutboxMessage(s.cfg.Events.Producer, newEvent(alert))
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, *message)
+	}
+	return messages, nil
+}
+
+func (s *productService) validateProductAttributes(categoryID uint, values map[string]any) (models.JSONMap, error) {
+	schema, err := s.productRepo.GetCategoryAttributes(categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ValidateAttributes(schema, values)
+}
+
+// resolveProductFilter checks the attribute filters against the category
+// schema. Only filterable attributes can be filtered on, ranges only apply to
+// numbers, and exact values are typed like the stored ones.
+func (s *productService) resolveProductFilter(filter *dto.ProductFilter) error {
+	if filter == nil || len(filter.Attributes)+len(filter.AttributeMin)+len(filter.AttributeMax) == 0 {
+		return nil
+	}
+	if filter.CategoryID == 0 {
+		return fmt.Errorf("%w: filtering on attributes needs a category", ErrInvalidAttribute)
+	}
+
+	schema, err := s.productRepo.GetCategoryAttributes(filter.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	filterable := func(key string) (*models.CategoryAttribute, error) {
+		index := slices.IndexFunc(schema, func(attribute models.CategoryAttribute) bool {
+			return attribute.Key == key
+		})
+		if index < 0 {
+			return nil, fmt.Errorf("%w: %q is unknown", ErrInvalidAttribute, key)
+		}
+		if !schema[index].IsFilterable {
+			return nil, fmt.Errorf("%w: %q is not filterable", ErrInvalidAttribute, key)
+		}
+		return &schema[index], nil
+	}
+
+	filter.AttributeMatches = map[string]any{}
+	for key, raw := range filter.Attributes {
+		attribute, err := filterable(key)
+		if err != nil {
+			return err
+		}
+
+		value, err := ParseAttributeValue(attribute, raw)
+		if err != nil {
+			return err
+		}
+		if filter.AttributeMatches[key], err = normaliseAttributeValue(attribute, value); err != nil {
+			return err
+		}
+	}
+
+	for _, bounds := range []map[string]float64{filter.AttributeMin, filter.AttributeMax} {
+		for key := range bounds {
+			attribute, err := filterable(key)
+			if err != nil {
+				return err
+			}
+			if attribute.Type != models.AttributeTypeNumber {
+				return fmt.Errorf("%w: %q is not a number", ErrInvalidAttribute, key)
+			}
+		}
+	}
+
+	return nil
+}
+
+func normaliseAttributeValue(attribute *models.CategoryAttribute, value any) (any, error) {
+	switch attribute.Type {
+	case models.AttributeTypeText:
+		text, ok := value.(string)
+		if !ok || strings.TrimSpace(text) == "" {
+			return nil, fmt.Errorf("%w: %q must be a non-empty string", ErrInvalidAttribute, attribute.Key)
+		}
+		return strings.TrimSpace(text), nil
+	case models.AttributeTypeNumber:
+		switch number := value.(type) {
+		case float64:
+			return number, nil
+		case int:
+			return float64(number), nil
+		}
+		return nil, fmt.Errorf("%w: %q must be a number", ErrInvalidAttribute, attribute.Key)
+	case models.AttributeTypeBoolean:
+		flag, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q must be a boolean", ErrInvalidAttribute, attribute.Key)
+		}
+		return flag, nil
+	case models.AttributeTypeEnum:
+		option, ok := value.(string)
+		if !ok || !slices.Contains(attribute.Options, option) {
+			return nil, fmt.Errorf("%w: %q must be one of %s", ErrInvalidAttribute, attribute.Key, strings.Join(attribute.Options, ", "))
+		}
+		return option, nil
+	default:
+		return nil, fmt.Errorf("%w: %q has unsupported type %q", ErrInvalidAttribute, attribute.Key, attribute.Type)
+	}
+}
+
+func validateAttributeOptions(attribute *models.CategoryAttribute) error {
+	if attribute.Type != models.AttributeTypeEnum {
+		attribute.Options = nil
+		return nil
+	}
+
+	if len(attribute.Options) == 0 {
+		return fmt.Errorf("%w: enum attributes require at least one option", ErrInvalidAttribute)
+	}
+	return nil
+}
+
+func generateCategoryAttributeResponse(attribute *models.CategoryAttribute) *dto.CategoryAttributeResponse {
+	return &dto.CategoryAttributeResponse{
+		ID:           attribute.ID,
+		CategoryID:   attribute.CategoryID,
+		Key:          attribute.Key,
+		Label:        attribute.Label,
+		Type:         string(attribute.Type),
+		Unit:         attribute.Unit,
+		Options:      attribute.Options,
+		IsRequired:   attribute.IsRequired,
+		IsFilterable: attribute.IsFilterable,
+		Position:     attribute.Position,
+	}
+}
+
+func (s *productService) generateProductImageResponse(image *models.ProductImage) *dto.ProductImageResponse {
+	return &dto.ProductImageResponse{
+		ID:           image.ID,
+		URL:          s.urls.URL(image.Object(image.Key)),
+		ThumbnailURL: s.urls.URL(image.Object(image.ThumbnailKey)),
+		MediumURL:    s.urls.URL(image.Object(image.MediumKey)),
+		LargeURL:     s.urls.URL(image.Object(image.LargeKey)),
+		Width:        image.Width,
+		Height:       image.Height,
+		AltText:      image.AltText,
+		IsPrimary:    image.IsPrimary,
+		Position:     image.Position,
+	}
+}
+
 func (s *productService) generateProductResponse(product *models.Product) *dto.ProductResponse {
 	images := make([]dto.ProductImageResponse, len(product.Images))
 	for i := range product.Images {
-		images[i] = dto.ProductImageResponse{
-			ID:        product.Images[i].ID,
-			URL:       product.Images[i].URL,
-			AltText:   product.Images[i].AltText,
-			IsPrimary: product.Images[i].IsPrimary,
+		images[i] = *s.generateProductImageResponse(&product.Images[i])
+	}
+
+	specifications := make([]dto.ProductSpecificationResponse, 0, len(product.Category.Attributes))
+	for i := range product.Category.Attributes {
+		attribute := &product.Category.Attributes[i]
+		value, ok := product.Attributes[attribute.Key]
+		if !ok {
+			continue
 		}
+
+		specifications = append(specifications, dto.ProductSpecificationResponse{
+			Key:   attribute.Key,
+			Label: attribute.Label,
+			Value: value,
+			Unit:  attribute.Unit,
+		})
 	}
 
 	return &dto.ProductResponse{
-		ID:          product.ID,
-		Name:        product.Name,
-		Description: product.Description,
-		Price:       product.Price,
-		Stock:       product.Stock,
-		CategoryID:  product.CategoryID,
-		SKU:         product.SKU,
-		IsActive:    product.IsActive,
+		ID:             product.ID,
+		Name:           product.Name,
+		Description:    product.Description,
+		Price:          product.Price,
+		Stock:          product.Stock,
+		CategoryID:     product.CategoryID,
+		SKU:            product.SKU,
+		IsActive:       product.IsActive,
+		Attributes:     product.Attributes,
+		Specifications: specifications,
+		AverageRating:  product.AverageRating,
+		ReviewCount:    product.ReviewCount,
 		Category: dto.CategoryResponse{
 			ID:          product.Category.ID,
 			Name:        product.Category.Name,
@@ -0,0 +1,264 @@
+package reviewService
+
+import (
+	"errors"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrReviewNotFound      = errors.New("review not found")
+	ErrNotVerifiedPurchase = errors.New("only customers who received this product can review it")
+	ErrAlreadyReviewed     = errors.New("you have already reviewed this product")
+)
+
+type ReviewServiceInterface interface {
+	GetProductReviews(productID uint, page, limit int) ([]dto.ReviewResponse, *utils.PaginatedMeta, error)
+	GetUserReview(userID, productID uint) (*dto.ReviewResponse, error)
+	CreateReview(userID, productID uint, data *dto.CreateReviewRequest) (*dto.ReviewResponse, error)
+	UpdateReview(userID, productID, reviewID uint, data *dto.UpdateReviewRequest) (*dto.ReviewResponse, error)
+	DeleteReview(userID, productID, reviewID uint) error
+	GetModerationQueue(status string, page, limit int) ([]dto.ReviewResponse, *utils.PaginatedMeta, error)
+	ModerateReview(moderatorID, reviewID uint, data *dto.ModerateReviewRequest) (*dto.ReviewResponse, error)
+}
+
+type reviewService struct {
+	reviewRepo  repository.ReviewRepositoryInterface
+	productRepo repository.ProductRepositoryInterface
+}
+
+const dateFormat = "2006-01-02 15:04:05"
+
+func New(db *gorm.DB) ReviewServiceInterface {
+	return &reviewService{
+		reviewRepo:  repository.NewReviewRepo(db),
+		productRepo: repository.NewProductRepo(db),
+	}
+}
+
+func (s *reviewService) GetProductReviews(productID uint, page, limit int) ([]dto.ReviewResponse, *utils.PaginatedMeta, error) {
+	page, limit = normalisePage(page, limit)
+
+	reviews, total, err := s.reviewRepo.GetProductReviews(productID, models.ReviewStatusApproved, (page-1)*limit, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return generateReviewResponses(reviews), generateMeta(page, limit, total), nil
+}
+
+func (s *reviewService) GetUserReview(userID, productID uint) (*dto.ReviewResponse, error) {
+	review, err := s.reviewRepo.GetUserReview(userID, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	return generateReviewResponse(review), nil
+}
+
+func (s *reviewService) CreateReview(userID, productID uint, data *dto.CreateReviewRequest) (*dto.ReviewResponse, error) {
+	if _, err := s.productRepo.GetProductById(productID); err != nil {
+		return nil, err
+	}
+
+	purchased, err := s.reviewRepo.HasDeliveredPurchase(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	if !purchased {
+		return nil, ErrNotVerifiedPurchase
+	}
+
+	if _, err := s.reviewRepo.GetUserReview(userID, productID); err == nil {
+		return nil, ErrAlreadyReviewed
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	review := models.Review{
+		ProductID: productID,
+		UserID:    userID,
+		Rating:    data.Rating,
+		Title:     data.Title,
+		Body:      data.Body,
+		Status:    models.ReviewStatusPending,
+	}
+
+	if err := s.reviewRepo.CreateReview(&review); err != nil {
+		return nil, err
+	}
+
+	return s.getReview(review.ID)
+}
+
+// UpdateReview applies the customer's edit and sends the review back to the
+// moderation queue.
+func (s *reviewService) UpdateReview(userID, productID, reviewID uint, data *dto.UpdateReviewRequest) (*dto.ReviewResponse, error) {
+	review, err := s.getOwnReview(userID, productID, reviewID)
+	if err != nil {
+		return nil, err
+	}
+
+	wasApproved := review.Status == models.ReviewStatusApproved
+
+	review.Rating = data.Rating
+	review.Title = data.Title
+	review.Body = data.Body
+	review.Status = models.ReviewStatusPending
+	review.ModerationNote = ""
+	review.ModeratedBy = nil
+	review.ModeratedAt = nil
+
+	if err := s.reviewRepo.UpdateReview(review); err != nil {
+		return nil, err
+	}
+
+	if wasApproved {
+		if err := s.reviewRepo.RefreshProductRating(productID); err != nil {
+			return nil, err
+		}
+	}
+
+	return s.getReview(review.ID)
+}
+
+func (s *reviewService) DeleteReview(userID, productID, reviewID uint) error {
+	review, err := s.getOwnReview(userID, productID, reviewID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.reviewRepo.DeleteReview(review); err != nil {
+		return err
+	}
+
+	if review.Status == models.ReviewStatusApproved {
+		return s.reviewRepo.RefreshProductRating(productID)
+	}
+	return nil
+}
+
+func (s *reviewService) GetModerationQueue(status string, page, limit int) ([]dto.ReviewResponse, *utils.PaginatedMeta, error) {
+	page, limit = normalisePage(page, limit)
+	if status == "" {
+		status = string(models.ReviewStatusPending)
+	}
+
+	reviews, total, err := s.reviewRepo.GetReviewsByStatus(models.ReviewStatus(status), (page-1)*limit, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return generateReviewResponses(reviews), generateMeta(page, limit, total), nil
+}
+
+func (s *reviewService) ModerateReview(moderatorID, reviewID uint, data *dto.ModerateReviewRequest) (*dto.ReviewResponse, error) {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Status = models.ReviewStatus(data.Status)
+	review.ModerationNote = data.Note
+	review.ModeratedBy = &moderatorID
+	review.ModeratedAt = &now
+
+	if err := s.reviewRepo.UpdateReview(review); err != nil {
+		return nil, err
+	}
+
+	if err := s.reviewRepo.RefreshProductRating(review.ProductID); err != nil {
+		return nil, err
+	}
+
+	return generateReviewResponse(review), nil
+}
+
+// Helper
+func (s *reviewService) getReview(reviewID uint) (*dto.ReviewResponse, error) {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		return nil, err
+	}
+	return generateReviewResponse(review), nil
+}
+
+func (s *reviewService) getOwnReview(userID, productID, reviewID uint) (*models.Review, error) {
+	review, err := s.reviewRepo.GetReviewById(reviewID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound
+		}
+		return nil, err
+	}
+
+	if review.UserID != userID || review.ProductID != productID {
+		return nil, ErrReviewNotFound
+	}
+
+	return review, nil
+}
+
+func normalisePage(page, limit int) (normalisedPage, normalisedLimit int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+func generateMeta(page, limit int, total int64) *utils.PaginatedMeta {
+	return &utils.PaginatedMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
+}
+
+func generateReviewResponses(reviews []models.Review) []dto.ReviewResponse {
+	reviewResponses := make([]dto.ReviewResponse, len(reviews))
+	for i := range reviews {
+		reviewResponses[i] = *generateReviewResponse(&reviews[i])
+	}
+	return reviewResponses
+}
+
+func generateReviewResponse(review *models.Review) *dto.ReviewResponse {
+	// Only the first letter of the last name is shown publicly.
+	authorName := review.User.FirstName
+	if review.User.LastName != "" {
+		authorName += " " + string([]rune(review.User.LastName)[:1]) + "."
+	}
+
+	return &dto.ReviewResponse{
+		ID:             review.ID,
+		ProductID:      review.ProductID,
+		UserID:         review.UserID,
+		AuthorName:     authorName,
+		Rating:         review.Rating,
+		Title:          review.Title,
+		Body:           review.Body,
+		Status:         string(review.Status),
+		ModerationNote: review.ModerationNote,
+		CreatedAt:      review.CreatedAt.Format(dateFormat),
+		UpdatedAt:      review.UpdatedAt.Format(dateFormat),
+	}
+}
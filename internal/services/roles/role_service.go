@@ -0,0 +1,365 @@
+package roleService
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRoleNotFound      = errors.New("role not found")
+	ErrUserNotFound      = errors.New("user not found")
+	ErrRoleExists        = errors.New("role already exists")
+	ErrInvalidRoleName   = errors.New("role names are lowercase letters, digits, - and _, starting with a letter")
+	ErrUnknownPermission = errors.New("unknown permission")
+	ErrSystemRole        = errors.New("system roles cannot be changed")
+	ErrRoleInUse         = errors.New("role is still assigned to users")
+	ErrLastAdmin         = errors.New("the last active admin cannot be given another role")
+)
+
+const dateFormat = "2006-01-02 15:04:05"
+
+var roleNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+type RoleServiceInterface interface {
+	GetPermissions() ([]dto.PermissionResponse, error)
+	GetRoles() ([]dto.RoleResponse, error)
+	GetRole(name string) (*dto.RoleResponse, error)
+	CreateRole(data *dto.CreateRoleRequest) (*dto.RoleResponse, error)
+	UpdateRole(name string, data *dto.UpdateRoleRequest) (*dto.RoleResponse, error)
+	DeleteRole(name string) error
+	AssignUserRole(actorID, userID uint, data *dto.AssignRoleRequest) (*dto.UserResponse, error)
+	HasPermissions(role string, permissions ...string) (bool, error)
+}
+
+type roleService struct {
+	db          *gorm.DB
+	cfg         *config.Config
+	roleRepo    repository.RoleRepositoryInterface
+	userRepo    repository.UserRepositoryInterface
+	sessionRepo repository.SessionRepositoryInterface
+	auditRepo   repository.AuditRepositoryInterface
+	sessions    sessionService.SessionServiceInterface
+
+	mu    sync.Mutex
+	cache map[string]cachedPermissions
+}
+
+type cachedPermissions struct {
+	permissions []string
+	expiresAt   time.Time
+}
+
+func New(db *gorm.DB, cfg *config.Config, sessions sessionService.SessionServiceInterface) RoleServiceInterface {
+	return &roleService{
+		db:          db,
+		cfg:         cfg,
+		roleRepo:    repository.NewRoleRepo(db),
+		userRepo:    repository.NewUserRepo(db),
+		sessionRepo: repository.NewSessionRepo(db),
+		auditRepo:   repository.NewAuditRepo(db),
+		sessions:    sessions,
+		cache:       map[string]cachedPermissions{},
+	}
+}
+
+func (s *roleService) GetPermissions() ([]dto.PermissionResponse, error) {
+	permissions, err := s.roleRepo.GetPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.PermissionResponse, len(permissions))
+	for i, permission := range permissions {
+		resp[i] = dto.PermissionResponse{Name: permission.Name, Description: permission.Description}
+	}
+	return resp, nil
+}
+
+func (s *roleService) GetRoles() ([]dto.RoleResponse, error) {
+	roles, err := s.roleRepo.GetRoles()
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.RoleResponse, len(roles))
+	for i := range roles {
+		resp[i] = roleResponse(&roles[i])
+	}
+	return resp, nil
+}
+
+func (s *roleService) GetRole(name string) (*dto.RoleResponse, error) {
+	role, err := s.getRole(name)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := roleResponse(role)
+	return &resp, nil
+}
+
+func (s *roleService) CreateRole(data *dto.CreateRoleRequest) (*dto.RoleResponse, error) {
+	if !roleNamePattern.MatchString(data.Name) {
+		return nil, ErrInvalidRoleName
+	}
+
+	existing, err := s.roleRepo.GetRole(data.Name)
+	if err != nil {
+		return nil, err
+	}
+	if existing.Name != "" {
+		return nil, ErrRoleExists
+	}
+
+	permissions, err := s.checkPermissions(data.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &models.Role{Name: data.Name, Description: data.Description}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.roleRepo.CreateRoleTx(role, tx); err != nil {
+			return err
+		}
+		return s.roleRepo.ReplaceRolePermissionsTx(role.Name, permissions, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetRole(role.Name)
+}
+
+// UpdateRole replaces the description and permissions of a role. The change
+// applies at once in this process and, in other API processes, once their
+// permission cache expires.
+func (s *roleService) UpdateRole(name string, data *dto.UpdateRoleRequest) (*dto.RoleResponse, error) {
+	role, err := s.getRole(name)
+	if err != nil {
+		return nil, err
+	}
+	if role.IsSystem {
+		return nil, ErrSystemRole
+	}
+
+	permissions, err := s.checkPermissions(data.Permissions)
+	if err != nil {
+		return nil, err
+	}
+
+	role.Description = data.Description
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		if err := s.roleRepo.UpdateRoleTx(role, tx); err != nil {
+			return err
+		}
+		return s.roleRepo.ReplaceRolePermissionsTx(role.Name, permissions, tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.forget(role.Name)
+	return s.GetRole(role.Name)
+}
+
+// DeleteRole deletes a role no user has.
+func (s *roleService) DeleteRole(name string) error {
+	role, err := s.getRole(name)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return ErrSystemRole
+	}
+
+	users, err := s.roleRepo.CountRoleUsers(role.Name)
+	if err != nil {
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.roleRepo.DeleteRoleTx(role.Name, tx)
+	}); err != nil {
+		return err
+	}
+
+	s.forget(role.Name)
+	return nil
+}
+
+// AssignUserRole gives a user another role and signs them out everywhere,
+// since their tokens carry the old one. The last active admin keeps the
+// role, so someone can still manage roles. The change is audited.
+func (s *roleService) AssignUserRole(actorID, userID uint, data *dto.AssignRoleRequest) (*dto.UserResponse, error) {
+	user, err := s.userRepo.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.ID == 0 {
+		return nil, ErrUserNotFound
+	}
+
+	role, err := s.getRole(data.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Role != role.Name {
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			if user.Role == string(models.RoleAdmin) {
+				admins, err := s.userRepo.LockActiveUsersWithRoleTx(user.Role, tx)
+				if err != nil {
+					return err
+				}
+				if !slices.ContainsFunc(admins, func(id uint) bool { return id != user.ID }) {
+					return ErrLastAdmin
+				}
+			}
+
+			previous := user.Role
+			user.Role = role.Name
+			if err := s.userRepo.SetRoleTx(user, tx); err != nil {
+				return err
+			}
+
+			if _, err := s.sessionRepo.RevokeUserSessionsTx(user.ID, "", tx); err != nil {
+				return err
+			}
+
+			return s.auditRepo.CreateAuditLogTx(&models.AuditLog{
+				ActorID: actorID,
+				UserID:  user.ID,
+				Action:  models.AuditUserRoleChanged,
+				Details: models.JSONMap{"from": previous, "to": role.Name},
+			}, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		s.sessions.ForgetUser(user.ID)
+	}
+
+	return &dto.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Phone:         user.Phone,
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		Locale:        user.Locale,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    user.MFAEnabledAt != nil,
+		CreatedAt:     user.CreatedAt.Format(dateFormat),
+	}, nil
+}
+
+// HasPermissions reports whether role has every one of permissions. The
+// permissions of a role are cached for Auth.PermissionCacheTTL. Changes made
+// through this service apply at once; changes made by another API process
+// take at most that long.
+func (s *roleService) HasPermissions(role string, permissions ...string) (bool, error) {
+	granted, err := s.rolePermissions(role)
+	if err != nil {
+		return false, err
+	}
+
+	for _, permission := range permissions {
+		if !slices.Contains(granted, permission) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+func (s *roleService) rolePermissions(role string) ([]string, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[role]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.permissions, nil
+	}
+
+	permissions, err := s.roleRepo.GetRolePermissions(role)
+	if err != nil {
+		return nil, err
+	}
+
+	// Roles come from verified tokens, so the cache only grows with roles
+	// that exist or existed.
+	s.mu.Lock()
+	s.cache[role] = cachedPermissions{permissions: permissions, expiresAt: now.Add(s.cfg.Auth.PermissionCacheTTL)}
+	s.mu.Unlock()
+
+	return permissions, nil
+}
+
+func (s *roleService) forget(role string) {
+	s.mu.Lock()
+	delete(s.cache, role)
+	s.mu.Unlock()
+}
+
+func (s *roleService) getRole(name string) (*models.Role, error) {
+	role, err := s.roleRepo.GetRole(name)
+	if err != nil {
+		return nil, err
+	}
+	if role.Name == "" {
+		return nil, ErrRoleNotFound
+	}
+	return role, nil
+}
+
+// checkPermissions fails with ErrUnknownPermission unless every permission
+// exists, and returns them without duplicates.
+func (s *roleService) checkPermissions(permissions []string) ([]string, error) {
+	known, err := s.roleRepo.GetPermissions()
+	if err != nil {
+		return nil, err
+	}
+
+	checked := make([]string, 0, len(permissions))
+	for _, permission := range permissions {
+		if !slices.ContainsFunc(known, func(p models.Permission) bool { return p.Name == permission }) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownPermission, permission)
+		}
+		if !slices.Contains(checked, permission) {
+			checked = append(checked, permission)
+		}
+	}
+	return checked, nil
+}
+
+func roleResponse(role *models.Role) dto.RoleResponse {
+	permissions := make([]string, len(role.Permissions))
+	for i, permission := range role.Permissions {
+		permissions[i] = permission.Permission
+	}
+
+	return dto.RoleResponse{
+		Name:        role.Name,
+		Description: role.Description,
+		IsSystem:    role.IsSystem,
+		Permissions: permissions,
+		CreatedAt:   role.CreatedAt.Format(dateFormat),
+		UpdatedAt:   role.UpdatedAt.Format(dateFormat),
+	}
+}
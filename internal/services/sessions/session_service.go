@@ -0,0 +1,211 @@
+package sessionService
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrSessionNotFound = errors.New("session not found")
+)
+
+// maxCachedSessions bounds the cache; expired entries are dropped once it is
+// reached.
+const maxCachedSessions = 10000
+
+const dateFormat = "2006-01-02 15:04:05"
+
+type SessionServiceInterface interface {
+	GetSessions(userID uint, currentSessionID string) ([]dto.SessionResponse, error)
+	RevokeSession(userID uint, sessionID string) error
+	RevokeOtherSessions(userID uint, currentSessionID string) (int64, error)
+	IsActive(userID uint, sessionID string) (bool, error)
+	Forget(sessionID string)
+	ForgetUser(userID uint)
+}
+
+type sessionService struct {
+	db          *gorm.DB
+	cfg         *config.Config
+	sessionRepo repository.SessionRepositoryInterface
+
+	mu    sync.Mutex
+	cache map[string]cachedSession
+}
+
+type cachedSession struct {
+	userID    uint
+	active    bool
+	expiresAt time.Time
+}
+
+func New(db *gorm.DB, cfg *config.Config) SessionServiceInterface {
+	return &sessionService{
+		db:          db,
+		cfg:         cfg,
+		sessionRepo: repository.NewSessionRepo(db),
+		cache:       map[string]cachedSession{},
+	}
+}
+
+func (s *sessionService) GetSessions(userID uint, currentSessionID string) ([]dto.SessionResponse, error) {
+	sessions, err := s.sessionRepo.GetActiveSessions(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := make([]dto.SessionResponse, len(sessions))
+	for i := range sessions {
+		resp[i] = dto.SessionResponse{
+			ID:         sessions[i].ID,
+			Device:     describeDevice(sessions[i].UserAgent),
+			IPAddress:  sessions[i].IPAddress,
+			UserAgent:  sessions[i].UserAgent,
+			Current:    sessions[i].ID == currentSessionID,
+			CreatedAt:  sessions[i].CreatedAt.Format(dateFormat),
+			LastUsedAt: sessions[i].LastUsedAt.Format(dateFormat),
+			ExpiresAt:  sessions[i].ExpiresAt.Format(dateFormat),
+		}
+	}
+
+	return resp, nil
+}
+
+// RevokeSession signs one of the user's devices out. Revoking the current
+// session is the same as logging out.
+func (s *sessionService) RevokeSession(userID uint, sessionID string) error {
+	session, err := s.sessionRepo.GetSession(userID, sessionID)
+	if err != nil {
+		return err
+	}
+
+	if session.ID == "" || session.RevokedAt != nil {
+		return ErrSessionNotFound
+	}
+
+	if err := s.db.Transaction(func(tx *gorm.DB) error {
+		return s.sessionRepo.RevokeSessionTx(session.ID, tx)
+	}); err != nil {
+		return err
+	}
+
+	s.Forget(session.ID)
+	return nil
+}
+
+// RevokeOtherSessions signs every device of the user out but the current one.
+func (s *sessionService) RevokeOtherSessions(userID uint, currentSessionID string) (int64, error) {
+	var revoked int64
+	err := s.db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		revoked, err = s.sessionRepo.RevokeUserSessionsTx(userID, currentSessionID, tx)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.ForgetUser(userID)
+	return revoked, nil
+}
+
+// IsActive reports whether access tokens of the session are still accepted.
+// Answers are cached for Auth.SessionCacheTTL. Revocations made through
+// Forget or ForgetUser apply at once; any other, such as one made by another
+// API instance, within that TTL.
+func (s *sessionService) IsActive(userID uint, sessionID string) (bool, error) {
+	now := time.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[sessionID]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.active, nil
+	}
+
+	active, err := s.sessionRepo.TouchActiveSession(sessionID)
+	if err != nil {
+		return false, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.cache) >= maxCachedSessions {
+		for id, entry := range s.cache {
+			if !now.Before(entry.expiresAt) {
+				delete(s.cache, id)
+			}
+		}
+	}
+	if len(s.cache) < maxCachedSessions {
+		s.cache[sessionID] = cachedSession{userID: userID, active: active, expiresAt: now.Add(s.cfg.Auth.SessionCacheTTL)}
+	}
+
+	return active, nil
+}
+
+// Forget drops the cached answer for a session, so revoking it applies at
+// once. Call it once the revocation is committed.
+func (s *sessionService) Forget(sessionID string) {
+	s.mu.Lock()
+	delete(s.cache, sessionID)
+	s.mu.Unlock()
+}
+
+// ForgetUser drops the cached answers for every session of the user, after
+// revoking all or most of them.
+func (s *sessionService) ForgetUser(userID uint) {
+	s.mu.Lock()
+	for id, entry := range s.cache {
+		if entry.userID == userID {
+			delete(s.cache, id)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// describeDevice names the browser and operating system of a user agent for
+// display, such as "Firefox on Windows".
+func describeDevice(userAgent string) string {
+	browsers := []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+		{"PostmanRuntime/", "Postman"},
+	}
+	systems := []struct{ token, name string }{
+		{"Android", "Android"},
+		{"iPhone", "iOS"},
+		{"iPad", "iPadOS"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+
+	browser := "Unknown browser"
+	for _, b := range browsers {
+		if strings.Contains(userAgent, b.token) {
+			browser = b.name
+			break
+		}
+	}
+
+	for _, system := range systems {
+		if strings.Contains(userAgent, system.token) {
+			return browser + " on " + system.name
+		}
+	}
+	return browser
+}
@@ -1,44 +1,316 @@
 package uploadService
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"mime/multipart"
-	"path/filepath"
+	"os"
 	"strings"
 	"time"
 
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
 	"github.com/anzhy11/go-e-commerce/internal/interfaces"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+	"github.com/anzhy11/go-e-commerce/pkg/imaging"
+	"github.com/rs/zerolog"
+	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidImage   = errors.New("invalid image")
+	ErrFileTooLarge   = errors.New("file is too large")
+	ErrUploadNotFound = errors.New("upload not found")
+)
+
+const (
+	dateFormat                 = "2006-01-02 15:04:05"
+	garbageCollectionBatchSize = 100
+)
+
+const (
+	RenditionThumbnail = "thumbnail"
+	RenditionMedium    = "medium"
+	RenditionLarge     = "large"
+)
+
+var productImageRenditions = []imaging.Rendition{
+	{Name: RenditionThumbnail, MaxEdge: 150},
+	{Name: RenditionMedium, MaxEdge: 600},
+	{Name: RenditionLarge, MaxEdge: 1200},
+}
+
 type UploadService struct {
-	provider interfaces.Upload
+	provider   interfaces.Upload
+	cfg        *config.UploadConfig
+	objectRepo repository.StoredObjectRepositoryInterface
 }
 
-func NewUploadService(provider interfaces.Upload) *UploadService {
+// UploadedImage references a sanitised product image and its renditions by
+// storage key. The objects are unreferenced until a product image row takes
+// them, so an upload that is never saved is left to garbage collection.
+type UploadedImage struct {
+	Provider     string
+	Key          string
+	ThumbnailKey string
+	MediumKey    string
+	LargeKey     string
+	Width        int
+	Height       int
+}
+
+type GarbageCollectionResult struct {
+	Deleted int
+	Skipped int
+	Failed  int
+}
+
+func NewUploadService(db *gorm.DB, provider interfaces.Upload, cfg *config.UploadConfig) *UploadService {
 	return &UploadService{
-		provider: provider,
+		provider:   provider,
+		cfg:        cfg,
+		objectRepo: repository.NewStoredObjectRepo(db),
+	}
+}
+
+func (s *UploadService) UploadProductImage(productID uint, file *multipart.FileHeader) (*UploadedImage, error) {
+	if s.cfg.MaxUploadSize > 0 && file.Size > s.cfg.MaxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
 	}
+	defer func() {
+		if err := src.Close(); err != nil {
+			fmt.Println("Error closing file:", err)
+		}
+	}()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.storeProductImage(productID, data)
 }
 
-func (s *UploadService) UploadProductImage(productID uint, file *multipart.FileHeader) (string, error) {
-	ext := strings.ToLower(filepath.Ext(file.Filename))
-	if !isValidImageExt(ext) {
-		return "", errors.New("invalid file type")
+// RequestProductImageUpload issues a presigned URL the client uploads the raw
+// image to. Nothing is attached to the product until the upload is confirmed.
+func (s *UploadService) RequestProductImageUpload(productID uint, data *dto.ProductImageUploadURLRequest) (*dto.ProductImageUploadURLResponse, error) {
+	if s.cfg.MaxUploadSize > 0 && data.Size > s.cfg.MaxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+
+	token, err := encryption.GenerateRandomString(16)
+	if err != nil {
+		return nil, err
 	}
 
-	randomName := fmt.Sprintf("%d%s", time.Now().Unix(), ext)
+	key := fmt.Sprintf("%s%s", incomingPrefix(productID), token)
+
+	request, err := s.provider.PresignUpload(key, data.ContentType, s.cfg.PresignExpiresIn)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.ProductImageUploadURLResponse{
+		Key:       key,
+		URL:       request.URL,
+		Method:    request.Method,
+		Headers:   request.Headers,
+		ExpiresAt: request.ExpiresAt.Format(dateFormat),
+	}, nil
+}
+
+// ConfirmProductImageUpload verifies an object uploaded through a presigned
+// URL and processes it like a regular upload. The raw object is removed
+// afterwards, whether or not it turned out to be a valid image.
+func (s *UploadService) ConfirmProductImageUpload(productID uint, key string) (*UploadedImage, error) {
+	if !strings.HasPrefix(key, incomingPrefix(productID)) || strings.Contains(key, "..") {
+		return nil, ErrUploadNotFound
+	}
+
+	info, err := s.provider.StatObject(key)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrObjectNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	defer func() {
+		_ = s.provider.DeleteFile(key)
+	}()
+
+	if s.cfg.MaxUploadSize > 0 && info.Size > s.cfg.MaxUploadSize {
+		return nil, ErrFileTooLarge
+	}
+
+	object, err := s.provider.OpenObject(key)
+	if err != nil {
+		if errors.Is(err, interfaces.ErrObjectNotFound) {
+			return nil, ErrUploadNotFound
+		}
+		return nil, err
+	}
+	defer func() {
+		if err := object.Close(); err != nil {
+			fmt.Println("Error closing file:", err)
+		}
+	}()
 
-	path := fmt.Sprintf("products/%d/%s", productID, randomName)
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, err
+	}
 
-	return s.provider.UploadFile(file, path)
+	return s.storeProductImage(productID, data)
 }
 
 func (s *UploadService) DeleteFile(filename string) error {
 	return s.provider.DeleteFile(filename)
 }
 
+// CollectGarbage deletes stored objects that no product image references.
+// Objects touched within the grace period are kept, which covers uploads that
+// are processed but not yet attached to a product.
+func (s *UploadService) CollectGarbage(gracePeriod time.Duration, dryRun bool, log *zerolog.Logger) (*GarbageCollectionResult, error) {
+	if err := s.objectRepo.RecountReferences(); err != nil {
+		return nil, err
+	}
+
+	before := time.Now().Add(-gracePeriod)
+	result := &GarbageCollectionResult{}
+
+	var afterID uint
+	for {
+		objects, err := s.objectRepo.GetUnreferencedObjects(afterID, before, garbageCollectionBatchSize)
+		if err != nil {
+			return result, err
+		}
+		if len(objects) == 0 {
+			return result, nil
+		}
+
+		for i := range objects {
+			object := &objects[i]
+			afterID = object.ID
+
+			if object.StorageProvider != s.provider.Name() {
+				log.Warn().Str("key", object.Key).Str("provider", object.StorageProvider).Msg("Skipping object stored on another provider")
+				result.Skipped++
+				continue
+			}
+
+			if dryRun {
+				log.Info().Str("key", object.Key).Msg("Would delete unreferenced object")
+				result.Deleted++
+				continue
+			}
+
+			deleted, err := s.objectRepo.DeleteUnreferencedObject(object.ID, before, func(object *models.StoredObject) error {
+				err := s.provider.DeleteFile(object.Key)
+				if errors.Is(err, os.ErrNotExist) {
+					return nil
+				}
+				return err
+			})
+			if err != nil {
+				log.Error().Err(err).Str("key", object.Key).Msg("Failed to delete unreferenced object")
+				result.Failed++
+				continue
+			}
+
+			if deleted {
+				log.Info().Str("key", object.Key).Msg("Deleted unreferenced object")
+				result.Deleted++
+			}
+		}
+	}
+}
+
 // Helper
-func isValidImageExt(ext string) bool {
-	return ext == ".png" || ext == ".jpg" || ext == ".jpeg" || ext == ".gif" || ext == ".webp"
+func (s *UploadService) storeProductImage(productID uint, data []byte) (*UploadedImage, error) {
+	processed, err := imaging.Process(data, imaging.Options{
+		MinDimension: s.cfg.MinImageDimension,
+		MaxDimension: s.cfg.MaxImageDimension,
+		Renditions:   productImageRenditions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	uploaded := &UploadedImage{
+		Provider: s.provider.Name(),
+		Width:    processed.Width,
+		Height:   processed.Height,
+	}
+
+	uploaded.Key, err = s.store(&processed.Original)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range processed.Renditions {
+		rendition := &processed.Renditions[i]
+
+		key, err := s.store(rendition)
+		if err != nil {
+			return nil, err
+		}
+
+		switch rendition.Name {
+		case RenditionThumbnail:
+			uploaded.ThumbnailKey = key
+		case RenditionMedium:
+			uploaded.MediumKey = key
+		case RenditionLarge:
+			uploaded.LargeKey = key
+		}
+	}
+
+	return uploaded, nil
+}
+
+func incomingPrefix(productID uint) string {
+	return fmt.Sprintf("incoming/products/%d/", productID)
+}
+
+// store saves an encoded image under the SHA-256 of its content. Identical
+// files share one object, so content that is already stored is not uploaded
+// again.
+func (s *UploadService) store(encoded *imaging.Encoded) (string, error) {
+	sum := sha256.Sum256(encoded.Data)
+	hash := hex.EncodeToString(sum[:])
+	key := fmt.Sprintf("objects/%s/%s/%s%s", hash[:2], hash[2:4], hash, encoded.Extension)
+
+	exists, err := s.objectRepo.TouchStoredObject(s.provider.Name(), key)
+	if err != nil {
+		return "", err
+	}
+	if exists {
+		return key, nil
+	}
+
+	if _, err := s.provider.UploadObject(bytes.NewReader(encoded.Data), key, encoded.ContentType); err != nil {
+		return "", err
+	}
+
+	if err := s.objectRepo.CreateStoredObject(&models.StoredObject{
+		StorageProvider: s.provider.Name(),
+		Key:             key,
+		ContentType:     encoded.ContentType,
+		Size:            int64(len(encoded.Data)),
+	}); err != nil {
+		return "", err
+	}
+
+	return key, nil
 }
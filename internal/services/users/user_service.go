@@ -2,75 +2,250 @@ package userService
 
 import (
 	"errors"
+	"slices"
 
 	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
 	"github.com/anzhy11/go-e-commerce/internal/repository"
+	sessionService "github.com/anzhy11/go-e-commerce/internal/services/sessions"
+	"github.com/anzhy11/go-e-commerce/internal/utils"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrUserNotFound         = errors.New("user not found")
+	ErrCannotDeactivateSelf = errors.New("you cannot deactivate your own account")
+	ErrLastAdmin            = errors.New("the last active admin cannot be deactivated")
+)
+
+const dateFormat = "2006-01-02 15:04:05"
+
 type UserServiceInterface interface {
 	GetProfile(userID uint) (*dto.UserResponse, error)
 	UpdateProfile(userID uint, data *dto.UpdateProfileRequest) (*dto.UserResponse, error)
+	GetUsers(filter *dto.UserFilter, page, limit int) ([]dto.UserResponse, *utils.PaginatedMeta, error)
+	DeactivateUser(actorID, userID uint, data *dto.DeactivateUserRequest) (*dto.UserResponse, error)
+	ReactivateUser(actorID, userID uint) (*dto.UserResponse, error)
+	GetAuditLogs(filter *dto.AuditLogFilter, page, limit int) ([]dto.AuditLogResponse, *utils.PaginatedMeta, error)
 }
 
 type userService struct {
-	db       *gorm.DB
-	userRepo repository.UserRepositoryInterface
+	db          *gorm.DB
+	userRepo    repository.UserRepositoryInterface
+	sessionRepo repository.SessionRepositoryInterface
+	auditRepo   repository.AuditRepositoryInterface
+	sessions    sessionService.SessionServiceInterface
 }
 
-func New(db *gorm.DB) UserServiceInterface {
+func New(db *gorm.DB, sessions sessionService.SessionServiceInterface) UserServiceInterface {
 	return &userService{
-		db:       db,
-		userRepo: repository.NewUserRepo(db),
+		db:          db,
+		userRepo:    repository.NewUserRepo(db),
+		sessionRepo: repository.NewSessionRepo(db),
+		auditRepo:   repository.NewAuditRepo(db),
+		sessions:    sessions,
 	}
 }
 
 func (s *userService) GetProfile(userID uint) (*dto.UserResponse, error) {
-	user, err := s.userRepo.GetUserById(userID)
+	user, err := s.getUser(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if user.ID == 0 {
-		return nil, errors.New("user not found")
-	}
-
-	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-		Role:      user.Role,
-		IsActive:  user.IsActive,
-	}, nil
+	resp := userResponse(user)
+	return &resp, nil
 }
 
 func (s *userService) UpdateProfile(userID uint, data *dto.UpdateProfileRequest) (*dto.UserResponse, error) {
-	user, err := s.userRepo.GetUserById(userID)
+	user, err := s.getUser(userID)
 	if err != nil {
 		return nil, err
 	}
 
-	if user.ID == 0 {
-		return nil, errors.New("user not found")
-	}
-
 	user.FirstName = data.FirstName
 	user.LastName = data.LastName
 	user.Phone = data.Phone
+	if data.Locale != "" {
+		user.Locale = data.Locale
+	}
 
 	if err := s.userRepo.UpdateUser(user); err != nil {
 		return nil, err
 	}
 
-	return &dto.UserResponse{
-		ID:        user.ID,
-		Email:     user.Email,
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Phone:     user.Phone,
-		Role:      user.Role,
-		IsActive:  user.IsActive,
-	}, nil
+	resp := userResponse(user)
+	return &resp, nil
+}
+
+func (s *userService) GetUsers(filter *dto.UserFilter, page, limit int) ([]dto.UserResponse, *utils.PaginatedMeta, error) {
+	page, limit = paginate(page, limit)
+
+	users, total, err := s.userRepo.GetUsers(filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]dto.UserResponse, len(users))
+	for i := range users {
+		resp[i] = userResponse(&users[i])
+	}
+
+	return resp, paginatedMeta(page, limit, total), nil
+}
+
+// DeactivateUser stops a user from signing in and signs them out of every
+// session. Their orders and data are kept. The last active admin stays
+// active, so someone can still manage roles.
+func (s *userService) DeactivateUser(actorID, userID uint, data *dto.DeactivateUserRequest) (*dto.UserResponse, error) {
+	if actorID == userID {
+		return nil, ErrCannotDeactivateSelf
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.IsActive {
+		user.IsActive = false
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			if user.Role == string(models.RoleAdmin) {
+				admins, err := s.userRepo.LockActiveUsersWithRoleTx(user.Role, tx)
+				if err != nil {
+					return err
+				}
+				if !slices.ContainsFunc(admins, func(id uint) bool { return id != user.ID }) {
+					return ErrLastAdmin
+				}
+			}
+
+			if err := s.userRepo.SetActiveTx(user, tx); err != nil {
+				return err
+			}
+
+			revoked, err := s.sessionRepo.RevokeUserSessionsTx(user.ID, "", tx)
+			if err != nil {
+				return err
+			}
+
+			return s.auditRepo.CreateAuditLogTx(&models.AuditLog{
+				ActorID: actorID,
+				UserID:  user.ID,
+				Action:  models.AuditUserDeactivated,
+				Details: models.JSONMap{"reason": data.Reason, "sessions_revoked": revoked},
+			}, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		s.sessions.ForgetUser(user.ID)
+	}
+
+	resp := userResponse(user)
+	return &resp, nil
+}
+
+// ReactivateUser lets a deactivated user sign in again.
+func (s *userService) ReactivateUser(actorID, userID uint) (*dto.UserResponse, error) {
+	user, err := s.getUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !user.IsActive {
+		user.IsActive = true
+		err = s.db.Transaction(func(tx *gorm.DB) error {
+			if err := s.userRepo.SetActiveTx(user, tx); err != nil {
+				return err
+			}
+
+			return s.auditRepo.CreateAuditLogTx(&models.AuditLog{
+				ActorID: actorID,
+				UserID:  user.ID,
+				Action:  models.AuditUserReactivated,
+			}, tx)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	resp := userResponse(user)
+	return &resp, nil
+}
+
+func (s *userService) GetAuditLogs(filter *dto.AuditLogFilter, page, limit int) ([]dto.AuditLogResponse, *utils.PaginatedMeta, error) {
+	page, limit = paginate(page, limit)
+
+	logs, total, err := s.auditRepo.GetAuditLogs(filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := make([]dto.AuditLogResponse, len(logs))
+	for i, log := range logs {
+		resp[i] = dto.AuditLogResponse{
+			ID:         log.ID,
+			Action:     string(log.Action),
+			UserID:     log.UserID,
+			ActorID:    log.ActorID,
+			ActorEmail: log.Actor.Email,
+			Details:    log.Details,
+			CreatedAt:  log.CreatedAt.Format(dateFormat),
+		}
+	}
+
+	return resp, paginatedMeta(page, limit, total), nil
+}
+
+func (s *userService) getUser(userID uint) (*models.User, error) {
+	user, err := s.userRepo.GetUserById(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.ID == 0 {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+func userResponse(user *models.User) dto.UserResponse {
+	return dto.UserResponse{
+		ID:            user.ID,
+		Email:         user.Email,
+		FirstName:     user.FirstName,
+		LastName:      user.LastName,
+		Phone:         user.Phone,
+		Role:          user.Role,
+		IsActive:      user.IsActive,
+		Locale:        user.Locale,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    user.MFAEnabledAt != nil,
+		CreatedAt:     user.CreatedAt.Format(dateFormat),
+	}
+}
+
+func paginate(page, limit int) (int, int) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 {
+		limit = 10
+	}
+	if limit > 100 {
+		limit = 100
+	}
+	return page, limit
+}
+
+func paginatedMeta(page, limit int, total int64) *utils.PaginatedMeta {
+	return &utils.PaginatedMeta{
+		Page:       page,
+		Limit:      limit,
+		Total:      total,
+		TotalPages: int((total + int64(limit) - 1) / int64(limit)),
+	}
 }
@@ -0,0 +1,188 @@
+package wishlistService
+
+import (
+	"errors"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/internal/dto"
+	"github.com/anzhy11/go-e-commerce/internal/models"
+	"github.com/anzhy11/go-e-commerce/internal/repository"
+	"github.com/anzhy11/go-e-commerce/internal/storage"
+	"github.com/anzhy11/go-e-commerce/pkg/encryption"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrWishlistItemNotFound = errors.New("product is not in your wishlist")
+	ErrAlreadyInWishlist    = errors.New("product is already in your wishlist")
+	ErrProductNotFound      = errors.New("product not found")
+	ErrInvalidUnsubscribe   = errors.New("invalid unsubscribe token")
+)
+
+type WishlistServiceInterface interface {
+	GetWishlist(userID uint) ([]dto.WishlistItemResponse, error)
+	AddToWishlist(userID uint, data *dto.AddToWishlistRequest) (*dto.WishlistItemResponse, error)
+	UpdateWishlistItem(userID, productID uint, data *dto.UpdateWishlistItemRequest) (*dto.WishlistItemResponse, error)
+	RemoveFromWishlist(userID, productID uint) error
+	Unsubscribe(token string) error
+}
+
+type wishlistService struct {
+	urls         *storage.URLResolver
+	wishlistRepo repository.WishlistRepositoryInterface
+	productRepo  repository.ProductRepositoryInterface
+}
+
+const dateFormat = "2006-01-02 15:04:05"
+
+func New(db *gorm.DB, cfg *config.Config) WishlistServiceInterface {
+	return &wishlistService{
+		urls:         storage.NewURLResolver(cfg),
+		wishlistRepo: repository.NewWishlistRepo(db),
+		productRepo:  repository.NewProductRepo(db),
+	}
+}
+
+func (s *wishlistService) GetWishlist(userID uint) ([]dto.WishlistItemResponse, error) {
+	items, err := s.wishlistRepo.GetWishlistItems(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response := make([]dto.WishlistItemResponse, len(items))
+	for i := range items {
+		response[i] = *s.generateWishlistItemResponse(&items[i])
+	}
+
+	return response, nil
+}
+
+func (s *wishlistService) AddToWishlist(userID uint, data *dto.AddToWishlistRequest) (*dto.WishlistItemResponse, error) {
+	if _, err := s.productRepo.GetProductById(data.ProductID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+
+	if _, err := s.wishlistRepo.GetWishlistItem(userID, data.ProductID); err == nil {
+		return nil, ErrAlreadyInWishlist
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	token, err := encryption.GenerateRandomString(32)
+	if err != nil {
+		return nil, err
+	}
+
+	item := &models.WishlistItem{
+		UserID:            userID,
+		ProductID:         data.ProductID,
+		NotifyBackInStock: true,
+		NotifyPriceDrop:   true,
+		UnsubscribeToken:  token,
+	}
+	if data.NotifyBackInStock != nil {
+		item.NotifyBackInStock = *data.NotifyBackInStock
+	}
+	if data.NotifyPriceDrop != nil {
+		item.NotifyPriceDrop = *data.NotifyPriceDrop
+	}
+
+	if err := s.wishlistRepo.CreateWishlistItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.getWishlistItem(userID, data.ProductID)
+}
+
+func (s *wishlistService) UpdateWishlistItem(userID, productID uint, data *dto.UpdateWishlistItemRequest) (*dto.WishlistItemResponse, error) {
+	item, err := s.wishlistRepo.GetWishlistItem(userID, productID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWishlistItemNotFound
+		}
+		return nil, err
+	}
+
+	if data.NotifyBackInStock != nil {
+		item.NotifyBackInStock = *data.NotifyBackInStock
+	}
+	if data.NotifyPriceDrop != nil {
+		item.NotifyPriceDrop = *data.NotifyPriceDrop
+	}
+
+	if err := s.wishlistRepo.UpdateWishlistItem(item); err != nil {
+		return nil, err
+	}
+
+	return s.generateWishlistItemResponse(item), nil
+}
+
+func (s *wishlistService) RemoveFromWishlist(userID, productID uint) error {
+	if err := s.wishlistRepo.DeleteWishlistItem(userID, productID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrWishlistItemNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Unsubscribe turns off every alert for the wishlist item owning the token.
+// The product stays on the wishlist.
+func (s *wishlistService) Unsubscribe(token string) error {
+	if token == "" {
+		return ErrInvalidUnsubscribe
+	}
+
+	item, err := s.wishlistRepo.GetWishlistItemByToken(token)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidUnsubscribe
+		}
+		return err
+	}
+
+	item.NotifyBackInStock = false
+	item.NotifyPriceDrop = false
+
+	return s.wishlistRepo.UpdateWishlistItem(item)
+}
+
+// Helper
+func (s *wishlistService) getWishlistItem(userID, productID uint) (*dto.WishlistItemResponse, error) {
+	item, err := s.wishlistRepo.GetWishlistItem(userID, productID)
+	if err != nil {
+		return nil, err
+	}
+	return s.generateWishlistItemResponse(item), nil
+}
+
+func (s *wishlistService) generateWishlistItemResponse(item *models.WishlistItem) *dto.WishlistItemResponse {
+	product := dto.WishlistProductResponse{
+		Name:     item.Product.Name,
+		SKU:      item.Product.SKU,
+		Price:    item.Product.Price,
+		Stock:    item.Product.Stock,
+		IsActive: item.Product.IsActive,
+		Category: item.Product.Category.Name,
+	}
+
+	for i := range item.Product.Images {
+		image := &item.Product.Images[i]
+		if image.IsPrimary || product.ImageURL == "" {
+			product.ImageURL = s.urls.URL(image.Object(image.Key))
+		}
+	}
+
+	return &dto.WishlistItemResponse{
+		ID:                item.ID,
+		ProductID:         item.ProductID,
+		Product:           product,
+		NotifyBackInStock: item.NotifyBackInStock,
+		NotifyPriceDrop:   item.NotifyPriceDrop,
+		CreatedAt:         item.CreatedAt.Format(dateFormat),
+	}
+}
@@ -0,0 +1,77 @@
+// Package storage describes files kept by an upload provider independently
+// of how they are served.
+package storage
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+)
+
+const (
+	ProviderLocal  = "local"
+	ProviderS3     = "s3"
+	ProviderMemory = "memory"
+)
+
+// Object identifies a stored file. Rows keep the key and provider rather
+// than a URL, so files can be deleted by key and served from anywhere.
+type Object struct {
+	Provider string
+	Key      string
+}
+
+// URLResolver turns stored objects into absolute public URLs. When a CDN base
+// URL is configured it fronts every provider.
+type URLResolver struct {
+	cdnBaseURL   string
+	localBaseURL string
+	s3BaseURL    string
+}
+
+func NewURLResolver(cfg *config.Config) *URLResolver {
+	return &URLResolver{
+		cdnBaseURL:   strings.TrimRight(cfg.Upload.CDNBaseURL, "/"),
+		localBaseURL: strings.TrimRight(cfg.Server.AppURL, "/") + "/uploads",
+		s3BaseURL:    s3BaseURL(&cfg.AWS),
+	}
+}
+
+// URL returns the public URL of the object, or an empty string for an empty key.
+func (r *URLResolver) URL(object Object) string {
+	if object.Key == "" {
+		return ""
+	}
+
+	base := r.cdnBaseURL
+	if base == "" {
+		switch object.Provider {
+		case ProviderS3:
+			base = r.s3BaseURL
+		default:
+			base = r.localBaseURL
+		}
+	}
+
+	return base + "/" + escapeKey(object.Key)
+}
+
+// Helper
+func s3BaseURL(cfg *config.AWSConfig) string {
+	// Custom endpoints (localstack, MinIO) are addressed path-style, matching
+	// the client configuration in the S3 provider.
+	if cfg.S3Endpoint != "" {
+		return fmt.Sprintf("%s/%s", strings.TrimRight(cfg.S3Endpoint, "/"), cfg.S3Bucket)
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com", cfg.S3Bucket, cfg.Region)
+}
+
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i := range segments {
+		segments[i] = url.PathEscape(segments[i])
+	}
+	return strings.Join(segments, "/")
+}
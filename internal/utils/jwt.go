@@ -2,59 +2,168 @@ package utils
 
 import (
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/pkg/jwks"
+	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 )
 
+// Token types, sent in the typ header. They keep a refresh token from being
+// accepted as an access token and the other way round.
+const (
+	AccessTokenType  = "at+jwt"
+	RefreshTokenType = "rt+jwt"
+)
+
+// Authentication methods, sent in the amr claim (RFC 8176).
+const (
+	AuthMethodPassword = "pwd"
+	AuthMethodOTP      = "otp"
+	// AuthMethodFederated is a sign-in with an external identity provider.
+	// RFC 8176 has no value for it; "fed" is the one in common use.
+	AuthMethodFederated = "fed"
+)
+
+var ErrInvalidToken = errors.New("invalid token")
+
 type Payload struct {
-	UserID uint   `json:"user_id"`
-	Email  string `json:"email"`
-	Role   string `json:"role"`
+	UserID      uint     `json:"user_id"`
+	Email       string   `json:"email"`
+	Role        string   `json:"role"`
+	SessionID   string   `json:"sid"`
+	AuthMethods []string `json:"amr,omitempty"`
 	jwt.RegisteredClaims
 }
 
-func GenerateTokenPair(cfg *config.Config, userID uint, email, role string) (accessToken, refreshToken string, err error) {
-	accessPayload := &Payload{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.ExpiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+// TokenManager signs tokens with the signing key of a key set and verifies
+// them with any key of the set, which lets keys be rotated without logging
+// anyone out.
+type TokenManager struct {
+	cfg  *config.JWTConfig
+	keys *jwks.KeySet
+}
+
+// NewTokenManager loads the keys in cfg.KeysDir. Outside release mode an
+// empty or missing directory is replaced by a key generated for this process
+// only, so tokens stop working on restart.
+func NewTokenManager(cfg *config.JWTConfig, ginMode string, log *zerolog.Logger) (*TokenManager, error) {
+	keys, err := jwks.LoadDir(cfg.KeysDir, cfg.SigningKeyID)
+	if err == nil {
+		return &TokenManager{cfg: cfg, keys: keys}, nil
+	}
+
+	if ginMode == gin.ReleaseMode || !errors.Is(err, jwks.ErrNoKeys) {
+		return nil, err
 	}
 
-	at := jwt.NewWithClaims(jwt.SigningMethodHS256, accessPayload)
-	accessToken, err = at.SignedString([]byte(cfg.JWT.Secret))
+	key, err := jwks.Generate(jwks.AlgEdDSA)
+	if err != nil {
+		return nil, err
+	}
+	keys, err = jwks.NewKeySet(key.ID, key)
 	if err != nil {
-		return
+		return nil, err
 	}
 
-	refreshPayload := &Payload{
-		UserID: userID,
-		Email:  email,
-		Role:   role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(cfg.JWT.RefreshTokenExpiresIn)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
-		},
+	log.Warn().Str("keys_dir", cfg.KeysDir).Msg("No JWT keys found, signing with a temporary key")
+	return &TokenManager{cfg: cfg, keys: keys}, nil
+}
+
+// JWKS returns the public keys tokens are verified with.
+func (m *TokenManager) JWKS() *jwks.Set {
+	return m.keys.JWKS()
+}
+
+// GenerateTokenPair signs an access and a refresh token for a session. Each
+// token gets a unique ID (jti). authMethods tells how the user signed in and
+// is carried over when the session is refreshed.
+func (m *TokenManager) GenerateTokenPair(userID uint, email, role, sessionID string, authMethods []string) (accessToken, refreshToken string, err error) {
+	accessToken, err = m.sign(AccessTokenType, m.cfg.Audience, m.cfg.ExpiresIn, userID, email, role, sessionID, authMethods)
+	if err != nil {
+		return "", "", err
 	}
 
-	rt := jwt.NewWithClaims(jwt.SigningMethodHS256, refreshPayload)
-	refreshToken, err = rt.SignedString([]byte(cfg.JWT.Secret))
+	refreshToken, err = m.sign(RefreshTokenType, m.refreshAudience(), m.cfg.RefreshTokenExpiresIn, userID, email, role, sessionID, authMethods)
 	if err != nil {
-		return
+		return "", "", err
 	}
 
 	return accessToken, refreshToken, nil
 }
 
-func VerifyToken(tokenString, secret string) (*Payload, error) {
-	token, err := jwt.ParseWithClaims(tokenString, &Payload{}, func(token *jwt.Token) (any, error) {
-		return []byte(secret), nil
+// VerifyAccessToken verifies a token sent to the API.
+func (m *TokenManager) VerifyAccessToken(tokenString string) (*Payload, error) {
+	return m.verify(tokenString, AccessTokenType, m.cfg.Audience)
+}
+
+// VerifyRefreshToken verifies a token sent to refresh a session.
+func (m *TokenManager) VerifyRefreshToken(tokenString string) (*Payload, error) {
+	return m.verify(tokenString, RefreshTokenType, m.refreshAudience())
+}
+
+// Refresh tokens are only ever sent back to us, so their audience is the
+// issuer rather than the services access tokens are meant for.
+func (m *TokenManager) refreshAudience() string {
+	return m.cfg.Issuer
+}
+
+func (m *TokenManager) sign(tokenType, audience string, ttl time.Duration, userID uint, email, role, sessionID string, authMethods []string) (string, error) {
+	key := m.keys.SigningKey()
+	method := jwt.GetSigningMethod(key.Algorithm)
+	if method == nil {
+		return "", fmt.Errorf("unsupported signing algorithm %s", key.Algorithm)
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(method, &Payload{
+		UserID:      userID,
+		Email:       email,
+		Role:        role,
+		SessionID:   sessionID,
+		AuthMethods: authMethods,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    m.cfg.Issuer,
+			Subject:   fmt.Sprintf("%d", userID),
+			Audience:  jwt.ClaimStrings{audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
 	})
+	token.Header["kid"] = key.ID
+	token.Header["typ"] = tokenType
+
+	return token.SignedString(key.Private)
+}
+
+func (m *TokenManager) verify(tokenString, tokenType, audience string) (*Payload, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &Payload{}, func(token *jwt.Token) (any, error) {
+		if typ, _ := token.Header["typ"].(string); typ != tokenType {
+			return nil, fmt.Errorf("%w: expected a %s token", ErrInvalidToken, tokenType)
+		}
+
+		kid, _ := token.Header["kid"].(string)
+		key := m.keys.Key(kid)
+		if key == nil {
+			return nil, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, kid)
+		}
+		// The algorithm comes from the key, never from the token alone.
+		if token.Method.Alg() != key.Algorithm {
+			return nil, fmt.Errorf("%w: key %q does not sign with %s", ErrInvalidToken, kid, token.Method.Alg())
+		}
+
+		return key.Public, nil
+	},
+		jwt.WithValidMethods([]string{jwks.AlgRS256, jwks.AlgEdDSA}),
+		jwt.WithIssuer(m.cfg.Issuer),
+		jwt.WithAudience(audience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -63,5 +172,5 @@ func VerifyToken(tokenString, secret string) (*Payload, error) {
 		return payload, nil
 	}
 
-	return nil, errors.New("invalid token")
+	return nil, ErrInvalidToken
 }
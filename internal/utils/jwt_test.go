@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anzhy11/go-e-commerce/internal/config"
+	"github.com/anzhy11/go-e-commerce/pkg/jwks"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var testJWTConfig = config.JWTConfig{
+	Issuer:                "https://shop.example.com",
+	Audience:              "shop-api",
+	ExpiresIn:             15 * time.Minute,
+	RefreshTokenExpiresIn: time.Hour,
+}
+
+func newTestTokenManager(t *testing.T, signingKeyID string, keys ...*jwks.Key) *TokenManager {
+	t.Helper()
+
+	set, err := jwks.NewKeySet(signingKeyID, keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := testJWTConfig
+	return &TokenManager{cfg: &cfg, keys: set}
+}
+
+func generateKey(t *testing.T, alg string) *jwks.Key {
+	t.Helper()
+
+	if alg == jwks.AlgRS256 {
+		// Generate makes 3072 bit keys; the minimum is enough here.
+		private, err := rsa.GenerateKey(rand.Reader, jwks.MinRSABits)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &jwks.Key{ID: "rsa", Algorithm: jwks.AlgRS256, Public: &private.PublicKey, Private: private}
+	}
+
+	key, err := jwks.Generate(alg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// publicOnly returns key as LoadDir reads a retired key: without its
+// private half.
+func publicOnly(key *jwks.Key) *jwks.Key {
+	return &jwks.Key{ID: key.ID, Algorithm: key.Algorithm, Public: key.Public}
+}
+
+// accessClaims are the claims of a valid access token.
+func accessClaims() *Payload {
+	now := time.Now()
+	return &Payload{
+		UserID:    7,
+		Email:     "jane@example.com",
+		Role:      "customer",
+		SessionID: "session",
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    testJWTConfig.Issuer,
+			Subject:   "7",
+			Audience:  jwt.ClaimStrings{testJWTConfig.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+	}
+}
+
+// forge signs claims with any method and key, setting the headers by hand.
+func forge(t *testing.T, method jwt.SigningMethod, signingKey any, kid string, claims *Payload) string {
+	t.Helper()
+
+	token := jwt.NewWithClaims(method, claims)
+	token.Header["kid"] = kid
+	token.Header["typ"] = AccessTokenType
+
+	signed, err := token.SignedString(signingKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func TestTokenPairRoundTrip(t *testing.T) {
+	for _, alg := range []string{jwks.AlgEdDSA, jwks.AlgRS256} {
+		t.Run(alg, func(t *testing.T) {
+			key := generateKey(t, alg)
+			m := newTestTokenManager(t, key.ID, key)
+
+			access, refresh, err := m.GenerateTokenPair(7, "jane@example.com", "customer", "session", []string{AuthMethodPassword})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			payload, err := m.VerifyAccessToken(access)
+			if err != nil {
+				t.Fatalf("access token: %v", err)
+			}
+			if payload.UserID != 7 || payload.SessionID != "session" || payload.Role != "customer" {
+				t.Errorf("unexpected payload %+v", payload)
+			}
+
+			if _, err := m.VerifyRefreshToken(refresh); err != nil {
+				t.Fatalf("refresh token: %v", err)
+			}
+		})
+	}
+}
+
+func TestTokenTypesAreNotInterchangeable(t *testing.T) {
+	key := generateKey(t, jwks.AlgEdDSA)
+	m := newTestTokenManager(t, key.ID, key)
+
+	access, refresh, err := m.GenerateTokenPair(7, "jane@example.com", "customer", "session", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := m.VerifyAccessToken(refresh); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("refresh token as access token: got %v, want ErrInvalidToken", err)
+	}
+	if _, err := m.VerifyRefreshToken(access); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("access token as refresh token: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestTokenTypeHeaderIsNotEnough(t *testing.T) {
+	key := generateKey(t, jwks.AlgEdDSA)
+	m := newTestTokenManager(t, key.ID, key)
+
+	// A refresh token relabelled as an access token still has the refresh
+	// audience.
+	claims := accessClaims()
+	claims.Audience = jwt.ClaimStrings{m.refreshAudience()}
+	token := forge(t, jwt.SigningMethodEdDSA, key.Private, key.ID, claims)
+
+	if _, err := m.VerifyAccessToken(token); err == nil {
+		t.Fatal("token with the refresh audience was accepted as an access token")
+	}
+}
+
+func TestAlgorithmMustMatchKey(t *testing.T) {
+	ed := generateKey(t, jwks.AlgEdDSA)
+	rs := generateKey(t, jwks.AlgRS256)
+	m := newTestTokenManager(t, ed.ID, ed, rs)
+
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    any
+		kid    string
+	}{
+		{"RS256 signature with the EdDSA kid", jwt.SigningMethodRS256, rs.Private, ed.ID},
+		{"EdDSA signature with the RS256 kid", jwt.SigningMethodEdDSA, ed.Private, rs.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := forge(t, tt.method, tt.key, tt.kid, accessClaims())
+			if _, err := m.VerifyAccessToken(token); err == nil {
+				t.Fatal("token was accepted")
+			}
+		})
+	}
+}
+
+func TestUnsignedAndSymmetricTokensAreRejected(t *testing.T) {
+	ed := generateKey(t, jwks.AlgEdDSA)
+	rs := generateKey(t, jwks.AlgRS256)
+	m := newTestTokenManager(t, ed.ID, ed, rs)
+
+	rsPublic := rs.Public.(*rsa.PublicKey)
+	tests := []struct {
+		name   string
+		method jwt.SigningMethod
+		key    any
+		kid    string
+	}{
+		{"alg none", jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, ed.ID},
+		// The classic confusion: the public key, which anyone has, used as
+		// an HMAC secret.
+		{"HS256 keyed with the EdDSA public key", jwt.SigningMethodHS256, []byte(ed.Public.(ed25519.PublicKey)), ed.ID},
+		{"HS256 keyed with the RSA modulus", jwt.SigningMethodHS256, rsPublic.N.Bytes(), rs.ID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := forge(t, tt.method, tt.key, tt.kid, accessClaims())
+			if _, err := m.VerifyAccessToken(token); err == nil {
+				t.Fatal("token was accepted")
+			}
+		})
+	}
+}
+
+func TestRotatedKeyVerifiesWhileInTheSet(t *testing.T) {
+	old := generateKey(t, jwks.AlgEdDSA)
+	current := generateKey(t, jwks.AlgEdDSA)
+
+	before := newTestTokenManager(t, old.ID, old)
+	access, refresh, err := before.GenerateTokenPair(7, "jane@example.com", "customer", "session", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After rotation the old key only verifies.
+	rotated := newTestTokenManager(t, current.ID, current, publicOnly(old))
+	if _, err := rotated.VerifyAccessToken(access); err != nil {
+		t.Errorf("access token of the rotated-out key: %v", err)
+	}
+	if _, err := rotated.VerifyRefreshToken(refresh); err != nil {
+		t.Errorf("refresh token of the rotated-out key: %v", err)
+	}
+
+	newAccess, _, err := rotated.GenerateTokenPair(7, "jane@example.com", "customer", "session", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rotated.VerifyAccessToken(newAccess); err != nil {
+		t.Errorf("access token of the new key: %v", err)
+	}
+
+	// Once the old key leaves the set, its tokens stop working.
+	retired := newTestTokenManager(t, current.ID, current)
+	if _, err := retired.VerifyAccessToken(access); !errors.Is(err, ErrInvalidToken) {
+		t.Errorf("access token of a removed key: got %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestExpiryAndIssuerAreChecked(t *testing.T) {
+	key := generateKey(t, jwks.AlgEdDSA)
+	m := newTestTokenManager(t, key.ID, key)
+
+	expired := accessClaims()
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+
+	noExpiry := accessClaims()
+	noExpiry.ExpiresAt = nil
+
+	otherIssuer := accessClaims()
+	otherIssuer.Issuer = "https://elsewhere.example.com"
+
+	tests := map[string]*Payload{
+		"expired":      expired,
+		"no expiry":    noExpiry,
+		"other issuer": otherIssuer,
+	}
+
+	for name, claims := range tests {
+		t.Run(name, func(t *testing.T) {
+			token := forge(t, jwt.SigningMethodEdDSA, key.Private, key.ID, claims)
+			if _, err := m.VerifyAccessToken(token); err == nil {
+				t.Fatal("token was accepted")
+			}
+		})
+	}
+}
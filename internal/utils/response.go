@@ -41,6 +41,14 @@ func CreatedResponse(c *gin.Context, message string, data any) {
 	})
 }
 
+func AcceptedResponse(c *gin.Context, message string, data any) {
+	c.JSON(http.StatusAccepted, Response{
+		Success: true,
+		Message: message,
+		Data:    data,
+	})
+}
+
 func Paginated(c *gin.Context, message string, data any, meta PaginatedMeta) {
 	c.JSON(http.StatusOK, PaginatedResponse{
 		Response: Response{
@@ -82,6 +90,10 @@ func NotFound(c *gin.Context, message string, err error) {
 	ErrorResponse(c, http.StatusNotFound, message, err)
 }
 
+func TooManyRequests(c *gin.Context, message string, err error) {
+	ErrorResponse(c, http.StatusTooManyRequests, message, err)
+}
+
 func InternalServerError(c *gin.Context, message string, err error) {
 	ErrorResponse(c, http.StatusInternalServerError, message, err)
 }
@@ -0,0 +1,13 @@
+package encryption
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// HashToken hashes a random token for storage. Tokens are long and random,
+// so a fast hash is enough, unlike passwords.
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,242 @@
+// Package imaging validates uploaded images and produces clean, resized
+// renditions of them. Every output is decoded and re-encoded, which drops
+// EXIF and any other metadata embedded in the original file.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/webp"
+)
+
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatWEBP Format = "webp"
+)
+
+var (
+	ErrUnsupportedFormat = errors.New("file is not a supported image")
+	ErrTooSmall          = errors.New("image dimensions are too small")
+	ErrTooLarge          = errors.New("image dimensions are too large")
+)
+
+const jpegQuality = 85
+
+// Rendition describes a resized copy of an image. The longest edge is scaled
+// down to MaxEdge; images that are already smaller are never upscaled.
+type Rendition struct {
+	Name    string
+	MaxEdge int
+}
+
+type Options struct {
+	MinDimension int
+	MaxDimension int
+	Renditions   []Rendition
+}
+
+// Encoded is a single re-encoded image ready to be stored.
+type Encoded struct {
+	Name        string
+	Data        []byte
+	ContentType string
+	Extension   string
+	Width       int
+	Height      int
+}
+
+type Result struct {
+	Source     Format
+	Width      int
+	Height     int
+	Original   Encoded
+	Renditions []Encoded
+}
+
+// Sniff detects the image format from the file content, ignoring whatever
+// name or extension the file was uploaded with.
+func Sniff(data []byte) (Format, error) {
+	switch http.DetectContentType(data) {
+	case "image/jpeg":
+		return FormatJPEG, nil
+	case "image/png":
+		return FormatPNG, nil
+	case "image/gif":
+		return FormatGIF, nil
+	case "image/webp":
+		return FormatWEBP, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+// Process validates data as an image within the configured dimensions and
+// returns the sanitised original along with every requested rendition.
+func Process(data []byte, opts Options) (*Result, error) {
+	format, err := Sniff(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check the header before decoding so oversized images are rejected
+	// without allocating their pixels.
+	cfg, err := decodeConfig(format, data)
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+	if err := checkDimensions(cfg.Width, cfg.Height, opts); err != nil {
+		return nil, err
+	}
+
+	img, err := decode(format, data)
+	if err != nil {
+		return nil, ErrUnsupportedFormat
+	}
+
+	if format == FormatJPEG {
+		img = applyOrientation(img, jpegOrientation(data))
+	}
+
+	bounds := img.Bounds()
+	result := &Result{
+		Source: format,
+		Width:  bounds.Dx(),
+		Height: bounds.Dy(),
+	}
+
+	output := outputFormat(format, img)
+
+	original, err := encode("original", img, output)
+	if err != nil {
+		return nil, err
+	}
+	result.Original = *original
+
+	for _, rendition := range opts.Renditions {
+		encoded, err := encode(rendition.Name, resize(img, rendition.MaxEdge), output)
+		if err != nil {
+			return nil, err
+		}
+		result.Renditions = append(result.Renditions, *encoded)
+	}
+
+	return result, nil
+}
+
+// Helper
+func checkDimensions(width, height int, opts Options) error {
+	if opts.MinDimension > 0 && (width < opts.MinDimension || height < opts.MinDimension) {
+		return fmt.Errorf("%w: minimum is %dx%d, got %dx%d", ErrTooSmall, opts.MinDimension, opts.MinDimension, width, height)
+	}
+	if opts.MaxDimension > 0 && (width > opts.MaxDimension || height > opts.MaxDimension) {
+		return fmt.Errorf("%w: maximum is %dx%d, got %dx%d", ErrTooLarge, opts.MaxDimension, opts.MaxDimension, width, height)
+	}
+	return nil
+}
+
+func decodeConfig(format Format, data []byte) (image.Config, error) {
+	r := bytes.NewReader(data)
+	switch format {
+	case FormatJPEG:
+		return jpeg.DecodeConfig(r)
+	case FormatPNG:
+		return png.DecodeConfig(r)
+	case FormatGIF:
+		return gif.DecodeConfig(r)
+	case FormatWEBP:
+		return webp.DecodeConfig(r)
+	default:
+		return image.Config{}, ErrUnsupportedFormat
+	}
+}
+
+func decode(format Format, data []byte) (image.Image, error) {
+	r := bytes.NewReader(data)
+	switch format {
+	case FormatJPEG:
+		return jpeg.Decode(r)
+	case FormatPNG:
+		return png.Decode(r)
+	case FormatGIF:
+		return gif.Decode(r)
+	case FormatWEBP:
+		return webp.Decode(r)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// outputFormat keeps photos as JPEG and everything that may carry
+// transparency as PNG. There is no WebP encoder in the standard library, so
+// WebP sources are mapped to one of the two.
+func outputFormat(source Format, img image.Image) Format {
+	if source == FormatJPEG {
+		return FormatJPEG
+	}
+	if source == FormatWEBP {
+		if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+			return FormatJPEG
+		}
+	}
+	return FormatPNG
+}
+
+func encode(name string, img image.Image, format Format) (*Encoded, error) {
+	var buf bytes.Buffer
+	encoded := &Encoded{
+		Name:   name,
+		Width:  img.Bounds().Dx(),
+		Height: img.Bounds().Dy(),
+	}
+
+	switch format {
+	case FormatJPEG:
+		if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality}); err != nil {
+			return nil, err
+		}
+		encoded.ContentType = "image/jpeg"
+		encoded.Extension = ".jpg"
+	default:
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, err
+		}
+		encoded.ContentType = "image/png"
+		encoded.Extension = ".png"
+	}
+
+	encoded.Data = buf.Bytes()
+	return encoded, nil
+}
+
+func resize(img image.Image, maxEdge int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	if maxEdge <= 0 || (width <= maxEdge && height <= maxEdge) {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxEdge/width)
+		width = maxEdge
+	} else {
+		width = max(1, width*maxEdge/height)
+		height = maxEdge
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+	return dst
+}
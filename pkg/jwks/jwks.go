@@ -0,0 +1,339 @@
+// Package jwks loads the keys tokens are signed with and publishes their
+// public halves as a JSON Web Key Set (RFC 7517), so other services can
+// verify tokens without sharing a secret. It also reads the key sets other
+// issuers publish.
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	AlgRS256 = "RS256"
+	AlgES256 = "ES256"
+	AlgEdDSA = "EdDSA"
+)
+
+// MinRSABits is the smallest RSA modulus accepted.
+const MinRSABits = 2048
+
+var (
+	ErrNoKeys            = errors.New("no keys found")
+	ErrNoSigningKey      = errors.New("signing key not found")
+	ErrUnsupportedKey    = errors.New("unsupported key type")
+	ErrAmbiguousSigner   = errors.New("several private keys found, a signing key id is required")
+	ErrSigningKeyPrivate = errors.New("signing key has no private key")
+)
+
+// Key is a verification key, and a signing key when Private is set. ID is
+// sent as the kid header of the tokens it signs.
+type Key struct {
+	ID        string
+	Algorithm string
+	Public    crypto.PublicKey
+	Private   crypto.Signer
+}
+
+// KeySet holds every key tokens may be verified with and the one new tokens
+// are signed with.
+type KeySet struct {
+	keys    map[string]*Key
+	signing *Key
+}
+
+// LoadDir reads every *.pem file in dir. The file name without extension is
+// the key ID. A file holds either a private key (PKCS#8, or PKCS#1 for RSA)
+// or a public key only (PKIX), which is used to verify tokens signed by a
+// retired key. signingKeyID may be empty when dir has a single private key.
+func LoadDir(dir, signingKeyID string) (*KeySet, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.pem"))
+	if err != nil {
+		return nil, err
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("%w in %s", ErrNoKeys, dir)
+	}
+
+	var keys []*Key
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		id := strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+		key, err := ParsePEM(id, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	if signingKeyID == "" {
+		for _, key := range keys {
+			if key.Private == nil {
+				continue
+			}
+			if signingKeyID != "" {
+				return nil, ErrAmbiguousSigner
+			}
+			signingKeyID = key.ID
+		}
+	}
+
+	return NewKeySet(signingKeyID, keys...)
+}
+
+// NewKeySet builds a key set signing with the key signingKeyID.
+func NewKeySet(signingKeyID string, keys ...*Key) (*KeySet, error) {
+	ks := &KeySet{keys: make(map[string]*Key, len(keys))}
+	for _, key := range keys {
+		ks.keys[key.ID] = key
+	}
+
+	signing, ok := ks.keys[signingKeyID]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrNoSigningKey, signingKeyID)
+	}
+	if signing.Private == nil {
+		return nil, fmt.Errorf("%w: %q", ErrSigningKeyPrivate, signingKeyID)
+	}
+	ks.signing = signing
+
+	return ks, nil
+}
+
+// Generate creates a key for alg with a random ID.
+func Generate(alg string) (*Key, error) {
+	id, err := randomID()
+	if err != nil {
+		return nil, err
+	}
+
+	switch alg {
+	case AlgEdDSA:
+		public, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: id, Algorithm: AlgEdDSA, Public: public, Private: private}, nil
+	case AlgRS256:
+		private, err := rsa.GenerateKey(rand.Reader, 3072)
+		if err != nil {
+			return nil, err
+		}
+		return &Key{ID: id, Algorithm: AlgRS256, Public: &private.PublicKey, Private: private}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKey, alg)
+	}
+}
+
+// ParsePEM parses a private or public key. The algorithm follows from the
+// key type: RS256 for RSA and EdDSA for Ed25519.
+func ParsePEM(id string, data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: PEM block %q", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &Key{ID: id}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Algorithm, key.Public, key.Private = AlgRS256, &k.PublicKey, k
+	case *rsa.PublicKey:
+		key.Algorithm, key.Public = AlgRS256, k
+	case ed25519.PrivateKey:
+		key.Algorithm, key.Public, key.Private = AlgEdDSA, k.Public(), k
+	case ed25519.PublicKey:
+		key.Algorithm, key.Public = AlgEdDSA, k
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, parsed)
+	}
+
+	if rsaKey, ok := key.Public.(*rsa.PublicKey); ok && rsaKey.N.BitLen() < MinRSABits {
+		return nil, fmt.Errorf("RSA key is %d bits, at least %d are required", rsaKey.N.BitLen(), MinRSABits)
+	}
+
+	return key, nil
+}
+
+// MarshalPEM encodes the private key of k as PKCS#8, or its public key as
+// PKIX when k has no private key.
+func (k *Key) MarshalPEM() ([]byte, error) {
+	if k.Private == nil {
+		der, err := x509.MarshalPKIXPublicKey(k.Public)
+		if err != nil {
+			return nil, err
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(k.Private)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+}
+
+// SigningKey returns the key new tokens are signed with.
+func (ks *KeySet) SigningKey() *Key {
+	return ks.signing
+}
+
+// Key returns the key with ID id, or nil.
+func (ks *KeySet) Key(id string) *Key {
+	return ks.keys[id]
+}
+
+// JWK is the public half of a key as published in a key set.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	KeyID     string `json:"kid"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	Y         string `json:"y,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// Set is a JSON Web Key Set document.
+type Set struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys, sorted by ID.
+func (ks *KeySet) JWKS() *Set {
+	set := &Set{Keys: make([]JWK, 0, len(ks.keys))}
+	for _, key := range ks.keys {
+		jwk := JWK{Use: "sig", Algorithm: key.Algorithm, KeyID: key.ID}
+		switch public := key.Public.(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		}
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	slices.SortFunc(set.Keys, func(a, b JWK) int {
+		return strings.Compare(a.KeyID, b.KeyID)
+	})
+	return set
+}
+
+// Key returns the verification key jwk describes. Keys not meant for
+// signatures, or of a type this package does not verify with, fail with
+// ErrUnsupportedKey. ES256 is only read here: keys of our own are RSA or
+// Ed25519.
+func (jwk *JWK) Key() (*Key, error) {
+	if jwk.Use != "" && jwk.Use != "sig" {
+		return nil, fmt.Errorf("%w: use %q", ErrUnsupportedKey, jwk.Use)
+	}
+
+	key := &Key{ID: jwk.KeyID}
+	switch {
+	case jwk.KeyType == "RSA" && (jwk.Algorithm == "" || jwk.Algorithm == AlgRS256):
+		n, err := decodeBigInt(jwk.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(jwk.E)
+		if err != nil {
+			return nil, err
+		}
+		if !e.IsInt64() || e.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		if n.BitLen() < MinRSABits {
+			return nil, fmt.Errorf("RSA key is %d bits, at least %d are required", n.BitLen(), MinRSABits)
+		}
+		key.Algorithm, key.Public = AlgRS256, &rsa.PublicKey{N: n, E: int(e.Int64())}
+	case jwk.KeyType == "EC" && jwk.Curve == "P-256" && (jwk.Algorithm == "" || jwk.Algorithm == AlgES256):
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(jwk.Y)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != 32 || len(y) != 32 {
+			return nil, errors.New("invalid P-256 point")
+		}
+		// An uncompressed point is 0x04 followed by both coordinates.
+		public, err := ecdsa.ParseUncompressedPublicKey(elliptic.P256(), append(append([]byte{4}, x...), y...))
+		if err != nil {
+			return nil, err
+		}
+		key.Algorithm, key.Public = AlgES256, public
+	case jwk.KeyType == "OKP" && jwk.Curve == "Ed25519" && (jwk.Algorithm == "" || jwk.Algorithm == AlgEdDSA):
+		x, err := base64.RawURLEncoding.DecodeString(jwk.X)
+		if err != nil {
+			return nil, err
+		}
+		if len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		key.Algorithm, key.Public = AlgEdDSA, ed25519.PublicKey(x)
+	default:
+		return nil, fmt.Errorf("%w: %s %s %s", ErrUnsupportedKey, jwk.KeyType, jwk.Curve, jwk.Algorithm)
+	}
+
+	return key, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, errors.New("empty integer")
+	}
+	return new(big.Int).SetBytes(b), nil
+}
+
+func randomID() (string, error) {
+	b := make([]byte, 12)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}